@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"time"
 
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/ucloud/grafana-operator/pkg/apis"
 	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	grafanav1beta1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1beta1"
 	"github.com/ucloud/grafana-operator/pkg/controller"
 	"github.com/ucloud/grafana-operator/pkg/controller/common"
 	config2 "github.com/ucloud/grafana-operator/pkg/controller/config"
@@ -34,6 +36,13 @@ var flagImageTag string
 var flagPluginsInitContainerImage string
 var flagPluginsInitContainerTag string
 var flagJsonnetLocation string
+var flagDatasourceHealthCheckInterval time.Duration
+var flagGarbageCollectionMode string
+var flagGarbageCollectionInterval time.Duration
+var flagGrafanaApiRetries int
+var flagGrafanaApiRateLimit float64
+var flagGrafanaApiBurst int
+var flagResyncPeriod time.Duration
 
 var (
 	metricsHost       = "0.0.0.0"
@@ -54,6 +63,13 @@ func init() {
 	flagset.StringVar(&flagPluginsInitContainerImage, "grafana-plugins-init-container-image", "", "Overrides the default Grafana Plugins Init Container image")
 	flagset.StringVar(&flagPluginsInitContainerTag, "grafana-plugins-init-container-tag", "", "Overrides the default Grafana Plugins Init Container tag")
 	flagset.StringVar(&flagJsonnetLocation, "jsonnet-location", "", "Overrides the base path of the jsonnet libraries")
+	flagset.DurationVar(&flagDatasourceHealthCheckInterval, "datasource-health-check-interval", config2.DatasourceHealthCheckInterval, "Interval of the datasource health checks")
+	flagset.StringVar(&flagGarbageCollectionMode, "garbage-collection", config2.GarbageCollectionDryRun, "Handling of operator managed dashboards and datasources without a matching custom resource: enabled, dry-run or disabled")
+	flagset.DurationVar(&flagGarbageCollectionInterval, "garbage-collection-interval", config2.GarbageCollectionInterval, "Interval of the garbage collection in every grafana instance")
+	flagset.IntVar(&flagGrafanaApiRetries, "grafana-api-retries", config2.GrafanaApiRetries, "Retries of Grafana API requests failing with connection errors or 5xx responses")
+	flagset.Float64Var(&flagGrafanaApiRateLimit, "grafana-api-rate-limit", 0, "Maximum requests per second to every Grafana instance, unlimited if 0")
+	flagset.IntVar(&flagGrafanaApiBurst, "grafana-api-burst", config2.GrafanaApiBurst, "Burst of requests to every Grafana instance allowed by the rate limit")
+	flagset.DurationVar(&flagResyncPeriod, "resync-period", config2.ResyncPeriod, "Interval after which dashboards and datasources are synced again without any change")
 	flagset.Parse(os.Args[1:])
 }
 
@@ -78,9 +94,16 @@ func main() {
 	controllerConfig.AddConfigItem(config2.ConfigGrafanaImageTag, flagImageTag)
 	controllerConfig.AddConfigItem(config2.ConfigPluginsInitContainerImage, flagPluginsInitContainerImage)
 	controllerConfig.AddConfigItem(config2.ConfigPluginsInitContainerTag, flagPluginsInitContainerTag)
-	controllerConfig.AddConfigItem(config2.ConfigOperatorNamespace, namespace)
+	controllerConfig.AddConfigItem(config2.ConfigOperatorNamespace, getOperatorNamespace(namespace))
 	controllerConfig.AddConfigItem(config2.ConfigDashboardLabelSelector, "")
 	controllerConfig.AddConfigItem(config2.ConfigJsonnetBasePath, flagJsonnetLocation)
+	controllerConfig.AddConfigItem(config2.ConfigDatasourceHealthCheckInterval, flagDatasourceHealthCheckInterval)
+	controllerConfig.AddConfigItem(config2.ConfigGarbageCollectionMode, flagGarbageCollectionMode)
+	controllerConfig.AddConfigItem(config2.ConfigGarbageCollectionInterval, flagGarbageCollectionInterval)
+	controllerConfig.AddConfigItem(config2.ConfigGrafanaApiRetries, flagGrafanaApiRetries)
+	controllerConfig.AddConfigItem(config2.ConfigGrafanaApiRateLimit, flagGrafanaApiRateLimit)
+	controllerConfig.AddConfigItem(config2.ConfigGrafanaApiBurst, flagGrafanaApiBurst)
+	controllerConfig.AddConfigItem(config2.ConfigResyncPeriod, flagResyncPeriod)
 
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
@@ -163,6 +186,11 @@ func startWebHook(mgr manager.Manager) {
 	ws := mgr.GetWebhookServer()
 	ws.CertDir = "/etc/webhook/certs"
 	ws.Port = 7443
+
+	if err := (&grafanav1alpha1.Grafana{}).SetupWebhookWithManager(mgr); err != nil {
+		log.Error(err, "unable to create webHook", "webHook", "Grafana")
+		os.Exit(1)
+	}
 	if err := (&grafanav1alpha1.GrafanaDashboard{}).SetupWebhookWithManager(mgr); err != nil {
 		log.Error(err, "unable to create webHook", "webHook", "GrafanaDashboard")
 		os.Exit(1)
@@ -171,6 +199,12 @@ func startWebHook(mgr manager.Manager) {
 		log.Error(err, "unable to create webHook", "webHook", "GrafanaDataSource")
 		os.Exit(1)
 	}
+
+	// Conversion between the served API versions, only datasources are served as v1beta1
+	if err := (&grafanav1beta1.GrafanaDataSource{}).SetupWebhookWithManager(mgr); err != nil {
+		log.Error(err, "unable to create conversion webHook", "webHook", "GrafanaDataSource")
+		os.Exit(1)
+	}
 }
 
 //func serveProfiling() {
@@ -185,3 +219,13 @@ func startWebHook(mgr manager.Manager) {
 //		}
 //	}, 5*time.Second, wait.NeverStop)
 //}
+
+// The operator namespace is used to allow API access to Grafana through network policies.
+// Fall back to the watch namespace when running locally
+func getOperatorNamespace(watchNamespace string) string {
+	operatorNamespace, err := k8sutil.GetOperatorNamespace()
+	if err != nil {
+		return watchNamespace
+	}
+	return operatorNamespace
+}
@@ -0,0 +1,130 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// The resource is synced and all its dependencies are ready
+	ConditionReady = "Ready"
+	// The last reconciliation of the resource succeeded
+	ConditionSynced = "Synced"
+	// The grafana deployment has all its replicas available
+	ConditionDeploymentAvailable = "DeploymentAvailable"
+	// The ingress or route of grafana is admitted. Only set if the operator
+	// accesses grafana through it
+	ConditionIngressReady = "IngressReady"
+	// All requested plugins are installed
+	ConditionPluginsInstalled = "PluginsInstalled"
+	// The datasource passed the health check of grafana
+	ConditionHealthy = "Healthy"
+	// The permissions of the dashboard are applied in all grafana instances.
+	// Only set if the dashboard has permissions
+	ConditionPermissionsApplied = "PermissionsApplied"
+)
+
+const (
+	ReasonReconcileSucceeded = "ReconcileSucceeded"
+	ReasonReconcileFailed    = "ReconcileFailed"
+)
+
+// Condition describes one aspect of the observed state of a resource
+type Condition struct {
+	Type               string             `json:"type"`
+	Status             v1.ConditionStatus `json:"status"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Reason             string             `json:"reason,omitempty"`
+	Message            string             `json:"message,omitempty"`
+	LastTransitionTime metav1.Time        `json:"lastTransitionTime,omitempty"`
+}
+
+// SetCondition adds the condition or replaces the existing condition of the same
+// type. The transition time is kept as long as the status does not change
+func SetCondition(conditions *[]Condition, condition Condition) {
+	if condition.LastTransitionTime.IsZero() {
+		condition.LastTransitionTime = metav1.Now()
+	}
+
+	for i := range *conditions {
+		existing := &(*conditions)[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+		*existing = condition
+		return
+	}
+
+	*conditions = append(*conditions, condition)
+}
+
+// RemoveCondition removes the condition of the given type if it exists
+func RemoveCondition(conditions *[]Condition, conditionType string) {
+	for i := range *conditions {
+		if (*conditions)[i].Type == conditionType {
+			*conditions = append((*conditions)[:i], (*conditions)[i+1:]...)
+			return
+		}
+	}
+}
+
+// FindCondition returns the condition of the given type or nil
+func FindCondition(conditions []Condition, conditionType string) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetSyncedCondition sets the Synced condition from the result of a reconciliation
+func SetSyncedCondition(conditions *[]Condition, generation int64, err error) {
+	condition := Condition{
+		Type:               ConditionSynced,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             ReasonReconcileSucceeded,
+	}
+
+	if err != nil {
+		condition.Status = v1.ConditionFalse
+		condition.Reason = ReasonReconcileFailed
+		condition.Message = err.Error()
+	}
+
+	SetCondition(conditions, condition)
+}
+
+// SetReadyCondition sets the Ready condition, which is only true if all conditions
+// of the given types exist and are true
+func SetReadyCondition(conditions *[]Condition, generation int64, conditionTypes ...string) {
+	ready := Condition{
+		Type:               ConditionReady,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "Ready",
+	}
+
+	for _, conditionType := range conditionTypes {
+		condition := FindCondition(*conditions, conditionType)
+		if condition == nil || condition.Status == v1.ConditionUnknown {
+			ready.Status = v1.ConditionUnknown
+			ready.Reason = conditionType + "Unknown"
+			break
+		}
+
+		if condition.Status != v1.ConditionTrue {
+			ready.Status = v1.ConditionFalse
+			ready.Reason = "Not" + conditionType
+			ready.Message = condition.Message
+			break
+		}
+	}
+
+	SetCondition(conditions, ready)
+}
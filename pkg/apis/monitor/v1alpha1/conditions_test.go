@@ -0,0 +1,46 @@
+package v1alpha1
+
+import (
+	"errors"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetConditionKeepsTransitionTime(t *testing.T) {
+	past := metav1.Unix(1000, 0)
+	conditions := []Condition{{Type: ConditionSynced, Status: v1.ConditionTrue, LastTransitionTime: past}}
+
+	SetSyncedCondition(&conditions, 2, nil)
+	if len(conditions) != 1 || !conditions[0].LastTransitionTime.Equal(&past) || conditions[0].ObservedGeneration != 2 {
+		t.Errorf("unexpected conditions %+v", conditions)
+	}
+
+	SetSyncedCondition(&conditions, 3, errors.New("failed"))
+	if conditions[0].Status != v1.ConditionFalse || conditions[0].LastTransitionTime.Equal(&past) || conditions[0].Message != "failed" {
+		t.Errorf("unexpected conditions %+v", conditions)
+	}
+}
+
+func TestSetReadyCondition(t *testing.T) {
+	var conditions []Condition
+	SetSyncedCondition(&conditions, 1, nil)
+
+	SetReadyCondition(&conditions, 1, ConditionSynced, ConditionDeploymentAvailable)
+	if ready := FindCondition(conditions, ConditionReady); ready.Status != v1.ConditionUnknown {
+		t.Errorf("expected unknown readiness, got %+v", ready)
+	}
+
+	SetCondition(&conditions, Condition{Type: ConditionDeploymentAvailable, Status: v1.ConditionFalse, Message: "deployment not ready"})
+	SetReadyCondition(&conditions, 1, ConditionSynced, ConditionDeploymentAvailable)
+	if ready := FindCondition(conditions, ConditionReady); ready.Status != v1.ConditionFalse || ready.Message != "deployment not ready" {
+		t.Errorf("expected not ready, got %+v", ready)
+	}
+
+	SetCondition(&conditions, Condition{Type: ConditionDeploymentAvailable, Status: v1.ConditionTrue})
+	SetReadyCondition(&conditions, 1, ConditionSynced, ConditionDeploymentAvailable)
+	if ready := FindCondition(conditions, ConditionReady); ready.Status != v1.ConditionTrue {
+		t.Errorf("expected ready, got %+v", ready)
+	}
+}
@@ -0,0 +1,10 @@
+package v1alpha1
+
+// v1alpha1 is the storage version of all resources and the hub that other
+// versions are converted from and to
+
+func (*Grafana) Hub() {}
+
+func (*GrafanaDashboard) Hub() {}
+
+func (*GrafanaDataSource) Hub() {}
@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	v12 "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -18,22 +19,62 @@ var (
 // GrafanaSpec defines the desired state of Grafana
 // +k8s:openapi-gen=true
 type GrafanaSpec struct {
-	Config                  GrafanaConfig            `json:"config"`
-	Containers              []v1.Container           `json:"containers,omitempty"`
-	DashboardLabelSelector  []*metav1.LabelSelector  `json:"dashboardLabelSelector,omitempty"`
-	DatasourceLabelSelector []*metav1.LabelSelector  `json:"datasourceLabelSelector,omitempty"`
-	Ingress                 *GrafanaIngress          `json:"ingress,omitempty"`
-	InitResources           *v1.ResourceRequirements `json:"initResources,omitempty"`
-	Secrets                 []string                 `json:"secrets,omitempty"`
-	ConfigMaps              []string                 `json:"configMaps,omitempty"`
-	Service                 *GrafanaService          `json:"service,omitempty"`
-	Deployment              *GrafanaDeployment       `json:"deployment,omitempty"`
-	Resources               *v1.ResourceRequirements `json:"resources,omitempty"`
-	ServiceAccount          *GrafanaServiceAccount   `json:"serviceAccount,omitempty"`
-	Client                  *GrafanaClient           `json:"client,omitempty"`
-	DataStorage             *GrafanaDataStorage      `json:"dataStorage,omitempty"`
-	Jsonnet                 *JsonnetConfig           `json:"jsonnet,omitempty"`
-}
+	Config                  GrafanaConfig                `json:"config"`
+	Containers              []v1.Container               `json:"containers,omitempty"`
+	DashboardLabelSelector  []*metav1.LabelSelector      `json:"dashboardLabelSelector,omitempty"`
+	DatasourceLabelSelector []*metav1.LabelSelector      `json:"datasourceLabelSelector,omitempty"`
+	Ingress                 *GrafanaIngress              `json:"ingress,omitempty"`
+	InitResources           *v1.ResourceRequirements     `json:"initResources,omitempty"`
+	Secrets                 []string                     `json:"secrets,omitempty"`
+	ConfigMaps              []string                     `json:"configMaps,omitempty"`
+	Service                 *GrafanaService              `json:"service,omitempty"`
+	Deployment              *GrafanaDeployment           `json:"deployment,omitempty"`
+	Resources               *v1.ResourceRequirements     `json:"resources,omitempty"`
+	ServiceAccount          *GrafanaServiceAccountConfig `json:"serviceAccount,omitempty"`
+	Client                  *GrafanaClient               `json:"client,omitempty"`
+	DataStorage             *GrafanaDataStorage          `json:"dataStorage,omitempty"`
+	Jsonnet                 *JsonnetConfig               `json:"jsonnet,omitempty"`
+	NetworkPolicy           *GrafanaNetworkPolicy        `json:"networkPolicy,omitempty"`
+	ImageRenderer           *GrafanaImageRenderer        `json:"imageRenderer,omitempty"`
+	// Selects the GrafanaOrganization resources created in this instance
+	OrganizationLabelSelector []*metav1.LabelSelector `json:"organizationLabelSelector,omitempty"`
+	// How the dashboards and datasources of different namespaces are separated
+	Tenancy GrafanaTenancy `json:"tenancy,omitempty"`
+	// Selects the namespaces other than the namespace of the instance whose
+	// dashboards and datasources are synced with namespaceOrgs tenancy. Only the
+	// namespace of the instance is selected if unset, an empty selector selects
+	// all namespaces
+	TenantNamespaceSelector *metav1.LabelSelector `json:"tenantNamespaceSelector,omitempty"`
+	// Select the GrafanaUser and GrafanaTeam resources created in this instance
+	UserLabelSelector []*metav1.LabelSelector `json:"userLabelSelector,omitempty"`
+	TeamLabelSelector []*metav1.LabelSelector `json:"teamLabelSelector,omitempty"`
+	// Select the GrafanaServiceAccount resources created in this instance
+	ServiceAccountLabelSelector []*metav1.LabelSelector `json:"serviceAccountLabelSelector,omitempty"`
+}
+
+type GrafanaTenancy string
+
+const (
+	// Dashboards and datasources are synced from the namespace of grafana
+	// into the organizations named in the resources
+	TenancyShared GrafanaTenancy = ""
+	// Dashboards and datasources are synced from the namespaces selected by
+	// the tenant namespace selector, every namespace gets an organization
+	// named after it
+	TenancyNamespaceOrgs GrafanaTenancy = "namespaceOrgs"
+)
+
+// Annotations of a namespace granting the membership in the organization of the
+// namespace, see TenancyNamespaceOrgs. Both hold a comma separated list of names
+// with an optional role, e.g. "alice=Editor, bob". The role defaults to Viewer
+const (
+	TenantUsersAnnotation = "monitor.kun/grafana-users"
+	TenantTeamsAnnotation = "monitor.kun/grafana-teams"
+)
+
+// Changing the value of this annotation of a Grafana rotates the generated
+// password of the admin user. Ignored if the password is set in the spec
+const RotateAdminPasswordAnnotation = "monitor.kun/rotate-admin-password"
 
 type JsonnetConfig struct {
 	LibraryLabelSelector *metav1.LabelSelector `json:"libraryLabelSelector,omitempty"`
@@ -62,7 +103,7 @@ type GrafanaDataStorage struct {
 	Class       string                          `json:"class"`
 }
 
-type GrafanaServiceAccount struct {
+type GrafanaServiceAccountConfig struct {
 	Annotations      map[string]string         `json:"annotations,omitempty"`
 	Labels           map[string]string         `json:"labels,omitempty"`
 	ImagePullSecrets []v1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
@@ -94,6 +135,28 @@ type GrafanaIngress struct {
 	Termination   v12.TLSTerminationType `json:"termination,omitempty"`
 }
 
+// GrafanaNetworkPolicy provides a means to restrict the traffic of the grafana pods
+type GrafanaNetworkPolicy struct {
+	Annotations         map[string]string                `json:"annotations,omitempty"`
+	Labels              map[string]string                `json:"labels,omitempty"`
+	Enabled             bool                             `json:"enabled,omitempty"`
+	IngressController   []networkingv1.NetworkPolicyPeer `json:"ingressController,omitempty"`
+	IngressPeers        []networkingv1.NetworkPolicyPeer `json:"ingressPeers,omitempty"`
+	DatasourcePeers     []networkingv1.NetworkPolicyPeer `json:"datasourcePeers,omitempty"`
+	PluginDownloadPeers []networkingv1.NetworkPolicyPeer `json:"pluginDownloadPeers,omitempty"`
+}
+
+// GrafanaImageRenderer provides a means to deploy the grafana image renderer
+// next to the grafana instance
+type GrafanaImageRenderer struct {
+	Annotations map[string]string        `json:"annotations,omitempty"`
+	Labels      map[string]string        `json:"labels,omitempty"`
+	Enabled     bool                     `json:"enabled,omitempty"`
+	Image       string                   `json:"image,omitempty"`
+	Replicas    int32                    `json:"replicas,omitempty"`
+	Resources   *v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
 // GrafanaConfig is the configuration for grafana
 type GrafanaConfig struct {
 	Paths                         *GrafanaConfigPaths                         `json:"paths,omitempty" ini:"paths,omitempty"`
@@ -127,6 +190,11 @@ type GrafanaConfig struct {
 	Alerting                      *GrafanaConfigAlerting                      `json:"alerting,omitempty" ini:"alerting,omitempty"`
 	Panels                        *GrafanaConfigPanels                        `json:"panels,omitempty" ini:"panels,omitempty"`
 	Plugins                       *GrafanaConfigPlugins                       `json:"plugins,omitempty" ini:"plugins,omitempty"`
+	Rendering                     *GrafanaConfigRendering                     `json:"rendering,omitempty" ini:"rendering,omitempty"`
+
+	// Extra allows setting sections and keys that are not covered by the typed
+	// fields above. Keys must not conflict with typed fields
+	Extra map[string]map[string]string `json:"extra,omitempty" ini:"-"`
 }
 
 type GrafanaConfigPaths struct {
@@ -150,46 +218,49 @@ type GrafanaConfigServer struct {
 }
 
 type GrafanaConfigDatabase struct {
-	Url             string `json:"url,omitempty" ini:"url,omitempty"`
-	Type            string `json:"type,omitempty" ini:"type,omitempty"`
-	Path            string `json:"path,omitempty" ini:"path,omitempty"`
-	Host            string `json:"host,omitempty" ini:"host,omitempty"`
-	Name            string `json:"name,omitempty" ini:"name,omitempty"`
-	User            string `json:"user,omitempty" ini:"user,omitempty"`
-	Password        string `json:"password,omitempty" ini:"password,omitempty"`
-	SslMode         string `json:"ssl_mode,omitempty" ini:"ssl_mode,omitempty"`
-	CaCertPath      string `json:"ca_cert_path,omitempty" ini:"ca_cert_path,omitempty"`
-	ClientKeyPath   string `json:"client_key_path,omitempty" ini:"client_key_path,omitempty"`
-	ClientCertPath  string `json:"client_cert_path,omitempty" ini:"client_cert_path,omitempty"`
-	ServerCertName  string `json:"server_cert_name,omitempty" ini:"server_cert_name,omitempty"`
-	MaxIdleConn     *int   `json:"max_idle_conn,omitempty" ini:"max_idle_conn,omitempty"`
-	MaxOpenConn     *int   `json:"max_open_conn,omitempty" ini:"max_open_conn,omitempty"`
-	ConnMaxLifetime *int   `json:"conn_max_lifetime,omitempty" ini:"conn_max_lifetime,omitempty"`
-	LogQueries      *bool  `json:"log_queries,omitempty" ini:"log_queries"`
-	CacheMode       string `json:"cache_mode,omitempty" ini:"cache_mode,omitempty"`
+	Url             string                `json:"url,omitempty" ini:"url,omitempty"`
+	Type            string                `json:"type,omitempty" ini:"type,omitempty"`
+	Path            string                `json:"path,omitempty" ini:"path,omitempty"`
+	Host            string                `json:"host,omitempty" ini:"host,omitempty"`
+	Name            string                `json:"name,omitempty" ini:"name,omitempty"`
+	User            string                `json:"user,omitempty" ini:"user,omitempty"`
+	Password        string                `json:"password,omitempty" ini:"password,omitempty"`
+	PasswordRef     *v1.SecretKeySelector `json:"password_ref,omitempty" ini:"-"`
+	SslMode         string                `json:"ssl_mode,omitempty" ini:"ssl_mode,omitempty"`
+	CaCertPath      string                `json:"ca_cert_path,omitempty" ini:"ca_cert_path,omitempty"`
+	ClientKeyPath   string                `json:"client_key_path,omitempty" ini:"client_key_path,omitempty"`
+	ClientCertPath  string                `json:"client_cert_path,omitempty" ini:"client_cert_path,omitempty"`
+	ServerCertName  string                `json:"server_cert_name,omitempty" ini:"server_cert_name,omitempty"`
+	MaxIdleConn     *int                  `json:"max_idle_conn,omitempty" ini:"max_idle_conn,omitempty"`
+	MaxOpenConn     *int                  `json:"max_open_conn,omitempty" ini:"max_open_conn,omitempty"`
+	ConnMaxLifetime *int                  `json:"conn_max_lifetime,omitempty" ini:"conn_max_lifetime,omitempty"`
+	LogQueries      *bool                 `json:"log_queries,omitempty" ini:"log_queries"`
+	CacheMode       string                `json:"cache_mode,omitempty" ini:"cache_mode,omitempty"`
 }
 
 type GrafanaConfigRemoteCache struct {
-	Type    string `json:"type,omitempty" ini:"type,omitempty"`
-	ConnStr string `json:"connstr,omitempty" ini:"connstr,omitempty"`
+	Type       string                `json:"type,omitempty" ini:"type,omitempty"`
+	ConnStr    string                `json:"connstr,omitempty" ini:"connstr,omitempty"`
+	ConnStrRef *v1.SecretKeySelector `json:"connstr_ref,omitempty" ini:"-"`
 }
 
 type GrafanaConfigSecurity struct {
-	AdminUser                            string `json:"admin_user,omitempty" ini:"admin_user,omitempty"`
-	AdminPassword                        string `json:"admin_password,omitempty" ini:"admin_password,omitempty"`
-	LoginRememberDays                    *int   `json:"login_remember_days,omitempty" ini:"login_remember_days,omitempty"`
-	SecretKey                            string `json:"secret_key,omitempty" ini:"secret_key,omitempty"`
-	DisableGravatar                      *bool  `json:"disable_gravatar,omitempty" ini:"disable_gravatar"`
-	DataSourceProxyWhitelist             string `json:"data_source_proxy_whitelist,omitempty" ini:"data_source_proxy_whitelist,omitempty"`
-	CookieSecure                         *bool  `json:"cookie_secure,omitempty" ini:"cookie_secure"`
-	CookieSamesite                       string `json:"cookie_samesite,omitempty" ini:"cookie_samesite,omitempty"`
-	AllowEmbedding                       *bool  `json:"allow_embedding,omitempty" ini:"allow_embedding"`
-	StrictTransportSecurity              *bool  `json:"strict_transport_security,omitempty" ini:"strict_transport_security"`
-	StrictTransportSecurityMaxAgeSeconds *int   `json:"strict_transport_security_max_age_seconds,omitempty" ini:"strict_transport_security_max_age_seconds,omitempty"`
-	StrictTransportSecurityPreload       *bool  `json:"strict_transport_security_preload,omitempty" ini:"strict_transport_security_preload"`
-	StrictTransportSecuritySubdomains    *bool  `json:"strict_transport_security_subdomains,omitempty" ini:"strict_transport_security_subdomains"`
-	XContentTypeOptions                  *bool  `json:"x_content_type_options,omitempty" ini:"x_content_type_options"`
-	XXssProtection                       *bool  `json:"x_xss_protection,omitempty" ini:"x_xss_protection"`
+	AdminUser                            string                `json:"admin_user,omitempty" ini:"admin_user,omitempty"`
+	AdminPassword                        string                `json:"admin_password,omitempty" ini:"admin_password,omitempty"`
+	LoginRememberDays                    *int                  `json:"login_remember_days,omitempty" ini:"login_remember_days,omitempty"`
+	SecretKey                            string                `json:"secret_key,omitempty" ini:"secret_key,omitempty"`
+	SecretKeyRef                         *v1.SecretKeySelector `json:"secret_key_ref,omitempty" ini:"-"`
+	DisableGravatar                      *bool                 `json:"disable_gravatar,omitempty" ini:"disable_gravatar"`
+	DataSourceProxyWhitelist             string                `json:"data_source_proxy_whitelist,omitempty" ini:"data_source_proxy_whitelist,omitempty"`
+	CookieSecure                         *bool                 `json:"cookie_secure,omitempty" ini:"cookie_secure"`
+	CookieSamesite                       string                `json:"cookie_samesite,omitempty" ini:"cookie_samesite,omitempty"`
+	AllowEmbedding                       *bool                 `json:"allow_embedding,omitempty" ini:"allow_embedding"`
+	StrictTransportSecurity              *bool                 `json:"strict_transport_security,omitempty" ini:"strict_transport_security"`
+	StrictTransportSecurityMaxAgeSeconds *int                  `json:"strict_transport_security_max_age_seconds,omitempty" ini:"strict_transport_security_max_age_seconds,omitempty"`
+	StrictTransportSecurityPreload       *bool                 `json:"strict_transport_security_preload,omitempty" ini:"strict_transport_security_preload"`
+	StrictTransportSecuritySubdomains    *bool                 `json:"strict_transport_security_subdomains,omitempty" ini:"strict_transport_security_subdomains"`
+	XContentTypeOptions                  *bool                 `json:"x_content_type_options,omitempty" ini:"x_content_type_options"`
+	XXssProtection                       *bool                 `json:"x_xss_protection,omitempty" ini:"x_xss_protection"`
 }
 
 type GrafanaConfigUsers struct {
@@ -226,53 +297,57 @@ type GrafanaConfigAuthAnonymous struct {
 }
 
 type GrafanaConfigAuthGoogle struct {
-	Enabled        *bool  `json:"enabled,omitempty" ini:"enabled"`
-	ClientId       string `json:"client_id,omitempty" ini:"client_id,omitempty"`
-	ClientSecret   string `json:"client_secret,omitempty" ini:"client_secret,omitempty"`
-	Scopes         string `json:"scopes,omitempty" ini:"scopes,omitempty"`
-	AuthUrl        string `json:"auth_url,omitempty" ini:"auth_url,omitempty"`
-	TokenUrl       string `json:"token_url,omitempty" ini:"token_url,omitempty"`
-	AllowedDomains string `json:"allowed_domains,omitempty" ini:"allowed_domains,omitempty"`
-	AllowSignUp    *bool  `json:"allow_sign_up,omitempty" ini:"allow_sign_up"`
+	Enabled         *bool                 `json:"enabled,omitempty" ini:"enabled"`
+	ClientId        string                `json:"client_id,omitempty" ini:"client_id,omitempty"`
+	ClientSecret    string                `json:"client_secret,omitempty" ini:"client_secret,omitempty"`
+	ClientSecretRef *v1.SecretKeySelector `json:"client_secret_ref,omitempty" ini:"-"`
+	Scopes          string                `json:"scopes,omitempty" ini:"scopes,omitempty"`
+	AuthUrl         string                `json:"auth_url,omitempty" ini:"auth_url,omitempty"`
+	TokenUrl        string                `json:"token_url,omitempty" ini:"token_url,omitempty"`
+	AllowedDomains  string                `json:"allowed_domains,omitempty" ini:"allowed_domains,omitempty"`
+	AllowSignUp     *bool                 `json:"allow_sign_up,omitempty" ini:"allow_sign_up"`
 }
 
 type GrafanaConfigAuthGithub struct {
-	Enabled              *bool  `json:"enabled,omitempty" ini:"enabled"`
-	AllowSignUp          *bool  `json:"allow_sign_up,omitempty" ini:"allow_sign_up"`
-	ClientId             string `json:"client_id,omitempty" ini:"client_id,omitempty"`
-	ClientSecret         string `json:"client_secret,omitempty" ini:"client_secret,omitempty"`
-	Scopes               string `json:"scopes,omitempty" ini:"scopes,omitempty"`
-	AuthUrl              string `json:"auth_url,omitempty" ini:"auth_url,omitempty"`
-	TokenUrl             string `json:"token_url,omitempty" ini:"token_url,omitempty"`
-	ApiUrl               string `json:"api_url,omitempty" ini:"api_url,omitempty"`
-	TeamIds              string `json:"team_ids,omitempty" ini:"team_ids,omitempty"`
-	AllowedOrganizations string `json:"allowed_organizations,omitempty" ini:"allowed_organizations,omitempty"`
+	Enabled              *bool                 `json:"enabled,omitempty" ini:"enabled"`
+	AllowSignUp          *bool                 `json:"allow_sign_up,omitempty" ini:"allow_sign_up"`
+	ClientId             string                `json:"client_id,omitempty" ini:"client_id,omitempty"`
+	ClientSecret         string                `json:"client_secret,omitempty" ini:"client_secret,omitempty"`
+	ClientSecretRef      *v1.SecretKeySelector `json:"client_secret_ref,omitempty" ini:"-"`
+	Scopes               string                `json:"scopes,omitempty" ini:"scopes,omitempty"`
+	AuthUrl              string                `json:"auth_url,omitempty" ini:"auth_url,omitempty"`
+	TokenUrl             string                `json:"token_url,omitempty" ini:"token_url,omitempty"`
+	ApiUrl               string                `json:"api_url,omitempty" ini:"api_url,omitempty"`
+	TeamIds              string                `json:"team_ids,omitempty" ini:"team_ids,omitempty"`
+	AllowedOrganizations string                `json:"allowed_organizations,omitempty" ini:"allowed_organizations,omitempty"`
 }
 
 type GrafanaConfigAuthGitlab struct {
-	Enabled       *bool  `json:"enabled,omitempty" ini:"enabled"`
-	AllowSignUp   *bool  `json:"allow_sign_up,omitempty" ini:"allow_sign_up"`
-	ClientId      string `json:"client_id,omitempty" ini:"client_id,omitempty"`
-	ClientSecret  string `json:"client_secret,omitempty" ini:"client_secret,omitempty"`
-	Scopes        string `json:"scopes,omitempty" ini:"scopes,omitempty"`
-	AuthUrl       string `json:"auth_url,omitempty" ini:"auth_url,omitempty"`
-	TokenUrl      string `json:"token_url,omitempty" ini:"token_url,omitempty"`
-	ApiUrl        string `json:"api_url,omitempty" ini:"api_url,omitempty"`
-	AllowedGroups string `json:"allowed_groups,omitempty" ini:"allowed_groups,omitempty"`
+	Enabled         *bool                 `json:"enabled,omitempty" ini:"enabled"`
+	AllowSignUp     *bool                 `json:"allow_sign_up,omitempty" ini:"allow_sign_up"`
+	ClientId        string                `json:"client_id,omitempty" ini:"client_id,omitempty"`
+	ClientSecret    string                `json:"client_secret,omitempty" ini:"client_secret,omitempty"`
+	ClientSecretRef *v1.SecretKeySelector `json:"client_secret_ref,omitempty" ini:"-"`
+	Scopes          string                `json:"scopes,omitempty" ini:"scopes,omitempty"`
+	AuthUrl         string                `json:"auth_url,omitempty" ini:"auth_url,omitempty"`
+	TokenUrl        string                `json:"token_url,omitempty" ini:"token_url,omitempty"`
+	ApiUrl          string                `json:"api_url,omitempty" ini:"api_url,omitempty"`
+	AllowedGroups   string                `json:"allowed_groups,omitempty" ini:"allowed_groups,omitempty"`
 }
 
 type GrafanaConfigAuthGenericOauth struct {
-	Enabled            *bool  `json:"enabled,omitempty" ini:"enabled"`
-	AllowSignUp        *bool  `json:"allow_sign_up,omitempty" ini:"allow_sign_up"`
-	ClientId           string `json:"client_id,omitempty" ini:"client_id,omitempty"`
-	ClientSecret       string `json:"client_secret,omitempty" ini:"client_secret,omitempty"`
-	Scopes             string `json:"scopes,omitempty" ini:"scopes,omitempty"`
-	AuthUrl            string `json:"auth_url,omitempty" ini:"auth_url,omitempty"`
-	TokenUrl           string `json:"token_url,omitempty" ini:"token_url,omitempty"`
-	ApiUrl             string `json:"api_url,omitempty" ini:"api_url,omitempty"`
-	AllowedDomains     string `json:"allowed_domains,omitempty" ini:"allowed_domains,omitempty"`
-	RoleAttributePath  string `json:"role_attribute_path,omitempty" ini:"role_attribute_path,omitempty"`
-	EmailAttributePath string `json:"email_attribute_path,omitempty" ini:"email_attribute_path,omitempty"`
+	Enabled            *bool                 `json:"enabled,omitempty" ini:"enabled"`
+	AllowSignUp        *bool                 `json:"allow_sign_up,omitempty" ini:"allow_sign_up"`
+	ClientId           string                `json:"client_id,omitempty" ini:"client_id,omitempty"`
+	ClientSecret       string                `json:"client_secret,omitempty" ini:"client_secret,omitempty"`
+	ClientSecretRef    *v1.SecretKeySelector `json:"client_secret_ref,omitempty" ini:"-"`
+	Scopes             string                `json:"scopes,omitempty" ini:"scopes,omitempty"`
+	AuthUrl            string                `json:"auth_url,omitempty" ini:"auth_url,omitempty"`
+	TokenUrl           string                `json:"token_url,omitempty" ini:"token_url,omitempty"`
+	ApiUrl             string                `json:"api_url,omitempty" ini:"api_url,omitempty"`
+	AllowedDomains     string                `json:"allowed_domains,omitempty" ini:"allowed_domains,omitempty"`
+	RoleAttributePath  string                `json:"role_attribute_path,omitempty" ini:"role_attribute_path,omitempty"`
+	EmailAttributePath string                `json:"email_attribute_path,omitempty" ini:"email_attribute_path,omitempty"`
 }
 
 type GrafanaConfigAuthLdap struct {
@@ -309,16 +384,17 @@ type GrafanaConfigDashboards struct {
 }
 
 type GrafanaConfigSmtp struct {
-	Enabled      *bool  `json:"enabled,omitempty" ini:"enabled"`
-	Host         string `json:"host,omitempty" ini:"host,omitempty"`
-	User         string `json:"user,omitempty" ini:"user,omitempty"`
-	Password     string `json:"password,omitempty" ini:"password,omitempty"`
-	CertFile     string `json:"cert_file,omitempty" ini:"cert_file,omitempty"`
-	KeyFile      string `json:"key_file,omitempty" ini:"key_file,omitempty"`
-	SkipVerify   *bool  `json:"skip_verify,omitempty" ini:"skip_verify"`
-	FromAddress  string `json:"from_address,omitempty" ini:"from_address,omitempty"`
-	FromName     string `json:"from_name,omitempty" ini:"from_name,omitempty"`
-	EhloIdentity string `json:"ehlo_identity,omitempty" ini:"ehlo_identity,omitempty"`
+	Enabled      *bool                 `json:"enabled,omitempty" ini:"enabled"`
+	Host         string                `json:"host,omitempty" ini:"host,omitempty"`
+	User         string                `json:"user,omitempty" ini:"user,omitempty"`
+	Password     string                `json:"password,omitempty" ini:"password,omitempty"`
+	PasswordRef  *v1.SecretKeySelector `json:"password_ref,omitempty" ini:"-"`
+	CertFile     string                `json:"cert_file,omitempty" ini:"cert_file,omitempty"`
+	KeyFile      string                `json:"key_file,omitempty" ini:"key_file,omitempty"`
+	SkipVerify   *bool                 `json:"skip_verify,omitempty" ini:"skip_verify"`
+	FromAddress  string                `json:"from_address,omitempty" ini:"from_address,omitempty"`
+	FromName     string                `json:"from_name,omitempty" ini:"from_name,omitempty"`
+	EhloIdentity string                `json:"ehlo_identity,omitempty" ini:"ehlo_identity,omitempty"`
 }
 
 type GrafanaConfigLog struct {
@@ -328,10 +404,11 @@ type GrafanaConfigLog struct {
 }
 
 type GrafanaConfigMetrics struct {
-	Enabled           *bool  `json:"enabled,omitempty" ini:"enabled"`
-	BasicAuthUsername string `json:"basic_auth_username,omitempty" ini:"basic_auth_username,omitempty"`
-	BasicAuthPassword string `json:"basic_auth_password,omitempty" ini:"basic_auth_password,omitempty"`
-	IntervalSeconds   *int   `json:"interval_seconds,omitempty" ini:"interval_seconds,omitempty"`
+	Enabled              *bool                 `json:"enabled,omitempty" ini:"enabled"`
+	BasicAuthUsername    string                `json:"basic_auth_username,omitempty" ini:"basic_auth_username,omitempty"`
+	BasicAuthPassword    string                `json:"basic_auth_password,omitempty" ini:"basic_auth_password,omitempty"`
+	BasicAuthPasswordRef *v1.SecretKeySelector `json:"basic_auth_password_ref,omitempty" ini:"-"`
+	IntervalSeconds      *int                  `json:"interval_seconds,omitempty" ini:"interval_seconds,omitempty"`
 }
 
 type GrafanaConfigMetricsGraphite struct {
@@ -351,19 +428,22 @@ type GrafanaConfigExternalImageStorage struct {
 }
 
 type GrafanaConfigExternalImageStorageS3 struct {
-	Bucket    string `json:"bucket,omitempty" ini:"bucket,omitempty"`
-	Region    string `json:"region,omitempty" ini:"region,omitempty"`
-	Path      string `json:"path,omitempty" ini:"path,omitempty"`
-	BucketUrl string `json:"bucket_url,omitempty" ini:"bucket_url,omitempty"`
-	AccessKey string `json:"access_key,omitempty" ini:"access_key,omitempty"`
-	SecretKey string `json:"secret_key,omitempty" ini:"secret_key,omitempty"`
+	Bucket       string                `json:"bucket,omitempty" ini:"bucket,omitempty"`
+	Region       string                `json:"region,omitempty" ini:"region,omitempty"`
+	Path         string                `json:"path,omitempty" ini:"path,omitempty"`
+	BucketUrl    string                `json:"bucket_url,omitempty" ini:"bucket_url,omitempty"`
+	AccessKey    string                `json:"access_key,omitempty" ini:"access_key,omitempty"`
+	AccessKeyRef *v1.SecretKeySelector `json:"access_key_ref,omitempty" ini:"-"`
+	SecretKey    string                `json:"secret_key,omitempty" ini:"secret_key,omitempty"`
+	SecretKeyRef *v1.SecretKeySelector `json:"secret_key_ref,omitempty" ini:"-"`
 }
 
 type GrafanaConfigExternalImageStorageWebdav struct {
-	Url       string `json:"url,omitempty" ini:"url,omitempty"`
-	PublicUrl string `json:"public_url,omitempty" ini:"public_url,omitempty"`
-	Username  string `json:"username,omitempty" ini:"username,omitempty"`
-	Password  string `json:"password,omitempty" ini:"password,omitempty"`
+	Url         string                `json:"url,omitempty" ini:"url,omitempty"`
+	PublicUrl   string                `json:"public_url,omitempty" ini:"public_url,omitempty"`
+	Username    string                `json:"username,omitempty" ini:"username,omitempty"`
+	Password    string                `json:"password,omitempty" ini:"password,omitempty"`
+	PasswordRef *v1.SecretKeySelector `json:"password_ref,omitempty" ini:"-"`
 }
 
 type GrafanaConfigExternalImageStorageGcs struct {
@@ -373,9 +453,10 @@ type GrafanaConfigExternalImageStorageGcs struct {
 }
 
 type GrafanaConfigExternalImageStorageAzureBlob struct {
-	AccountName   string `json:"account_name,omitempty" ini:"account_name,omitempty"`
-	AccountKey    string `json:"account_key,omitempty" ini:"account_key,omitempty"`
-	ContainerName string `json:"container_name,omitempty" ini:"container_name,omitempty"`
+	AccountName   string                `json:"account_name,omitempty" ini:"account_name,omitempty"`
+	AccountKey    string                `json:"account_key,omitempty" ini:"account_key,omitempty"`
+	AccountKeyRef *v1.SecretKeySelector `json:"account_key_ref,omitempty" ini:"-"`
+	ContainerName string                `json:"container_name,omitempty" ini:"container_name,omitempty"`
 }
 
 type GrafanaConfigAlerting struct {
@@ -397,6 +478,12 @@ type GrafanaConfigPlugins struct {
 	EnableAlpha *bool `json:"enable_alpha,omitempty" ini:"enable_alpha"`
 }
 
+type GrafanaConfigRendering struct {
+	ServerUrl                    string `json:"server_url,omitempty" ini:"server_url,omitempty"`
+	CallbackUrl                  string `json:"callback_url,omitempty" ini:"callback_url,omitempty"`
+	ConcurrentRenderRequestLimit *int   `json:"concurrent_render_request_limit,omitempty" ini:"concurrent_render_request_limit,omitempty"`
+}
+
 // GrafanaStatus defines the observed state of Grafana
 // +k8s:openapi-gen=true
 type GrafanaStatus struct {
@@ -406,6 +493,49 @@ type GrafanaStatus struct {
 	InstalledDatasources []*GrafanaDatasourceRef `json:"datasources"`
 	InstalledPlugins     PluginList              `json:"installedPlugins"`
 	FailedPlugins        PluginList              `json:"failedPlugins"`
+	Conditions           []Condition             `json:"conditions,omitempty"`
+	DashboardCount       int                     `json:"dashboardCount"`
+	DatasourceCount      int                     `json:"datasourceCount"`
+	LastSyncTime         *metav1.Time            `json:"lastSyncTime,omitempty"`
+	OrphanedDashboards   []*GrafanaDashboardRef  `json:"orphanedDashboards,omitempty"`
+	OrphanedDatasources  []*GrafanaDatasourceRef `json:"orphanedDatasources,omitempty"`
+	Tenants              []*GrafanaTenant        `json:"tenants,omitempty"`
+	// The value of the rotate admin password annotation that was last handled
+	AdminPasswordRotation string `json:"adminPasswordRotation,omitempty"`
+}
+
+// Organization of a namespace in an instance with namespaceOrgs tenancy
+type GrafanaTenant struct {
+	Namespace string `json:"namespace"`
+	// Id of the organization the operator created for the namespace, existing
+	// organizations named after the namespace are not used
+	OrgID uint `json:"orgId,omitempty"`
+	// Logins of the users that were granted the membership through the
+	// annotations of the namespace
+	Members []string `json:"members,omitempty"`
+}
+
+// Sync state of a dashboard or datasource in a single grafana instance, as
+// reported by the dashboard and datasource controllers
+type GrafanaInstanceStatus struct {
+	Namespace string       `json:"namespace"`
+	Name      string       `json:"name"`
+	ID        uint         `json:"id,omitempty"`
+	UID       string       `json:"uid,omitempty"`
+	Version   int          `json:"version,omitempty"`
+	LastSync  *metav1.Time `json:"lastSync,omitempty"`
+	// Hash of the submitted dashboard json, changes of the dashboard sources are
+	// detected by comparing it
+	Hash string `json:"hash,omitempty"`
+	// Resource version and key of the secret the password of a user was last
+	// read from, changes of the password are detected by comparing it
+	PasswordVersion string `json:"passwordVersion,omitempty"`
+	// Organizations a user was granted a role in, roles that are no longer
+	// listed are revoked
+	OrgIDs []uint `json:"orgIds,omitempty"`
+	// True if the operator created the object in grafana, false if it adopted
+	// an existing one
+	Created bool `json:"created,omitempty"`
 }
 
 // GrafanaPlugin contains information about a single plugin
@@ -435,6 +565,21 @@ type GrafanaList struct {
 	Items           []Grafana `json:"items"`
 }
 
+// ResourceNamespace returns the namespace of the dashboards and datasources of
+// the instance, all namespaces for namespaceOrgs tenancy
+func (in *Grafana) ResourceNamespace() string {
+	if in.Spec.Tenancy == TenancyNamespaceOrgs {
+		return metav1.NamespaceAll
+	}
+	return in.Namespace
+}
+
+// IngressReadinessRequired is true if the operator accesses grafana through the
+// ingress or route, which then has to be ready
+func (in *Grafana) IngressReadinessRequired() bool {
+	return in.Spec.Ingress != nil && in.Spec.Ingress.Enabled && (in.Spec.Client == nil || !in.Spec.Client.PreferService)
+}
+
 func init() {
 	SchemeBuilder.Register(&Grafana{}, &GrafanaList{})
 }
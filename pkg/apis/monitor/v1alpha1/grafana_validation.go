@@ -0,0 +1,292 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	v12 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Auth sections supported by grafana that can be configured with the extra config
+var knownAuthSections = map[string]bool{
+	"auth":               true,
+	"auth.anonymous":     true,
+	"auth.azuread":       true,
+	"auth.basic":         true,
+	"auth.generic_oauth": true,
+	"auth.github":        true,
+	"auth.gitlab":        true,
+	"auth.google":        true,
+	"auth.grafana_com":   true,
+	"auth.grafananet":    true,
+	"auth.jwt":           true,
+	"auth.ldap":          true,
+	"auth.okta":          true,
+	"auth.proxy":         true,
+	"auth.saml":          true,
+}
+
+// Returns the sections and keys of the typed config fields. Keys in the extra
+// config must not override them
+func typedConfigKeys() map[string]map[string]bool {
+	keys := map[string]map[string]bool{
+		// Paths are always set by the operator
+		"paths": {
+			"data":         true,
+			"logs":         true,
+			"plugins":      true,
+			"provisioning": true,
+		},
+	}
+
+	cfgType := reflect.TypeOf(GrafanaConfig{})
+	for i := 0; i < cfgType.NumField(); i++ {
+		section := iniName(cfgType.Field(i))
+		if section == "" {
+			continue
+		}
+
+		if keys[section] == nil {
+			keys[section] = map[string]bool{}
+		}
+
+		for _, field := range sectionFields(cfgType.Field(i).Type) {
+			if key := iniName(field); key != "" {
+				keys[section][key] = true
+			}
+		}
+	}
+	return keys
+}
+
+func sectionFields(sectionType reflect.Type) []reflect.StructField {
+	if sectionType.Kind() == reflect.Ptr {
+		sectionType = sectionType.Elem()
+	}
+	if sectionType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []reflect.StructField
+	for i := 0; i < sectionType.NumField(); i++ {
+		fields = append(fields, sectionType.Field(i))
+	}
+	return fields
+}
+
+func iniName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("ini"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// ValidateExtra checks that the extra config does not override any typed fields
+// and can't inject sections or keys into the ini file
+func (in *GrafanaConfig) ValidateExtra() error {
+	typed := typedConfigKeys()
+	for section, values := range in.Extra {
+		if section == "" || strings.ContainsAny(section, "\r\n[]") {
+			return fmt.Errorf("invalid extra config section %q", section)
+		}
+		for key, value := range values {
+			if strings.TrimSpace(key) == "" || strings.ContainsAny(key, "\r\n[=") {
+				return fmt.Errorf("invalid extra config key %q in section %v", key, section)
+			}
+			if strings.ContainsAny(value, "\r\n") {
+				return fmt.Errorf("extra config value of %v.%v must not contain line breaks", section, key)
+			}
+			if typed[section][key] {
+				return fmt.Errorf("extra config key %v.%v conflicts with a typed config field", section, key)
+			}
+		}
+	}
+	return nil
+}
+
+// Checks that sections added by the extra config are known auth sections
+func (in *GrafanaConfig) validateExtraAuthSections() error {
+	for section := range in.Extra {
+		if strings.HasPrefix(section, "auth.") && !knownAuthSections[section] {
+			return fmt.Errorf("unknown auth section %v", section)
+		}
+	}
+	return nil
+}
+
+// Checks that secret values are either provided in plain text or by a secret
+// reference, but not both
+func (in *GrafanaConfig) validateSecretRefs() error {
+	cfgValue := reflect.ValueOf(in).Elem()
+	for i := 0; i < cfgValue.NumField(); i++ {
+		section := cfgValue.Field(i)
+		if section.Kind() != reflect.Ptr || section.IsNil() || section.Elem().Kind() != reflect.Struct {
+			continue
+		}
+
+		for _, field := range sectionFields(section.Type()) {
+			if !strings.HasSuffix(field.Name, "Ref") {
+				continue
+			}
+
+			ref, ok := section.Elem().FieldByName(field.Name).Interface().(*v1.SecretKeySelector)
+			if !ok || ref == nil {
+				continue
+			}
+
+			if ref.Name == "" || ref.Key == "" {
+				return fmt.Errorf("secret reference %v.%v requires a name and a key",
+					iniName(cfgValue.Type().Field(i)), strings.Split(field.Tag.Get("json"), ",")[0])
+			}
+
+			value := section.Elem().FieldByName(strings.TrimSuffix(field.Name, "Ref"))
+			if value.IsValid() && value.String() != "" {
+				return fmt.Errorf("%v.%v is set both as plain text and as a secret reference",
+					iniName(cfgValue.Type().Field(i)), strings.Split(field.Tag.Get("json"), ",")[0])
+			}
+		}
+	}
+	return nil
+}
+
+func validateLabelSelectors(name string, selectors []*metav1.LabelSelector) error {
+	for _, selector := range selectors {
+		if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+			return fmt.Errorf("invalid %v: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func validateResources(name string, resources *v1.ResourceRequirements) error {
+	if resources == nil {
+		return nil
+	}
+
+	for resourceName, request := range resources.Requests {
+		limit, ok := resources.Limits[resourceName]
+		if ok && request.Cmp(limit) > 0 {
+			return fmt.Errorf("%v: %v request %v exceeds the limit %v",
+				name, resourceName, request.String(), limit.String())
+		}
+	}
+	return nil
+}
+
+func (in *Grafana) validateIngress() error {
+	ingress := in.Spec.Ingress
+	if ingress == nil {
+		return nil
+	}
+
+	switch ingress.Termination {
+	case "", v12.TLSTerminationEdge, v12.TLSTerminationReencrypt, v12.TLSTerminationPassthrough:
+	default:
+		return fmt.Errorf("invalid ingress termination %v, must be one of edge, reencrypt or passthrough", ingress.Termination)
+	}
+
+	// The operator accesses the API on the root path of the ingress host
+	preferService := in.Spec.Client != nil && in.Spec.Client.PreferService
+	if ingress.Enabled && !preferService && ingress.Path != "" && ingress.Path != "/" {
+		return fmt.Errorf("ingress path %v requires client.preferService to be enabled", ingress.Path)
+	}
+
+	return nil
+}
+
+func (in *Grafana) validateService() error {
+	if in.Spec.Service == nil {
+		return nil
+	}
+
+	switch in.Spec.Service.Type {
+	case "", v1.ServiceTypeClusterIP, v1.ServiceTypeNodePort, v1.ServiceTypeLoadBalancer:
+	default:
+		return fmt.Errorf("invalid service type %v, must be one of ClusterIP, NodePort or LoadBalancer", in.Spec.Service.Type)
+	}
+
+	return nil
+}
+
+// Validate checks the Grafana CR for invalid values and combinations
+func (in *Grafana) Validate() error {
+	if err := in.validateIngress(); err != nil {
+		return err
+	}
+
+	if err := in.validateService(); err != nil {
+		return err
+	}
+
+	if err := validateResources("resources", in.Spec.Resources); err != nil {
+		return err
+	}
+
+	if err := validateResources("initResources", in.Spec.InitResources); err != nil {
+		return err
+	}
+
+	if in.Spec.ImageRenderer != nil {
+		if err := validateResources("imageRenderer.resources", in.Spec.ImageRenderer.Resources); err != nil {
+			return err
+		}
+	}
+
+	if in.Spec.DataStorage != nil && in.Spec.DataStorage.Size.IsZero() {
+		return fmt.Errorf("dataStorage.size is required")
+	}
+
+	if err := validateLabelSelectors("dashboardLabelSelector", in.Spec.DashboardLabelSelector); err != nil {
+		return err
+	}
+
+	if err := validateLabelSelectors("datasourceLabelSelector", in.Spec.DatasourceLabelSelector); err != nil {
+		return err
+	}
+
+	if err := validateLabelSelectors("organizationLabelSelector", in.Spec.OrganizationLabelSelector); err != nil {
+		return err
+	}
+
+	if err := validateLabelSelectors("userLabelSelector", in.Spec.UserLabelSelector); err != nil {
+		return err
+	}
+
+	if err := validateLabelSelectors("teamLabelSelector", in.Spec.TeamLabelSelector); err != nil {
+		return err
+	}
+
+	if err := validateLabelSelectors("serviceAccountLabelSelector", in.Spec.ServiceAccountLabelSelector); err != nil {
+		return err
+	}
+
+	if in.Spec.Tenancy != TenancyShared && in.Spec.Tenancy != TenancyNamespaceOrgs {
+		return fmt.Errorf("unknown tenancy %v, expected %v or no value", in.Spec.Tenancy, TenancyNamespaceOrgs)
+	}
+
+	if in.Spec.TenantNamespaceSelector != nil {
+		if err := validateLabelSelectors("tenantNamespaceSelector", []*metav1.LabelSelector{in.Spec.TenantNamespaceSelector}); err != nil {
+			return err
+		}
+	}
+
+	if in.Spec.Jsonnet != nil && in.Spec.Jsonnet.LibraryLabelSelector != nil {
+		if err := validateLabelSelectors("jsonnet.libraryLabelSelector", []*metav1.LabelSelector{in.Spec.Jsonnet.LibraryLabelSelector}); err != nil {
+			return err
+		}
+	}
+
+	if err := in.Spec.Config.ValidateExtra(); err != nil {
+		return err
+	}
+
+	if err := in.Spec.Config.validateExtraAuthSections(); err != nil {
+		return err
+	}
+
+	return in.Spec.Config.validateSecretRefs()
+}
@@ -0,0 +1,95 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGrafanaValidate(t *testing.T) {
+	invalid := map[string]GrafanaSpec{
+		"termination": {
+			Ingress: &GrafanaIngress{Termination: "none"},
+		},
+		"ingress path": {
+			Ingress: &GrafanaIngress{Enabled: true, Path: "/grafana"},
+		},
+		"resources": {
+			Resources: &v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+				Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+			},
+		},
+		"label selector": {
+			DashboardLabelSelector: []*metav1.LabelSelector{
+				{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app", Operator: "Like"},
+					},
+				},
+			},
+		},
+		"auth section": {
+			Config: GrafanaConfig{
+				Extra: map[string]map[string]string{"auth.unknown": {"enabled": "true"}},
+			},
+		},
+		"secret ref": {
+			Config: GrafanaConfig{
+				Database: &GrafanaConfigDatabase{
+					Password: "secret",
+					PasswordRef: &v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "db"},
+						Key:                  "password",
+					},
+				},
+			},
+		},
+	}
+
+	for name, spec := range invalid {
+		cr := &Grafana{Spec: spec}
+		if err := cr.ValidateCreate(); err == nil {
+			t.Errorf("%v: expected validation error", name)
+		}
+	}
+
+	valid := &Grafana{
+		Spec: GrafanaSpec{
+			Ingress: &GrafanaIngress{Enabled: true, Path: "/grafana", Termination: "edge"},
+			Client:  &GrafanaClient{PreferService: true},
+			Config: GrafanaConfig{
+				Extra: map[string]map[string]string{"auth.azuread": {"enabled": "true"}},
+			},
+		},
+	}
+	if err := valid.ValidateCreate(); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+}
+
+func TestGrafanaDefault(t *testing.T) {
+	cr := &Grafana{
+		Spec: GrafanaSpec{
+			Service: &GrafanaService{
+				Ports: []v1.ServicePort{{Name: "proxy", Port: 9091}},
+			},
+		},
+	}
+	cr.Default()
+
+	if cr.Spec.Deployment.Replicas != DefaultReplicas {
+		t.Errorf("replicas not defaulted")
+	}
+
+	if *cr.Spec.Client.TimeoutSeconds != DefaultClientTimeoutSeconds {
+		t.Errorf("client timeout not defaulted")
+	}
+
+	port := cr.Spec.Service.Ports[0]
+	if port.Protocol != v1.ProtocolTCP || port.TargetPort.IntValue() != 9091 {
+		t.Errorf("service port not defaulted: %v", port)
+	}
+}
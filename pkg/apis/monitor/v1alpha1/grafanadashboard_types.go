@@ -28,6 +28,21 @@ type GrafanaDashboardSpec struct {
 	Url          string                       `json:"url,omitempty"`
 	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
 	Datasources  []GrafanaDashboardDatasource `json:"datasources,omitempty"`
+	// Name of the grafana organization of the dashboard and its folder, the
+	// default organization if empty
+	Organization string `json:"organization,omitempty"`
+	// Replaces the permissions of the dashboard if not empty. Permissions
+	// inherited from the folder still apply
+	Permissions []GrafanaDashboardPermission `json:"permissions,omitempty"`
+}
+
+// GrafanaDashboardStatus defines the observed state of GrafanaDashboard
+type GrafanaDashboardStatus struct {
+	Phase      StatusPhase             `json:"phase"`
+	Message    string                  `json:"message"`
+	UID        string                  `json:"uid,omitempty"`
+	Conditions []Condition             `json:"conditions,omitempty"`
+	Instances  []GrafanaInstanceStatus `json:"instances,omitempty"`
 }
 
 type GrafanaDashboardDatasource struct {
@@ -35,12 +50,32 @@ type GrafanaDashboardDatasource struct {
 	DatasourceName string `json:"datasourceName"`
 }
 
+// Permission of a team, a user or all users with an organization role on a
+// dashboard. Exactly one of team, user or role is required
+type GrafanaDashboardPermission struct {
+	// Name of a team of the organization of the dashboard
+	Team string `json:"team,omitempty"`
+	// Login or email of a user
+	User string `json:"user,omitempty"`
+	// Viewer or Editor
+	Role string `json:"role,omitempty"`
+	// View, Edit or Admin
+	Permission string `json:"permission"`
+}
+
 // Used to keep a dashboard reference without having access to the dashboard
 // struct itself
 type GrafanaDashboardRef struct {
-	Name      string `json:"name"`
-	Namespace string `json:"namespace"`
-	UID       string `json:"uid"`
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace"`
+	UID       string       `json:"uid"`
+	ID        uint         `json:"id,omitempty"`
+	Version   int          `json:"version,omitempty"`
+	LastSync  *metav1.Time `json:"lastSync,omitempty"`
+	// Only set for orphans, true if the dashboard was created by the operator
+	Managed bool `json:"managed,omitempty"`
+	// Only set for orphans, the organization of the dashboard
+	OrgID uint `json:"orgId,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -51,7 +86,8 @@ type GrafanaDashboard struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec GrafanaDashboardSpec `json:"spec,omitempty"`
+	Spec   GrafanaDashboardSpec   `json:"spec,omitempty"`
+	Status GrafanaDashboardStatus `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
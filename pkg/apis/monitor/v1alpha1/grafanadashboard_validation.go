@@ -0,0 +1,160 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/google/go-jsonnet"
+)
+
+// Levels of the dashboard permissions in the grafana api
+var DashboardPermissionLevels = map[string]int{
+	"View":  1,
+	"Edit":  2,
+	"Admin": 4,
+}
+
+// Maximum size of inline jsonnet accepted by the admission webhook
+const DashboardJsonnetMaxSize = 512 * 1024
+
+func (in *GrafanaDashboard) contentSources() []string {
+	var sources []string
+	if in.Spec.Json != "" {
+		sources = append(sources, "json")
+	}
+	if in.Spec.Jsonnet != "" {
+		sources = append(sources, "jsonnet")
+	}
+	if in.Spec.Url != "" {
+		sources = append(sources, "url")
+	}
+	if in.Spec.ConfigMapRef != nil {
+		sources = append(sources, "configMapRef")
+	}
+	return sources
+}
+
+// Returns the dashboard json if it can be obtained without accessing the cluster
+// or the network, empty otherwise
+func (in *GrafanaDashboard) inlineJson() (string, error) {
+	if in.Spec.Json == "" {
+		return "", nil
+	}
+
+	if _, err := in.Parse(""); err != nil {
+		return "", fmt.Errorf("invalid dashboard json: %v", err)
+	}
+	return in.Spec.Json, nil
+}
+
+// Jsonnet is only parsed, not evaluated. Evaluation can't be interrupted and may
+// import files, it is left to the dashboard controller
+func (in *GrafanaDashboard) validateJsonnet() error {
+	if in.Spec.Jsonnet == "" {
+		return nil
+	}
+
+	if len(in.Spec.Jsonnet) > DashboardJsonnetMaxSize {
+		return fmt.Errorf("dashboard jsonnet exceeds %v bytes", DashboardJsonnetMaxSize)
+	}
+
+	if _, err := jsonnet.SnippetToAST(in.Name, in.Spec.Jsonnet); err != nil {
+		return fmt.Errorf("invalid dashboard jsonnet: %v", err)
+	}
+	return nil
+}
+
+func (in *GrafanaDashboard) validateDatasources(json string) error {
+	for _, input := range in.Spec.Datasources {
+		if input.InputName == "" || input.DatasourceName == "" {
+			return fmt.Errorf("datasources require an inputName and a datasourceName")
+		}
+
+		// The contents of jsonnet, url and config map sources are only known at
+		// reconcile time
+		if json == "" {
+			continue
+		}
+
+		if !strings.Contains(json, fmt.Sprintf("${%s}", input.InputName)) {
+			return fmt.Errorf("datasource input %v is not used in the dashboard", input.InputName)
+		}
+	}
+	return nil
+}
+
+func (in *GrafanaDashboard) validatePlugins() error {
+	for _, plugin := range in.Spec.Plugins {
+		if plugin.Name == "" {
+			return fmt.Errorf("plugins require a name")
+		}
+
+		if _, err := semver.Make(plugin.Version); err != nil {
+			return fmt.Errorf("invalid version %v of plugin %v: %v", plugin.Version, plugin.Name, err)
+		}
+	}
+	return nil
+}
+
+func (in *GrafanaDashboard) validatePermissions() error {
+	for _, permission := range in.Spec.Permissions {
+		var grantees int
+		for _, grantee := range []string{permission.Team, permission.User, permission.Role} {
+			if grantee != "" {
+				grantees++
+			}
+		}
+		if grantees != 1 {
+			return fmt.Errorf("permissions require exactly one of team, user or role")
+		}
+
+		if permission.Role != "" && permission.Role != "Viewer" && permission.Role != "Editor" {
+			return fmt.Errorf("invalid permission role %v, expected Viewer or Editor", permission.Role)
+		}
+
+		if _, ok := DashboardPermissionLevels[permission.Permission]; !ok {
+			return fmt.Errorf("invalid permission %v, expected View, Edit or Admin", permission.Permission)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the dashboard has exactly one valid content source
+// and consistent datasource inputs, plugins and permissions
+func (in *GrafanaDashboard) Validate() error {
+	sources := in.contentSources()
+	if len(sources) != 1 {
+		return fmt.Errorf("exactly one of json, jsonnet, url or configMapRef is required, got %v", len(sources))
+	}
+
+	if in.Spec.Url != "" {
+		if _, err := url.ParseRequestURI(in.Spec.Url); err != nil {
+			return fmt.Errorf("invalid dashboard url %v", in.Spec.Url)
+		}
+	}
+
+	if ref := in.Spec.ConfigMapRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+		return fmt.Errorf("configMapRef requires a name and a key")
+	}
+
+	if err := in.validateJsonnet(); err != nil {
+		return err
+	}
+
+	json, err := in.inlineJson()
+	if err != nil {
+		return err
+	}
+
+	if err := in.validateDatasources(json); err != nil {
+		return err
+	}
+
+	if err := in.validatePlugins(); err != nil {
+		return err
+	}
+
+	return in.validatePermissions()
+}
@@ -0,0 +1,132 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGrafanaDashboardValidate(t *testing.T) {
+	invalid := map[string]GrafanaDashboardSpec{
+		"no source": {},
+		"two sources": {
+			Json: `{}`,
+			Url:  "https://grafana.com/api/dashboards/1/revisions/1/download",
+		},
+		"json": {
+			Json: `{"title": `,
+		},
+		"jsonnet": {
+			Jsonnet: `{ title: missing }`,
+		},
+		"jsonnet size": {
+			Jsonnet: "{ title: \"" + strings.Repeat("a", DashboardJsonnetMaxSize) + "\" }",
+		},
+		"config map ref": {
+			ConfigMapRef: &corev1.ConfigMapKeySelector{},
+		},
+		"datasource input": {
+			Json:        `{"panels": [{"datasource": "${DS_PROMETHEUS}"}]}`,
+			Datasources: []GrafanaDashboardDatasource{{InputName: "DS_LOKI", DatasourceName: "loki"}},
+		},
+		"plugin version": {
+			Json:    `{}`,
+			Plugins: PluginList{{Name: "grafana-piechart-panel", Version: "latest"}},
+		},
+		"permission grantee": {
+			Json:        `{}`,
+			Permissions: []GrafanaDashboardPermission{{Team: "sre", User: "alice", Permission: "View"}},
+		},
+		"permission role": {
+			Json:        `{}`,
+			Permissions: []GrafanaDashboardPermission{{Role: "Admin", Permission: "View"}},
+		},
+		"permission": {
+			Json:        `{}`,
+			Permissions: []GrafanaDashboardPermission{{Team: "sre", Permission: "Read"}},
+		},
+	}
+
+	for name, spec := range invalid {
+		cr := &GrafanaDashboard{Spec: spec}
+		if err := cr.ValidateCreate(); err == nil {
+			t.Errorf("%v: expected validation error", name)
+		}
+	}
+
+	valid := map[string]GrafanaDashboardSpec{
+		"json": {
+			Json:        `{"panels": [{"datasource": "${DS_PROMETHEUS}"}]}`,
+			Datasources: []GrafanaDashboardDatasource{{InputName: "DS_PROMETHEUS", DatasourceName: "prometheus"}},
+			Plugins:     PluginList{{Name: "grafana-piechart-panel", Version: "1.3.9"}},
+		},
+		"jsonnet": {
+			Jsonnet: `{ title: "dashboard", panels: [] }`,
+			Permissions: []GrafanaDashboardPermission{
+				{Team: "sre", Permission: "Admin"},
+				{Role: "Viewer", Permission: "View"},
+			},
+		},
+		"url": {
+			Url:         "https://grafana.com/api/dashboards/1/revisions/1/download",
+			Datasources: []GrafanaDashboardDatasource{{InputName: "DS_PROMETHEUS", DatasourceName: "prometheus"}},
+		},
+	}
+
+	for name, spec := range valid {
+		cr := &GrafanaDashboard{Spec: spec}
+		if err := cr.ValidateCreate(); err != nil {
+			t.Errorf("%v: unexpected validation error: %v", name, err)
+		}
+	}
+}
+
+// Jsonnet is not evaluated by the webhook, imports are not resolved and runtime
+// errors are left to the controller
+func TestGrafanaDashboardValidateJsonnetNotEvaluated(t *testing.T) {
+	snippets := []string{
+		`local f(n) = if n == 0 then 0 else f(n - 1) + f(n - 1); { title: f(64) }`,
+		`{ title: importstr "/var/run/secrets/kubernetes.io/serviceaccount/token" }`,
+		`error importstr "/etc/hostname"`,
+	}
+
+	for _, snippet := range snippets {
+		cr := &GrafanaDashboard{Spec: GrafanaDashboardSpec{Jsonnet: snippet}}
+		if err := cr.ValidateCreate(); err != nil {
+			t.Errorf("%v: unexpected validation error: %v", snippet, err)
+		}
+	}
+}
+
+func TestGrafanaDashboardValidateUpdate(t *testing.T) {
+	old := &GrafanaDashboard{Spec: GrafanaDashboardSpec{Json: `{"title": "overview"}`}}
+
+	permissions := old.DeepCopy()
+	permissions.Spec.Permissions = []GrafanaDashboardPermission{{Role: "Editor", Permission: "Edit"}}
+	if err := permissions.ValidateUpdate(old); err != nil {
+		t.Errorf("changing the permissions must be allowed: %v", err)
+	}
+
+	invalid := old.DeepCopy()
+	invalid.Spec.Permissions = []GrafanaDashboardPermission{{Role: "Admin", Permission: "Edit"}}
+	if err := invalid.ValidateUpdate(old); err == nil {
+		t.Error("expected an error for invalid permissions")
+	}
+
+	moved := old.DeepCopy()
+	moved.Spec.Organization = "team-a"
+	if err := moved.ValidateUpdate(old); err == nil {
+		t.Error("expected an error for a changed organization")
+	}
+
+	// Metadata of dashboards that became invalid can still be changed, e.g. to
+	// remove the finalizer
+	legacy := &GrafanaDashboard{Spec: GrafanaDashboardSpec{Json: `{"title": `}}
+	legacy.Finalizers = []string{"finalizer.grafanadashboards.monitor.kun"}
+	finalized := legacy.DeepCopy()
+	finalized.Finalizers = nil
+	if err := finalized.ValidateUpdate(legacy); err != nil {
+		t.Errorf("metadata changes must be allowed: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Datasource plugins accept arbitrary options in jsonData and secureJsonData.
+// Options without a typed field are kept in Extra, so they survive updates of
+// the resource and are sent to grafana
+
+func (in GrafanaDataSourceJsonData) MarshalJSON() ([]byte, error) {
+	type typed GrafanaDataSourceJsonData
+	return MarshalJsonWithExtra(typed(in), in.Extra)
+}
+
+func (in *GrafanaDataSourceJsonData) UnmarshalJSON(data []byte) error {
+	type typed GrafanaDataSourceJsonData
+	extra, err := UnmarshalJsonWithExtra(data, (*typed)(in))
+	in.Extra = extra
+	return err
+}
+
+func (in GrafanaDataSourceSecureJsonData) MarshalJSON() ([]byte, error) {
+	type typed GrafanaDataSourceSecureJsonData
+	return MarshalJsonWithExtra(typed(in), in.Extra)
+}
+
+func (in *GrafanaDataSourceSecureJsonData) UnmarshalJSON(data []byte) error {
+	type typed GrafanaDataSourceSecureJsonData
+	extra, err := UnmarshalJsonWithExtra(data, (*typed)(in))
+	in.Extra = extra
+	return err
+}
+
+// deepcopy-gen can't copy the Extra options, the deepcopy functions of the
+// json data are written by hand
+
+func (in *GrafanaDataSourceJsonData) DeepCopyInto(out *GrafanaDataSourceJsonData) {
+	*out = *in
+	if in.Extra != nil {
+		out.Extra = runtime.DeepCopyJSON(in.Extra)
+	}
+}
+
+func (in *GrafanaDataSourceJsonData) DeepCopy() *GrafanaDataSourceJsonData {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDataSourceJsonData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *GrafanaDataSourceSecureJsonData) DeepCopyInto(out *GrafanaDataSourceSecureJsonData) {
+	*out = *in
+	if in.Extra != nil {
+		out.Extra = runtime.DeepCopyJSON(in.Extra)
+	}
+}
+
+func (in *GrafanaDataSourceSecureJsonData) DeepCopy() *GrafanaDataSourceSecureJsonData {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDataSourceSecureJsonData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// Resources created before lists were supported contain a single datasource object
+func (in *GrafanaDataSourceFieldsList) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		fields := GrafanaDataSourceFields{}
+		if err := json.Unmarshal(trimmed, &fields); err != nil {
+			return err
+		}
+		*in = GrafanaDataSourceFieldsList{fields}
+		return nil
+	}
+
+	var list []GrafanaDataSourceFields
+	if err := json.Unmarshal(trimmed, &list); err != nil {
+		return err
+	}
+	*in = list
+	return nil
+}
+
+// MarshalJsonWithExtra serializes the typed struct and deep merges it into the
+// extra options. Typed fields take precedence
+func MarshalJsonWithExtra(typed interface{}, extra map[string]interface{}) ([]byte, error) {
+	bytes, err := json.Marshal(typed)
+	if err != nil || len(extra) == 0 {
+		return bytes, err
+	}
+
+	typedValues := map[string]interface{}{}
+	if err := json.Unmarshal(bytes, &typedValues); err != nil {
+		return nil, err
+	}
+
+	merged := runtime.DeepCopyJSON(extra)
+	MergeJson(merged, typedValues)
+	return json.Marshal(merged)
+}
+
+// UnmarshalJsonWithExtra deserializes the typed struct and returns all values
+// that are not covered by its fields
+func UnmarshalJsonWithExtra(data []byte, typed interface{}) (map[string]interface{}, error) {
+	if err := json.Unmarshal(data, typed); err != nil {
+		return nil, err
+	}
+
+	extra := map[string]interface{}{}
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return nil, err
+	}
+
+	typedType := reflect.TypeOf(typed).Elem()
+	for i := 0; i < typedType.NumField(); i++ {
+		name := strings.Split(typedType.Field(i).Tag.Get("json"), ",")[0]
+		delete(extra, name)
+	}
+
+	if len(extra) == 0 {
+		return nil, nil
+	}
+	return extra, nil
+}
+
+// MergeJson recursively merges src into dst. Nested objects are merged, all
+// other values in src replace the values in dst
+func MergeJson(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcObject, srcIsObject := value.(map[string]interface{})
+		dstObject, dstIsObject := dst[key].(map[string]interface{})
+		if srcIsObject && dstIsObject {
+			MergeJson(dstObject, srcObject)
+			continue
+		}
+		dst[key] = value
+	}
+}
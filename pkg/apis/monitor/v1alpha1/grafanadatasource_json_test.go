@@ -0,0 +1,77 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGrafanaDataSourceJsonDataExtra(t *testing.T) {
+	raw := `{
+		"timeInterval": "5s",
+		"derivedFields": [{"name": "traceID", "datasourceUid": "tempo"}],
+		"tracesToLogs": {"datasourceUid": "loki", "tags": ["job"]}
+	}`
+
+	jsonData := GrafanaDataSourceJsonData{}
+	if err := json.Unmarshal([]byte(raw), &jsonData); err != nil {
+		t.Fatal(err)
+	}
+
+	if jsonData.TimeInterval != "5s" {
+		t.Errorf("typed field not set: %+v", jsonData)
+	}
+
+	if len(jsonData.Extra) != 2 || jsonData.Extra["timeInterval"] != nil {
+		t.Errorf("unexpected extra options: %v", jsonData.Extra)
+	}
+
+	bytes, err := json.Marshal(jsonData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected, actual map[string]interface{}
+	json.Unmarshal([]byte(raw), &expected)
+	json.Unmarshal(bytes, &actual)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("options not preserved:\n%v\n%v", expected, actual)
+	}
+}
+
+func TestMergeJson(t *testing.T) {
+	dst := map[string]interface{}{
+		"a": map[string]interface{}{"x": "1", "y": "2"},
+		"b": "extra",
+	}
+	src := map[string]interface{}{
+		"a": map[string]interface{}{"y": "typed"},
+		"b": "typed",
+	}
+
+	MergeJson(dst, src)
+
+	expected := map[string]interface{}{
+		"a": map[string]interface{}{"x": "1", "y": "typed"},
+		"b": "typed",
+	}
+	if !reflect.DeepEqual(dst, expected) {
+		t.Errorf("unexpected merge result: %v", dst)
+	}
+}
+
+func TestGrafanaDataSourceFieldsListSingleObject(t *testing.T) {
+	spec := GrafanaDataSourceSpec{}
+	if err := json.Unmarshal([]byte(`{"datasources": {"name": "prometheus", "type": "prometheus"}}`), &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(spec.Datasources) != 1 || spec.Datasources[0].Name != "prometheus" {
+		t.Errorf("single datasource not converted to a list: %+v", spec.Datasources)
+	}
+
+	bytes, _ := json.Marshal(spec)
+	if err := json.Unmarshal(bytes, &spec); err != nil || len(spec.Datasources) != 1 {
+		t.Errorf("list not preserved: %s", bytes)
+	}
+}
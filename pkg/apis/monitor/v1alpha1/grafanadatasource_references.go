@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// Datasources can refer to the uid of other datasources in their json options,
+// e.g. tempo to loki. The references are replaced by the operator
+var DatasourceUidReference = regexp.MustCompile(`\$\{uid:([^}]+)\}`)
+
+// References returns the names of the datasources referenced in the json options
+func (in *GrafanaDataSourceFields) References() []string {
+	var names []string
+	for _, options := range []interface{}{in.JsonData, in.SecureJsonData} {
+		bytes, err := json.Marshal(options)
+		if err != nil {
+			continue
+		}
+
+		for _, match := range DatasourceUidReference.FindAllStringSubmatch(string(bytes), -1) {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// Sorted returns the datasources ordered so that referenced datasources of the
+// list come before the ones referencing them. The order of the list is kept
+// otherwise. References to datasources outside of the list are ignored
+func (in GrafanaDataSourceFieldsList) Sorted() (GrafanaDataSourceFieldsList, error) {
+	indexes := map[string]int{}
+	for i, fields := range in {
+		indexes[fields.Name] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(in))
+	sorted := make(GrafanaDataSourceFieldsList, 0, len(in))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("circular reference to datasource %v", in[i].Name)
+		}
+
+		state[i] = visiting
+		for _, name := range in[i].References() {
+			if ref, ok := indexes[name]; ok {
+				if err := visit(ref); err != nil {
+					return err
+				}
+			}
+		}
+		state[i] = visited
+		sorted = append(sorted, in[i])
+		return nil
+	}
+
+	for i := range in {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
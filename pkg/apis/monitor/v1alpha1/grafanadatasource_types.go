@@ -2,10 +2,11 @@ package v1alpha1
 
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
-	"io"
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -20,14 +21,33 @@ type GrafanaDataSourceSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book.kubebuilder.io/beyond_basics/generating_crd.html
-	Datasources GrafanaDataSourceFields `json:"datasources"`
+	Datasources GrafanaDataSourceFieldsList `json:"datasources"`
+	// Name of the grafana organization of the datasources, the default
+	// organization if empty. Overridden by the orgId of a datasource
+	Organization string `json:"organization,omitempty"`
 }
 
 // GrafanaDataSourceStatus defines the observed state of GrafanaDataSource
 // +k8s:openapi-gen=true
 type GrafanaDataSourceStatus struct {
-	Phase   StatusPhase `json:"phase"`
-	Message string      `json:"message"`
+	Phase       StatusPhase                   `json:"phase"`
+	Message     string                        `json:"message"`
+	Datasources []GrafanaDataSourceItemStatus `json:"datasources,omitempty"`
+	Conditions  []Condition                   `json:"conditions,omitempty"`
+}
+
+// Sync state of a single datasource of the list. Also used to remove datasources
+// from grafana once they are removed from the list
+type GrafanaDataSourceItemStatus struct {
+	Name string `json:"name"`
+	UID  string `json:"uid,omitempty"`
+	// The orgId of the datasource in the spec, datasources removed from the
+	// list are deleted from this organization
+	OrgID      int                     `json:"orgId,omitempty"`
+	Phase      StatusPhase             `json:"phase"`
+	Message    string                  `json:"message,omitempty"`
+	Conditions []Condition             `json:"conditions,omitempty"`
+	Instances  []GrafanaInstanceStatus `json:"instances,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -51,28 +71,49 @@ type GrafanaDataSourceList struct {
 	Items           []GrafanaDataSource `json:"items"`
 }
 
+// Datasources of a resource. A single datasource object is accepted as well
+type GrafanaDataSourceFieldsList []GrafanaDataSourceFields
+
 type GrafanaDataSourceFields struct {
-	Name              string                          `json:"name"`
-	Type              string                          `json:"type"`
-	Access            string                          `json:"access"`
-	OrgId             int                             `json:"orgId,omitempty"`
-	Url               string                          `json:"url"`
-	Password          string                          `json:"password,omitempty"`
-	User              string                          `json:"user,omitempty"`
-	Database          string                          `json:"database,omitempty"`
-	BasicAuth         bool                            `json:"basicAuth,omitempty"`
-	BasicAuthUser     string                          `json:"basicAuthUser,omitempty"`
-	BasicAuthPassword string                          `json:"basicAuthPassword,omitempty"`
-	WithCredentials   bool                            `json:"withCredentials,omitempty"`
-	IsDefault         bool                            `json:"isDefault,omitempty"`
-	JsonData          GrafanaDataSourceJsonData       `json:"jsonData,omitempty"`
-	SecureJsonData    GrafanaDataSourceSecureJsonData `json:"secureJsonData,omitempty"`
-	Version           int                             `json:"version,omitempty"`
-	Editable          bool                            `json:"editable,omitempty"`
+	Name                 string                          `json:"name"`
+	Uid                  string                          `json:"uid,omitempty"`
+	Type                 string                          `json:"type"`
+	Access               string                          `json:"access"`
+	OrgId                int                             `json:"orgId,omitempty"`
+	Url                  string                          `json:"url"`
+	Password             string                          `json:"password,omitempty"`
+	PasswordRef          *v1.SecretKeySelector           `json:"passwordRef,omitempty"`
+	User                 string                          `json:"user,omitempty"`
+	Database             string                          `json:"database,omitempty"`
+	BasicAuth            bool                            `json:"basicAuth,omitempty"`
+	BasicAuthUser        string                          `json:"basicAuthUser,omitempty"`
+	BasicAuthPassword    string                          `json:"basicAuthPassword,omitempty"`
+	BasicAuthPasswordRef *v1.SecretKeySelector           `json:"basicAuthPasswordRef,omitempty"`
+	WithCredentials      bool                            `json:"withCredentials,omitempty"`
+	IsDefault            bool                            `json:"isDefault,omitempty"`
+	JsonData             GrafanaDataSourceJsonData       `json:"jsonData,omitempty"`
+	SecureJsonData       GrafanaDataSourceSecureJsonData `json:"secureJsonData,omitempty"`
+	HttpHeaders          []GrafanaDataSourceHttpHeader   `json:"httpHeaders,omitempty"`
+	Version              int                             `json:"version,omitempty"`
+	Editable             bool                            `json:"editable,omitempty"`
+}
+
+// Custom HTTP header sent by grafana to the datasource. Expanded into the numbered
+// httpHeaderName and httpHeaderValue fields of the json data
+type GrafanaDataSourceHttpHeader struct {
+	Name      string                             `json:"name"`
+	Value     string                             `json:"value,omitempty"`
+	ValueFrom *GrafanaDataSourceHttpHeaderSource `json:"valueFrom,omitempty"`
+}
+
+type GrafanaDataSourceHttpHeaderSource struct {
+	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
 
 // The most common json options
 // See https://grafana.com/docs/administration/provisioning/#datasources
+// The deepcopy functions are hand-written, see grafanadatasource_json.go
+// +k8s:deepcopy-gen=false
 type GrafanaDataSourceJsonData struct {
 	TlsAuth                 bool   `json:"tlsAuth,omitempty"`
 	TlsAuthWithCACert       bool   `json:"tlsAuthWithCACert,omitempty"`
@@ -122,17 +163,31 @@ type GrafanaDataSourceJsonData struct {
 	ClientEmail        string `json:"clientEmail,omitempty"`
 	AuthenticationType string `json:"authenticationType,omitempty"`
 	DefaultProject     string `json:"defaultProject,omitempty"`
+	// Options without a typed field, e.g. of datasource plugins. Merged with
+	// the typed fields when serialized. Not supported by deepcopy-gen
+	Extra map[string]interface{} `json:"-"`
 }
 
 // Used to keep a datasource reference without having access to the datasource
 // struct itself
 type GrafanaDatasourceRef struct {
-	Name string `json:"name"`
-	ID   string `json:"id"`
+	Name      string       `json:"name"`
+	ID        string       `json:"id"`
+	Namespace string       `json:"namespace,omitempty"`
+	Resource  string       `json:"resource,omitempty"`
+	UID       string       `json:"uid,omitempty"`
+	Version   int          `json:"version,omitempty"`
+	LastSync  *metav1.Time `json:"lastSync,omitempty"`
+	// Only set for orphans, true if the datasource was created by the operator
+	Managed bool `json:"managed,omitempty"`
+	// Only set for orphans, the organization of the datasource
+	OrgID uint `json:"orgId,omitempty"`
 }
 
 // The most common secure json options
 // See https://grafana.com/docs/administration/provisioning/#datasources
+// The deepcopy functions are hand-written, see grafanadatasource_json.go
+// +k8s:deepcopy-gen=false
 type GrafanaDataSourceSecureJsonData struct {
 	TlsCaCert         string `json:"tlsCACert,omitempty"`
 	TlsClientCert     string `json:"tlsClientCert,omitempty"`
@@ -154,6 +209,9 @@ type GrafanaDataSourceSecureJsonData struct {
 	HTTPHeaderValue9 string `json:"httpHeaderValue9,omitempty"`
 	// Fields for Stackdriver data sources
 	PrivateKey string `json:"privateKey,omitempty"`
+	// Options without a typed field, e.g. of datasource plugins. Merged with
+	// the typed fields when serialized. Not supported by deepcopy-gen
+	Extra map[string]interface{} `json:"-"`
 }
 
 func init() {
@@ -166,7 +224,19 @@ func (in *GrafanaDataSource) Filename() string {
 }
 
 func (in *GrafanaDataSource) Hash() string {
+	// Secret references are pointers and have to be hashed by value
+	fields, _ := json.Marshal(in.Spec.Datasources)
+
 	hash := sha256.New()
-	io.WriteString(hash, fmt.Sprintf("%v", in.Spec.Datasources))
+	hash.Write(fields)
 	return fmt.Sprintf("%x", hash.Sum(nil))
 }
+
+// Names returns the names of the datasources in the list
+func (in GrafanaDataSourceFieldsList) Names() []string {
+	names := make([]string, 0, len(in))
+	for _, fields := range in {
+		names = append(names, fields.Name)
+	}
+	return names
+}
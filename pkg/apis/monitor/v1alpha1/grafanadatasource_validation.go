@@ -0,0 +1,188 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// Datasource types shipped with grafana
+var builtinDatasourceTypes = map[string]bool{
+	"alertmanager":                     true,
+	"cloudwatch":                       true,
+	"elasticsearch":                    true,
+	"grafana-azure-monitor-datasource": true,
+	"graphite":                         true,
+	"influxdb":                         true,
+	"jaeger":                           true,
+	"loki":                             true,
+	"mssql":                            true,
+	"mysql":                            true,
+	"opentsdb":                         true,
+	"postgres":                         true,
+	"prometheus":                       true,
+	"stackdriver":                      true,
+	"tempo":                            true,
+	"testdata":                         true,
+	"zipkin":                           true,
+}
+
+// Builtin datasources that are accessed over http and require a url with a scheme
+// and a host. The sql datasources take a plain host:port address
+var httpDatasourceTypes = map[string]bool{
+	"alertmanager":  true,
+	"elasticsearch": true,
+	"graphite":      true,
+	"influxdb":      true,
+	"jaeger":        true,
+	"loki":          true,
+	"opentsdb":      true,
+	"prometheus":    true,
+	"tempo":         true,
+	"zipkin":        true,
+}
+
+// Datasource plugins follow the <org>-<name>-datasource naming scheme
+var datasourcePluginType = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*-datasource$`)
+
+const (
+	DatasourceAccessProxy  = "proxy"
+	DatasourceAccessDirect = "direct"
+)
+
+func validateDatasourceType(dsType string) error {
+	if dsType == "" {
+		return fmt.Errorf("datasource type is required")
+	}
+
+	if !builtinDatasourceTypes[dsType] && !datasourcePluginType.MatchString(dsType) {
+		return fmt.Errorf("unknown datasource type %v", dsType)
+	}
+	return nil
+}
+
+func validateDatasourceUrl(dsType, dsUrl string) error {
+	if !httpDatasourceTypes[dsType] {
+		if _, err := url.Parse(dsUrl); err != nil {
+			return fmt.Errorf("invalid datasource url %v", dsUrl)
+		}
+		return nil
+	}
+
+	parsed, err := url.ParseRequestURI(dsUrl)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("invalid datasource url %v, %v datasources require a url with a scheme and a host", dsUrl, dsType)
+	}
+	return nil
+}
+
+func validateHttpHeader(header GrafanaDataSourceHttpHeader) error {
+	if header.Name == "" {
+		return fmt.Errorf("http headers require a name")
+	}
+
+	if header.ValueFrom == nil {
+		return nil
+	}
+
+	if header.Value != "" {
+		return fmt.Errorf("http header %v has both a value and valueFrom", header.Name)
+	}
+
+	ref := header.ValueFrom.SecretKeyRef
+	if ref == nil || ref.Name == "" || ref.Key == "" {
+		return fmt.Errorf("valueFrom of http header %v requires a secretKeyRef with a name and a key", header.Name)
+	}
+	return nil
+}
+
+func (in *GrafanaDataSourceFields) validate() error {
+	if in.Name == "" {
+		return fmt.Errorf("datasource name is required")
+	}
+
+	if err := validateDatasourceType(in.Type); err != nil {
+		return err
+	}
+
+	switch in.Access {
+	case "", DatasourceAccessProxy, DatasourceAccessDirect:
+	default:
+		return fmt.Errorf("invalid datasource access %v, must be one of proxy or direct", in.Access)
+	}
+
+	if in.Password != "" && in.PasswordRef != nil {
+		return fmt.Errorf("password is set both as plain text and as a secret reference")
+	}
+
+	if in.BasicAuthPassword != "" && in.BasicAuthPasswordRef != nil {
+		return fmt.Errorf("basicAuthPassword is set both as plain text and as a secret reference")
+	}
+
+	for _, header := range in.HttpHeaders {
+		if err := validateHttpHeader(header); err != nil {
+			return err
+		}
+	}
+
+	if in.Url != "" {
+		if err := validateDatasourceUrl(in.Type, in.Url); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate checks every datasource of the list and that names and uids are
+// unique and references between the datasources are not circular
+func (in *GrafanaDataSource) Validate() error {
+	if len(in.Spec.Datasources) == 0 {
+		return fmt.Errorf("at least one datasource is required")
+	}
+
+	names := map[string]bool{}
+	uids := map[string]bool{}
+	for i := range in.Spec.Datasources {
+		fields := &in.Spec.Datasources[i]
+		if err := fields.validate(); err != nil {
+			return fmt.Errorf("datasource %v: %v", i, err)
+		}
+
+		if names[fields.Name] {
+			return fmt.Errorf("duplicate datasource name %v", fields.Name)
+		}
+		names[fields.Name] = true
+
+		if fields.Uid != "" && uids[fields.Uid] {
+			return fmt.Errorf("duplicate datasource uid %v", fields.Uid)
+		}
+		uids[fields.Uid] = true
+	}
+
+	_, err := in.Spec.Datasources.Sorted()
+	return err
+}
+
+// Returns the name of a datasource that exists in both resources with different
+// fields, empty if there is none
+func (in *GrafanaDataSource) changedDatasource(old *GrafanaDataSource) string {
+	previous := map[string][]byte{}
+	for _, fields := range old.Spec.Datasources {
+		previous[fields.Name], _ = json.Marshal(fields)
+	}
+
+	for _, fields := range in.Spec.Datasources {
+		oldFields, ok := previous[fields.Name]
+		if !ok {
+			continue
+		}
+
+		if newFields, _ := json.Marshal(fields); !bytes.Equal(oldFields, newFields) {
+			return fields.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestGrafanaDataSourceValidate(t *testing.T) {
+	invalid := map[string]GrafanaDataSourceFields{
+		"type":   {Name: "ds", Type: "unknown", Access: "proxy"},
+		"access": {Name: "ds", Type: "prometheus", Access: "server"},
+		"url":    {Name: "ds", Type: "prometheus", Access: "proxy", Url: "http://prometheus:port"},
+		"scheme": {Name: "ds", Type: "prometheus", Access: "proxy", Url: "prometheus:9090"},
+		"host":   {Name: "ds", Type: "loki", Access: "proxy", Url: "/loki"},
+	}
+
+	for name, fields := range invalid {
+		cr := &GrafanaDataSource{Spec: GrafanaDataSourceSpec{Datasources: GrafanaDataSourceFieldsList{fields}}}
+		if err := cr.ValidateCreate(); err == nil {
+			t.Errorf("%v: expected validation error", name)
+		}
+	}
+
+	valid := []GrafanaDataSourceFields{
+		{Name: "prometheus", Type: "prometheus", Access: "proxy", Url: "http://prometheus:9090"},
+		{Name: "clickhouse", Type: "vertamedia-clickhouse-datasource", Access: "direct"},
+		{Name: "mysql", Type: "mysql", Url: "mysql:3306"},
+	}
+
+	for _, fields := range valid {
+		cr := &GrafanaDataSource{Spec: GrafanaDataSourceSpec{Datasources: GrafanaDataSourceFieldsList{fields}}}
+		if err := cr.ValidateCreate(); err != nil {
+			t.Errorf("%v: unexpected validation error: %v", fields.Name, err)
+		}
+	}
+}
+
+func TestGrafanaDataSourceValidateList(t *testing.T) {
+	tempo := GrafanaDataSourceFields{
+		Name: "tempo",
+		Type: "tempo",
+		JsonData: GrafanaDataSourceJsonData{
+			Extra: map[string]interface{}{
+				"tracesToLogs": map[string]interface{}{"datasourceUid": "${uid:loki}"},
+			},
+		},
+	}
+	loki := GrafanaDataSourceFields{Name: "loki", Type: "loki", Uid: "loki"}
+
+	cr := &GrafanaDataSource{Spec: GrafanaDataSourceSpec{Datasources: GrafanaDataSourceFieldsList{tempo, loki}}}
+	if err := cr.ValidateCreate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	sorted, _ := cr.Spec.Datasources.Sorted()
+	if sorted[0].Name != "loki" || sorted[1].Name != "tempo" {
+		t.Errorf("referenced datasource must come first, got %v, %v", sorted[0].Name, sorted[1].Name)
+	}
+
+	invalid := map[string]GrafanaDataSourceFieldsList{
+		"empty":     {},
+		"duplicate": {loki, loki},
+	}
+
+	circular := loki.DeepCopy()
+	circular.SecureJsonData.Extra = map[string]interface{}{"token": "${uid:tempo}"}
+	invalid["circular"] = GrafanaDataSourceFieldsList{tempo, *circular}
+
+	for name, list := range invalid {
+		cr := &GrafanaDataSource{Spec: GrafanaDataSourceSpec{Datasources: list}}
+		if err := cr.ValidateCreate(); err == nil {
+			t.Errorf("%v: expected validation error", name)
+		}
+	}
+}
+
+func TestGrafanaDataSourceValidateUpdate(t *testing.T) {
+	prometheus := GrafanaDataSourceFields{Name: "prometheus", Type: "prometheus", Url: "http://prometheus:9090"}
+	loki := GrafanaDataSourceFields{Name: "loki", Type: "loki", Url: "http://loki:3100"}
+
+	old := &GrafanaDataSource{Spec: GrafanaDataSourceSpec{Datasources: GrafanaDataSourceFieldsList{prometheus}}}
+	added := &GrafanaDataSource{Spec: GrafanaDataSourceSpec{Datasources: GrafanaDataSourceFieldsList{prometheus, loki}}}
+	if err := added.ValidateUpdate(old); err != nil {
+		t.Errorf("adding a datasource must be allowed: %v", err)
+	}
+
+	if err := old.ValidateUpdate(added); err != nil {
+		t.Errorf("removing a datasource must be allowed: %v", err)
+	}
+
+	changed := added.DeepCopy()
+	changed.Spec.Datasources[0].Url = "http://prometheus:9091"
+	if err := changed.ValidateUpdate(added); err == nil {
+		t.Errorf("expected an error for a changed datasource")
+	}
+}
@@ -0,0 +1,85 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const GrafanaOrganizationKind = "GrafanaOrganization"
+
+// What happens to the organization in grafana when the resource is deleted
+type OrganizationDeletionPolicy string
+
+const (
+	// Organizations created by the operator are deleted, adopted organizations
+	// are kept
+	OrganizationDeletionPolicyCreated OrganizationDeletionPolicy = ""
+	// The organization is deleted even if it existed before it was adopted
+	OrganizationDeletionPolicyDelete OrganizationDeletionPolicy = "Delete"
+	// The organization is never deleted
+	OrganizationDeletionPolicyRetain OrganizationDeletionPolicy = "Retain"
+)
+
+// GrafanaOrganizationSpec defines the desired state of GrafanaOrganization
+// +k8s:openapi-gen=true
+type GrafanaOrganizationSpec struct {
+	// Name of the organization in grafana, defaults to the name of the resource
+	Name        string                          `json:"name,omitempty"`
+	Preferences *GrafanaOrganizationPreferences `json:"preferences,omitempty"`
+	// Deletes the organization with all its dashboards, datasources and users
+	// when the resource is deleted
+	DeletionPolicy OrganizationDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// Preferences of all users of the organization. Empty fields keep the grafana
+// defaults
+type GrafanaOrganizationPreferences struct {
+	// light or dark
+	Theme string `json:"theme,omitempty"`
+	// utc or browser
+	Timezone string `json:"timezone,omitempty"`
+	// Uid of a dashboard of the organization
+	HomeDashboardUID string `json:"homeDashboardUid,omitempty"`
+}
+
+// GrafanaOrganizationStatus defines the observed state of GrafanaOrganization
+// +k8s:openapi-gen=true
+type GrafanaOrganizationStatus struct {
+	Phase      StatusPhase `json:"phase"`
+	Message    string      `json:"message"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	// The id of the organization in every grafana instance
+	Instances []GrafanaInstanceStatus `json:"instances,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GrafanaOrganization is the Schema for the grafanaorganizations API
+// +k8s:openapi-gen=true
+type GrafanaOrganization struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaOrganizationSpec   `json:"spec,omitempty"`
+	Status GrafanaOrganizationStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GrafanaOrganizationList contains a list of GrafanaOrganization
+type GrafanaOrganizationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaOrganization `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaOrganization{}, &GrafanaOrganizationList{})
+}
+
+// OrganizationName returns the name of the organization in grafana
+func (in *GrafanaOrganization) OrganizationName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
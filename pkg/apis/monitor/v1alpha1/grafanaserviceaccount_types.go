@@ -0,0 +1,93 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const GrafanaServiceAccountKind = "GrafanaServiceAccount"
+
+// Changing the value of the annotation on a GrafanaServiceAccount rotates its
+// tokens right away
+const RotateTokenAnnotation = "monitor.kun/rotate-token"
+
+// GrafanaServiceAccountSpec defines the desired state of GrafanaServiceAccount
+// +k8s:openapi-gen=true
+type GrafanaServiceAccountSpec struct {
+	// Name of the service account in grafana, defaults to the name of the
+	// resource
+	Name string `json:"name,omitempty"`
+	// Name of the grafana organization of the service account, the default
+	// organization if empty
+	Organization string `json:"organization,omitempty"`
+	// Viewer, Editor or Admin, defaults to Viewer
+	Role     string `json:"role,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+	// Secret the tokens are written to, one key per grafana instance. Defaults
+	// to <name>-grafana-token
+	SecretName string `json:"secretName,omitempty"`
+	// Tokens older than the period are replaced, e.g. 720h. Tokens are only
+	// replaced on demand if empty
+	RotationPeriod *metav1.Duration `json:"rotationPeriod,omitempty"`
+}
+
+// GrafanaServiceAccountStatus defines the observed state of GrafanaServiceAccount
+// +k8s:openapi-gen=true
+type GrafanaServiceAccountStatus struct {
+	Phase      StatusPhase `json:"phase"`
+	Message    string      `json:"message"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	// The id of the service account the operator created in every grafana
+	// instance, the last sync is the time the token was created
+	Instances []GrafanaInstanceStatus `json:"instances,omitempty"`
+	// Value of the rotate annotation the tokens were created for
+	Rotation string `json:"rotation,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GrafanaServiceAccount is the Schema for the grafanaserviceaccounts API
+// +k8s:openapi-gen=true
+type GrafanaServiceAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaServiceAccountSpec   `json:"spec,omitempty"`
+	Status GrafanaServiceAccountStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GrafanaServiceAccountList contains a list of GrafanaServiceAccount
+type GrafanaServiceAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaServiceAccount `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaServiceAccount{}, &GrafanaServiceAccountList{})
+}
+
+// ServiceAccountName returns the name of the service account in grafana
+func (in *GrafanaServiceAccount) ServiceAccountName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
+
+// ServiceAccountRole returns the organization role of the service account
+func (in *GrafanaServiceAccount) ServiceAccountRole() string {
+	if in.Spec.Role != "" {
+		return in.Spec.Role
+	}
+	return "Viewer"
+}
+
+// TokenSecretName returns the name of the secret containing the tokens
+func (in *GrafanaServiceAccount) TokenSecretName() string {
+	if in.Spec.SecretName != "" {
+		return in.Spec.SecretName
+	}
+	return in.Name + "-grafana-token"
+}
@@ -0,0 +1,66 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const GrafanaTeamKind = "GrafanaTeam"
+
+// GrafanaTeamSpec defines the desired state of GrafanaTeam
+// +k8s:openapi-gen=true
+type GrafanaTeamSpec struct {
+	// Name of the team in grafana, defaults to the name of the resource
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	// Name of the grafana organization of the team, the default organization
+	// if empty
+	Organization string `json:"organization,omitempty"`
+	// Logins or emails of the members. Members not listed are removed
+	Members []string `json:"members,omitempty"`
+	// Groups of an external auth provider synced to the team. Requires grafana
+	// enterprise, the groups are left unchanged if the list is empty
+	ExternalGroups []string `json:"externalGroups,omitempty"`
+}
+
+// GrafanaTeamStatus defines the observed state of GrafanaTeam
+// +k8s:openapi-gen=true
+type GrafanaTeamStatus struct {
+	Phase      StatusPhase `json:"phase"`
+	Message    string      `json:"message"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	// The id of the team in every grafana instance
+	Instances []GrafanaInstanceStatus `json:"instances,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GrafanaTeam is the Schema for the grafanateams API
+// +k8s:openapi-gen=true
+type GrafanaTeam struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaTeamSpec   `json:"spec,omitempty"`
+	Status GrafanaTeamStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GrafanaTeamList contains a list of GrafanaTeam
+type GrafanaTeamList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaTeam `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaTeam{}, &GrafanaTeamList{})
+}
+
+// TeamName returns the name of the team in grafana
+func (in *GrafanaTeam) TeamName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
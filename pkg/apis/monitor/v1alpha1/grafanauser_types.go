@@ -0,0 +1,74 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const GrafanaUserKind = "GrafanaUser"
+
+// GrafanaUserSpec defines the desired state of GrafanaUser
+// +k8s:openapi-gen=true
+type GrafanaUserSpec struct {
+	// Login of the user in grafana, defaults to the name of the resource
+	Login string `json:"login,omitempty"`
+	Email string `json:"email,omitempty"`
+	Name  string `json:"name,omitempty"`
+	// Secret key containing the initial password. The password is set again
+	// when the secret changes
+	PasswordRef *v1.SecretKeySelector `json:"passwordRef"`
+	// Roles of the user in organizations
+	Organizations []GrafanaUserOrganization `json:"organizations,omitempty"`
+	// Grants the permissions of a grafana server admin
+	GrafanaAdmin bool `json:"grafanaAdmin,omitempty"`
+}
+
+type GrafanaUserOrganization struct {
+	// Name of the organization, the default organization if empty
+	Name string `json:"name,omitempty"`
+	// Viewer, Editor or Admin
+	Role string `json:"role"`
+}
+
+// GrafanaUserStatus defines the observed state of GrafanaUser
+// +k8s:openapi-gen=true
+type GrafanaUserStatus struct {
+	Phase      StatusPhase `json:"phase"`
+	Message    string      `json:"message"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	// The id of the user in every grafana instance
+	Instances []GrafanaInstanceStatus `json:"instances,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GrafanaUser is the Schema for the grafanausers API
+// +k8s:openapi-gen=true
+type GrafanaUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaUserSpec   `json:"spec,omitempty"`
+	Status GrafanaUserStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GrafanaUserList contains a list of GrafanaUser
+type GrafanaUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaUser{}, &GrafanaUserList{})
+}
+
+// UserLogin returns the login of the user in grafana
+func (in *GrafanaUser) UserLogin() string {
+	if in.Spec.Login != "" {
+		return in.Spec.Login
+	}
+	return in.Name
+}
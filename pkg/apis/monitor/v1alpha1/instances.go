@@ -0,0 +1,21 @@
+package v1alpha1
+
+// SetInstanceStatus adds the sync state of a grafana instance or replaces the
+// existing state of the same instance
+func SetInstanceStatus(instances *[]GrafanaInstanceStatus, status GrafanaInstanceStatus) {
+	if existing := FindInstanceStatus(*instances, status.Namespace, status.Name); existing != nil {
+		*existing = status
+		return
+	}
+	*instances = append(*instances, status)
+}
+
+// FindInstanceStatus returns the sync state of the given grafana instance or nil
+func FindInstanceStatus(instances []GrafanaInstanceStatus, namespace, name string) *GrafanaInstanceStatus {
+	for i := range instances {
+		if instances[i].Namespace == namespace && instances[i].Name == name {
+			return &instances[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Annotation allowing a user or team to take over an object that already exists
+// in grafana and was not created by the operator
+const AdoptAnnotation = "monitor.kun/adopt"
+
+// Adopts returns true if the resource may take over an existing object
+func Adopts(obj metav1.Object) bool {
+	return obj.GetAnnotations()[AdoptAnnotation] == "true"
+}
+
+// SyncedResource is a resource that is created in every matching grafana
+// instance. Its status has a phase, a message and the Synced and Ready conditions
+type SyncedResource interface {
+	runtime.Object
+	metav1.Object
+	SetSyncStatus(phase StatusPhase, message string, err error)
+}
+
+func setSyncStatus(phase *StatusPhase, message *string, conditions *[]Condition, generation int64, newPhase StatusPhase, newMessage string, err error) {
+	*phase = newPhase
+	*message = newMessage
+	SetSyncedCondition(conditions, generation, err)
+	SetReadyCondition(conditions, generation, ConditionSynced)
+}
+
+func (in *GrafanaOrganization) SetSyncStatus(phase StatusPhase, message string, err error) {
+	setSyncStatus(&in.Status.Phase, &in.Status.Message, &in.Status.Conditions, in.Generation, phase, message, err)
+}
+
+func (in *GrafanaTeam) SetSyncStatus(phase StatusPhase, message string, err error) {
+	setSyncStatus(&in.Status.Phase, &in.Status.Message, &in.Status.Conditions, in.Generation, phase, message, err)
+}
+
+func (in *GrafanaUser) SetSyncStatus(phase StatusPhase, message string, err error) {
+	setSyncStatus(&in.Status.Phase, &in.Status.Message, &in.Status.Conditions, in.Generation, phase, message, err)
+}
+
+func (in *GrafanaServiceAccount) SetSyncStatus(phase StatusPhase, message string, err error) {
+	setSyncStatus(&in.Status.Phase, &in.Status.Message, &in.Status.Conditions, in.Generation, phase, message, err)
+}
@@ -2,12 +2,73 @@ package v1alpha1
 
 import (
 	"fmt"
+	"reflect"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
+const (
+	DefaultReplicas             int32 = 1
+	DefaultClientTimeoutSeconds       = 5
+)
+
+func (in *Grafana) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		Complete()
+}
+
+var _ webhook.Defaulter = &Grafana{}
+
+func (in *Grafana) Default() {
+	if in.Spec.Deployment == nil {
+		in.Spec.Deployment = &GrafanaDeployment{}
+	}
+	if in.Spec.Deployment.Replicas <= 0 {
+		in.Spec.Deployment.Replicas = DefaultReplicas
+	}
+
+	if in.Spec.Client == nil {
+		in.Spec.Client = &GrafanaClient{}
+	}
+	if in.Spec.Client.TimeoutSeconds == nil {
+		timeout := DefaultClientTimeoutSeconds
+		in.Spec.Client.TimeoutSeconds = &timeout
+	}
+
+	if in.Spec.Service != nil {
+		if in.Spec.Service.Type == "" {
+			in.Spec.Service.Type = v1.ServiceTypeClusterIP
+		}
+		for i, port := range in.Spec.Service.Ports {
+			if port.Protocol == "" {
+				in.Spec.Service.Ports[i].Protocol = v1.ProtocolTCP
+			}
+			if port.TargetPort.IntVal == 0 && port.TargetPort.StrVal == "" {
+				in.Spec.Service.Ports[i].TargetPort = intstr.FromInt(int(port.Port))
+			}
+		}
+	}
+}
+
+var _ webhook.Validator = &Grafana{}
+
+func (in *Grafana) ValidateCreate() error {
+	return in.Validate()
+}
+
+func (in *Grafana) ValidateUpdate(old runtime.Object) error {
+	return in.Validate()
+}
+
+func (in *Grafana) ValidateDelete() error {
+	return nil
+}
+
 func (in *GrafanaDashboard) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(in).
@@ -17,17 +78,26 @@ func (in *GrafanaDashboard) SetupWebhookWithManager(mgr ctrl.Manager) error {
 var _ webhook.Validator = &GrafanaDashboard{}
 
 func (in *GrafanaDashboard) ValidateCreate() error {
-	return nil
+	return in.Validate()
 }
 
+// Updates of the metadata are always allowed so that dashboards that became
+// invalid can still be deleted. The organization can't be changed, the dashboard
+// would not be deleted from the previous one
 func (in *GrafanaDashboard) ValidateUpdate(old runtime.Object) error {
 	oldObj, ok := old.(*GrafanaDashboard)
 	if ok {
 		if in.Hash() != oldObj.Hash() {
 			return fmt.Errorf("GrafanaDashboard' Spec do not allowed changes")
 		}
+		if in.Spec.Organization != oldObj.Spec.Organization {
+			return fmt.Errorf("GrafanaDashboard' Spec do not allowed changes of the organization, recreate the dashboard instead")
+		}
+		if reflect.DeepEqual(in.Spec, oldObj.Spec) {
+			return nil
+		}
 	}
-	return nil
+	return in.Validate()
 }
 
 func (in *GrafanaDashboard) ValidateDelete() error {
@@ -43,15 +113,18 @@ func (in *GrafanaDataSource) SetupWebhookWithManager(mgr ctrl.Manager) error {
 var _ webhook.Validator = &GrafanaDataSource{}
 
 func (in *GrafanaDataSource) ValidateCreate() error {
-	return nil
+	return in.Validate()
 }
 
 func (in *GrafanaDataSource) ValidateUpdate(old runtime.Object) error {
 	oldObj, ok := old.(*GrafanaDataSource)
-	if ok {
-		if in.Hash() != oldObj.Hash() {
-			return fmt.Errorf("GrafanaDataSource' Spec do not allowed changes")
+	if ok && in.Hash() != oldObj.Hash() {
+		// Datasources can be added to or removed from the list, but existing
+		// datasources are not updated in grafana
+		if name := in.changedDatasource(oldObj); name != "" {
+			return fmt.Errorf("GrafanaDataSource' Spec do not allowed changes of datasource %v", name)
 		}
+		return in.Validate()
 	}
 	return nil
 }
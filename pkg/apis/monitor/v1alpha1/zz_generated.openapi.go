@@ -13,13 +13,25 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Grafana":                 schema_pkg_apis_monitor_v1alpha1_Grafana(ref),
-		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboard":        schema_pkg_apis_monitor_v1alpha1_GrafanaDashboard(ref),
-		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataSource":       schema_pkg_apis_monitor_v1alpha1_GrafanaDataSource(ref),
-		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataSourceSpec":   schema_pkg_apis_monitor_v1alpha1_GrafanaDataSourceSpec(ref),
-		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataSourceStatus": schema_pkg_apis_monitor_v1alpha1_GrafanaDataSourceStatus(ref),
-		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaSpec":             schema_pkg_apis_monitor_v1alpha1_GrafanaSpec(ref),
-		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaStatus":           schema_pkg_apis_monitor_v1alpha1_GrafanaStatus(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Grafana":                     schema_pkg_apis_monitor_v1alpha1_Grafana(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboard":            schema_pkg_apis_monitor_v1alpha1_GrafanaDashboard(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataSource":           schema_pkg_apis_monitor_v1alpha1_GrafanaDataSource(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataSourceSpec":       schema_pkg_apis_monitor_v1alpha1_GrafanaDataSourceSpec(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataSourceStatus":     schema_pkg_apis_monitor_v1alpha1_GrafanaDataSourceStatus(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaOrganization":         schema_pkg_apis_monitor_v1alpha1_GrafanaOrganization(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaOrganizationSpec":     schema_pkg_apis_monitor_v1alpha1_GrafanaOrganizationSpec(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaOrganizationStatus":   schema_pkg_apis_monitor_v1alpha1_GrafanaOrganizationStatus(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaServiceAccount":       schema_pkg_apis_monitor_v1alpha1_GrafanaServiceAccount(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaServiceAccountSpec":   schema_pkg_apis_monitor_v1alpha1_GrafanaServiceAccountSpec(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaServiceAccountStatus": schema_pkg_apis_monitor_v1alpha1_GrafanaServiceAccountStatus(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaSpec":                 schema_pkg_apis_monitor_v1alpha1_GrafanaSpec(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaStatus":               schema_pkg_apis_monitor_v1alpha1_GrafanaStatus(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaTeam":                 schema_pkg_apis_monitor_v1alpha1_GrafanaTeam(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaTeamSpec":             schema_pkg_apis_monitor_v1alpha1_GrafanaTeamSpec(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaTeamStatus":           schema_pkg_apis_monitor_v1alpha1_GrafanaTeamStatus(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaUser":                 schema_pkg_apis_monitor_v1alpha1_GrafanaUser(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaUserSpec":             schema_pkg_apis_monitor_v1alpha1_GrafanaUserSpec(ref),
+		"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaUserStatus":           schema_pkg_apis_monitor_v1alpha1_GrafanaUserStatus(ref),
 	}
 }
 
@@ -98,11 +110,16 @@ func schema_pkg_apis_monitor_v1alpha1_GrafanaDashboard(ref common.ReferenceCallb
 							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboardSpec"),
 						},
 					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboardStatus"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboardSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboardSpec", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboardStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
@@ -160,7 +177,21 @@ func schema_pkg_apis_monitor_v1alpha1_GrafanaDataSourceSpec(ref common.Reference
 					"datasources": {
 						SchemaProps: spec.SchemaProps{
 							Description: "INSERT ADDITIONAL SPEC FIELDS - desired state of cluster Important: Run \"operator-sdk generate k8s\" to regenerate code after modifying this file Add custom validation using kubebuilder tags: https://book.kubebuilder.io/beyond_basics/generating_crd.html",
-							Ref:         ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataSourceFields"),
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataSourceFields"),
+									},
+								},
+							},
+						},
+					},
+					"organization": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the grafana organization of the datasources, the default organization if empty. Overridden by the orgId of a datasource",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
@@ -191,146 +222,274 @@ func schema_pkg_apis_monitor_v1alpha1_GrafanaDataSourceStatus(ref common.Referen
 							Format: "",
 						},
 					},
+					"datasources": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataSourceItemStatus"),
+									},
+								},
+							},
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"phase", "message"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataSourceItemStatus"},
 	}
 }
 
-func schema_pkg_apis_monitor_v1alpha1_GrafanaSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_monitor_v1alpha1_GrafanaOrganization(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "GrafanaSpec defines the desired state of Grafana",
+				Description: "GrafanaOrganization is the Schema for the grafanaorganizations API",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"config": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaConfig"),
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"containers": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("k8s.io/api/core/v1.Container"),
-									},
-								},
-							},
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"dashboardLabelSelector": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
-									},
-								},
-							},
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"datasourceLabelSelector": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
-									},
-								},
-							},
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaOrganizationSpec"),
 						},
 					},
-					"ingress": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaIngress"),
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaOrganizationStatus"),
 						},
 					},
-					"initResources": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaOrganizationSpec", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaOrganizationStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaOrganizationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaOrganizationSpec defines the desired state of GrafanaOrganization",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/api/core/v1.ResourceRequirements"),
+							Description: "Name of the organization in grafana, defaults to the name of the resource",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"secrets": {
+					"preferences": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaOrganizationPreferences"),
+						},
+					},
+					"deletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deletes the organization with all its dashboards, datasources and users when the resource is deleted",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaOrganizationPreferences"},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaOrganizationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaOrganizationStatus defines the observed state of GrafanaOrganization",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"conditions": {
 						SchemaProps: spec.SchemaProps{
 							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Type:   []string{"string"},
-										Format: "",
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition"),
 									},
 								},
 							},
 						},
 					},
-					"configMaps": {
+					"instances": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "The id of the organization in every grafana instance",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Type:   []string{"string"},
-										Format: "",
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaInstanceStatus"),
 									},
 								},
 							},
 						},
 					},
-					"service": {
+				},
+				Required: []string{"phase", "message"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaInstanceStatus"},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaServiceAccount(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaServiceAccount is the Schema for the grafanaserviceaccounts API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaService"),
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"deployment": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDeployment"),
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"resources": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/api/core/v1.ResourceRequirements"),
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"serviceAccount": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaServiceAccount"),
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaServiceAccountSpec"),
 						},
 					},
-					"client": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaClient"),
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaServiceAccountStatus"),
 						},
 					},
-					"dataStorage": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaServiceAccountSpec", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaServiceAccountStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaServiceAccountSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaServiceAccountSpec defines the desired state of GrafanaServiceAccount",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataStorage"),
+							Description: "Name of the service account in grafana, defaults to the name of the resource",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"jsonnet": {
+					"organization": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.JsonnetConfig"),
+							Description: "Name of the grafana organization of the service account, the default organization if empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"role": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Viewer, Editor or Admin, defaults to Viewer",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"disabled": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"secretName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secret the tokens are written to, one key per grafana instance. Defaults to <name>-grafana-token",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"rotationPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tokens older than the period are replaced, e.g. 720h. Tokens are only replaced on demand if empty",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
 				},
-				Required: []string{"config"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaClient", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaConfig", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataStorage", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDeployment", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaIngress", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaService", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaServiceAccount", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.JsonnetConfig", "k8s.io/api/core/v1.Container", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
-func schema_pkg_apis_monitor_v1alpha1_GrafanaStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_monitor_v1alpha1_GrafanaServiceAccountStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "GrafanaStatus defines the observed state of Grafana",
+				Description: "GrafanaServiceAccountStatus defines the observed state of GrafanaServiceAccount",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"phase": {
@@ -345,59 +504,709 @@ func schema_pkg_apis_monitor_v1alpha1_GrafanaStatus(ref common.ReferenceCallback
 							Format: "",
 						},
 					},
-					"dashboards": {
+					"conditions": {
 						SchemaProps: spec.SchemaProps{
 							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboardRef"),
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition"),
 									},
 								},
 							},
 						},
 					},
-					"datasources": {
+					"instances": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "The id of the service account the operator created in every grafana instance, the last sync is the time the token was created",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDatasourceRef"),
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaInstanceStatus"),
 									},
 								},
 							},
 						},
 					},
-					"installedPlugins": {
+					"rotation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value of the rotate annotation the tokens were created for",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"phase", "message"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaInstanceStatus"},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaSpec defines the desired state of Grafana",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"config": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaConfig"),
+						},
+					},
+					"containers": {
 						SchemaProps: spec.SchemaProps{
 							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaPlugin"),
+										Ref: ref("k8s.io/api/core/v1.Container"),
 									},
 								},
 							},
 						},
 					},
-					"failedPlugins": {
+					"dashboardLabelSelector": {
 						SchemaProps: spec.SchemaProps{
 							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaPlugin"),
+										Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
 									},
 								},
 							},
 						},
 					},
-				},
-				Required: []string{"phase", "message", "dashboards", "datasources", "installedPlugins", "failedPlugins"},
+					"datasourceLabelSelector": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+									},
+								},
+							},
+						},
+					},
+					"ingress": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaIngress"),
+						},
+					},
+					"initResources": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/api/core/v1.ResourceRequirements"),
+						},
+					},
+					"secrets": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"configMaps": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"service": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaService"),
+						},
+					},
+					"deployment": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDeployment"),
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/api/core/v1.ResourceRequirements"),
+						},
+					},
+					"serviceAccount": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaServiceAccountConfig"),
+						},
+					},
+					"client": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaClient"),
+						},
+					},
+					"dataStorage": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataStorage"),
+						},
+					},
+					"jsonnet": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.JsonnetConfig"),
+						},
+					},
+					"networkPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaNetworkPolicy"),
+						},
+					},
+					"imageRenderer": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaImageRenderer"),
+						},
+					},
+					"organizationLabelSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Selects the GrafanaOrganization resources created in this instance",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+									},
+								},
+							},
+						},
+					},
+					"tenancy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How the dashboards and datasources of different namespaces are separated",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tenantNamespaceSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Selects the namespaces other than the namespace of the instance whose dashboards and datasources are synced with namespaceOrgs tenancy. Only the namespace of the instance is selected if unset, an empty selector selects all namespaces",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"userLabelSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Select the GrafanaUser and GrafanaTeam resources created in this instance",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+									},
+								},
+							},
+						},
+					},
+					"teamLabelSelector": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+									},
+								},
+							},
+						},
+					},
+					"serviceAccountLabelSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Select the GrafanaServiceAccount resources created in this instance",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"config"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaClient", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaConfig", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDataStorage", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDeployment", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaImageRenderer", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaIngress", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaNetworkPolicy", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaService", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaServiceAccountConfig", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.JsonnetConfig", "k8s.io/api/core/v1.Container", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaStatus defines the observed state of Grafana",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"dashboards": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboardRef"),
+									},
+								},
+							},
+						},
+					},
+					"datasources": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDatasourceRef"),
+									},
+								},
+							},
+						},
+					},
+					"installedPlugins": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaPlugin"),
+									},
+								},
+							},
+						},
+					},
+					"failedPlugins": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaPlugin"),
+									},
+								},
+							},
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"dashboardCount": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"datasourceCount": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"lastSyncTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"orphanedDashboards": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboardRef"),
+									},
+								},
+							},
+						},
+					},
+					"orphanedDatasources": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDatasourceRef"),
+									},
+								},
+							},
+						},
+					},
+					"tenants": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaTenant"),
+									},
+								},
+							},
+						},
+					},
+					"adminPasswordRotation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The value of the rotate admin password annotation that was last handled",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"phase", "message", "dashboards", "datasources", "installedPlugins", "failedPlugins", "dashboardCount", "datasourceCount"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboardRef", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDatasourceRef", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaPlugin", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaTenant", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaTeam(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaTeam is the Schema for the grafanateams API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaTeamSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaTeamStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaTeamSpec", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaTeamStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaTeamSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaTeamSpec defines the desired state of GrafanaTeam",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the team in grafana, defaults to the name of the resource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"email": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"organization": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the grafana organization of the team, the default organization if empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"members": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Logins or emails of the members. Members not listed are removed",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"externalGroups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Groups of an external auth provider synced to the team. Requires grafana enterprise, the groups are left unchanged if the list is empty",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaTeamStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaTeamStatus defines the observed state of GrafanaTeam",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"instances": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The id of the team in every grafana instance",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaInstanceStatus"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"phase", "message"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaInstanceStatus"},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaUser(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaUser is the Schema for the grafanausers API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaUserSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaUserStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaUserSpec", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaUserStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaUserSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaUserSpec defines the desired state of GrafanaUser",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"login": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Login of the user in grafana, defaults to the name of the resource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"email": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"passwordRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secret key containing the initial password. The password is set again when the secret changes",
+							Ref:         ref("k8s.io/api/core/v1.SecretKeySelector"),
+						},
+					},
+					"organizations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Roles of the user in organizations",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaUserOrganization"),
+									},
+								},
+							},
+						},
+					},
+					"grafanaAdmin": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Grants the permissions of a grafana server admin",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"passwordRef"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaUserOrganization", "k8s.io/api/core/v1.SecretKeySelector"},
+	}
+}
+
+func schema_pkg_apis_monitor_v1alpha1_GrafanaUserStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GrafanaUserStatus defines the observed state of GrafanaUser",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"instances": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The id of the user in every grafana instance",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaInstanceStatus"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"phase", "message"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDashboardRef", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaDatasourceRef", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaPlugin"},
+			"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.Condition", "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1.GrafanaInstanceStatus"},
 	}
 }
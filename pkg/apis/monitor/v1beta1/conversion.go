@@ -0,0 +1,159 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var numberedHttpHeaderName = regexp.MustCompile(`^httpHeaderName(\d+)$`)
+
+// ConvertTo converts this GrafanaDataSource to the hub version (v1alpha1)
+func (in *GrafanaDataSource) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1alpha1.GrafanaDataSource)
+	dst.ObjectMeta = in.ObjectMeta
+	in.Status.DeepCopyInto(&dst.Status)
+	dst.Spec.Organization = in.Spec.Organization
+
+	dst.Spec.Datasources = make(v1alpha1.GrafanaDataSourceFieldsList, len(in.Spec.Datasources))
+	for i := range in.Spec.Datasources {
+		if err := in.Spec.Datasources[i].convertTo(&dst.Spec.Datasources[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (in *GrafanaDataSourceFields) convertTo(fields *v1alpha1.GrafanaDataSourceFields) error {
+	src := in.DeepCopy()
+	fields.Name = src.Name
+	fields.Uid = src.Uid
+	fields.Type = src.Type
+	fields.Access = src.Access
+	fields.OrgId = src.OrgId
+	fields.Url = src.Url
+	fields.Password = src.Password
+	fields.PasswordRef = src.PasswordRef
+	fields.User = src.User
+	fields.Database = src.Database
+	fields.BasicAuth = src.BasicAuth
+	fields.BasicAuthUser = src.BasicAuthUser
+	fields.BasicAuthPassword = src.BasicAuthPassword
+	fields.BasicAuthPasswordRef = src.BasicAuthPasswordRef
+	fields.HttpHeaders = src.HttpHeaders
+	fields.WithCredentials = src.WithCredentials
+	fields.IsDefault = src.IsDefault
+	fields.Version = src.Version
+	fields.Editable = src.Editable
+
+	fields.JsonData = v1alpha1.GrafanaDataSourceJsonData{}
+	if err := convertJson(src.JsonData, &fields.JsonData); err != nil {
+		return err
+	}
+
+	fields.SecureJsonData = v1alpha1.GrafanaDataSourceSecureJsonData{}
+	return convertJson(src.SecureJsonData, &fields.SecureJsonData)
+}
+
+// ConvertFrom converts from the hub version (v1alpha1) to this version
+func (in *GrafanaDataSource) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1alpha1.GrafanaDataSource)
+	in.ObjectMeta = src.ObjectMeta
+	src.Status.DeepCopyInto(&in.Status)
+	in.Spec.Organization = src.Spec.Organization
+
+	in.Spec.Datasources = make([]GrafanaDataSourceFields, len(src.Spec.Datasources))
+	for i := range src.Spec.Datasources {
+		if err := in.Spec.Datasources[i].convertFrom(&src.Spec.Datasources[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (in *GrafanaDataSourceFields) convertFrom(src *v1alpha1.GrafanaDataSourceFields) error {
+	fields := src.DeepCopy()
+	*in = GrafanaDataSourceFields{
+		Name:                 fields.Name,
+		Uid:                  fields.Uid,
+		Type:                 fields.Type,
+		Access:               fields.Access,
+		OrgId:                fields.OrgId,
+		Url:                  fields.Url,
+		Password:             fields.Password,
+		PasswordRef:          fields.PasswordRef,
+		User:                 fields.User,
+		Database:             fields.Database,
+		BasicAuth:            fields.BasicAuth,
+		BasicAuthUser:        fields.BasicAuthUser,
+		BasicAuthPassword:    fields.BasicAuthPassword,
+		BasicAuthPasswordRef: fields.BasicAuthPasswordRef,
+		WithCredentials:      fields.WithCredentials,
+		IsDefault:            fields.IsDefault,
+		Version:              fields.Version,
+		Editable:             fields.Editable,
+	}
+
+	if err := convertJson(fields.JsonData, &in.JsonData); err != nil {
+		return err
+	}
+
+	if err := convertJson(fields.SecureJsonData, &in.SecureJsonData); err != nil {
+		return err
+	}
+
+	in.HttpHeaders = append(in.extractNumberedHttpHeaders(), fields.HttpHeaders...)
+	return nil
+}
+
+// The json options only differ in the numbered http header fields of v1alpha1,
+// all other fields share the same json names
+func convertJson(src interface{}, dst interface{}) error {
+	bytes, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, dst)
+}
+
+// The numbered httpHeaderName and httpHeaderValue fields of v1alpha1 end up in
+// the extra json options and are moved to the header list
+func (in *GrafanaDataSourceFields) extractNumberedHttpHeaders() []GrafanaDataSourceHttpHeader {
+	var indexes []int
+	for key := range in.JsonData.Extra {
+		match := numberedHttpHeaderName.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+		index, _ := strconv.Atoi(match[1])
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	var headers []GrafanaDataSourceHttpHeader
+	for _, index := range indexes {
+		nameKey := fmt.Sprintf("httpHeaderName%d", index)
+		valueKey := fmt.Sprintf("httpHeaderValue%d", index)
+
+		header := GrafanaDataSourceHttpHeader{}
+		header.Name, _ = in.JsonData.Extra[nameKey].(string)
+		header.Value, _ = in.SecureJsonData.Extra[valueKey].(string)
+		headers = append(headers, header)
+
+		delete(in.JsonData.Extra, nameKey)
+		delete(in.SecureJsonData.Extra, valueKey)
+	}
+
+	if len(in.JsonData.Extra) == 0 {
+		in.JsonData.Extra = nil
+	}
+	if len(in.SecureJsonData.Extra) == 0 {
+		in.SecureJsonData.Extra = nil
+	}
+	return headers
+}
@@ -0,0 +1,98 @@
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+func TestIsConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, obj := range []runtime.Object{&GrafanaDataSource{}} {
+		ok, err := conversion.IsConvertible(scheme, obj)
+		if err != nil || !ok {
+			t.Errorf("%T is not convertible: %v", obj, err)
+		}
+	}
+}
+
+func TestGrafanaDataSourceConversion(t *testing.T) {
+	ds := &GrafanaDataSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "grafana"},
+		Spec: GrafanaDataSourceSpec{
+			Datasources: []GrafanaDataSourceFields{
+				{
+					Name:   "prometheus",
+					Type:   "prometheus",
+					Access: "proxy",
+					Url:    "http://prometheus:9090",
+					BasicAuthPasswordRef: &v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "prometheus"},
+						Key:                  "password",
+					},
+					JsonData: GrafanaDataSourceJsonData{
+						TimeInterval: "5s",
+						Extra: map[string]interface{}{
+							"httpMethod": "POST",
+						},
+					},
+					SecureJsonData: GrafanaDataSourceSecureJsonData{
+						TlsClientKey: "key",
+					},
+					HttpHeaders: []GrafanaDataSourceHttpHeader{
+						{Name: "X-Scope-OrgID", Value: "team-a"},
+						{Name: "X-Custom", Value: "value"},
+					},
+				},
+				{Name: "loki", Uid: "loki", Type: "loki"},
+			},
+		},
+	}
+
+	hub := &v1alpha1.GrafanaDataSource{}
+	if err := ds.ConvertTo(hub); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hub.Spec.Datasources) != 2 || hub.Spec.Datasources[1].Uid != "loki" {
+		t.Errorf("datasource list not converted: %+v", hub.Spec.Datasources)
+	}
+
+	fields := hub.Spec.Datasources[0]
+	if len(fields.HttpHeaders) != 2 || fields.HttpHeaders[0].Name != "X-Scope-OrgID" {
+		t.Errorf("http headers not converted: %+v", fields)
+	}
+	if fields.JsonData.TimeInterval != "5s" || fields.SecureJsonData.TlsClientKey != "key" {
+		t.Errorf("json data not converted: %+v", fields)
+	}
+
+	converted := &GrafanaDataSource{}
+	if err := converted.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ds, converted) {
+		t.Errorf("conversion is not lossless:\n%+v\n%+v", ds, converted)
+	}
+
+	// Numbered headers of v1alpha1 are added to the header list
+	hub.Spec.Datasources[0].JsonData.HTTPHeaderName1 = "X-Legacy"
+	hub.Spec.Datasources[0].SecureJsonData.HTTPHeaderValue1 = "legacy"
+	if err := converted.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if len(converted.Spec.Datasources[0].HttpHeaders) != 3 || converted.Spec.Datasources[0].HttpHeaders[0].Value != "legacy" {
+		t.Errorf("numbered http headers not converted: %+v", converted.Spec.Datasources[0].HttpHeaders)
+	}
+}
@@ -0,0 +1,29 @@
+package v1beta1
+
+import (
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+)
+
+func (in GrafanaDataSourceJsonData) MarshalJSON() ([]byte, error) {
+	type typed GrafanaDataSourceJsonData
+	return v1alpha1.MarshalJsonWithExtra(typed(in), in.Extra)
+}
+
+func (in *GrafanaDataSourceJsonData) UnmarshalJSON(data []byte) error {
+	type typed GrafanaDataSourceJsonData
+	extra, err := v1alpha1.UnmarshalJsonWithExtra(data, (*typed)(in))
+	in.Extra = extra
+	return err
+}
+
+func (in GrafanaDataSourceSecureJsonData) MarshalJSON() ([]byte, error) {
+	type typed GrafanaDataSourceSecureJsonData
+	return v1alpha1.MarshalJsonWithExtra(typed(in), in.Extra)
+}
+
+func (in *GrafanaDataSourceSecureJsonData) UnmarshalJSON(data []byte) error {
+	type typed GrafanaDataSourceSecureJsonData
+	extra, err := v1alpha1.UnmarshalJsonWithExtra(data, (*typed)(in))
+	in.Extra = extra
+	return err
+}
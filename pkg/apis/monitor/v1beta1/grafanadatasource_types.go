@@ -0,0 +1,133 @@
+package v1beta1
+
+import (
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrafanaDataSourceSpec defines the desired state of GrafanaDataSource
+// +k8s:openapi-gen=true
+type GrafanaDataSourceSpec struct {
+	Datasources  []GrafanaDataSourceFields `json:"datasources"`
+	Organization string                    `json:"organization,omitempty"`
+}
+
+// Unlike v1alpha1 custom http headers can only be set as a list
+type GrafanaDataSourceFields struct {
+	Name                 string                          `json:"name"`
+	Uid                  string                          `json:"uid,omitempty"`
+	Type                 string                          `json:"type"`
+	Access               string                          `json:"access,omitempty"`
+	OrgId                int                             `json:"orgId,omitempty"`
+	Url                  string                          `json:"url,omitempty"`
+	Password             string                          `json:"password,omitempty"`
+	PasswordRef          *v1.SecretKeySelector           `json:"passwordRef,omitempty"`
+	User                 string                          `json:"user,omitempty"`
+	Database             string                          `json:"database,omitempty"`
+	BasicAuth            bool                            `json:"basicAuth,omitempty"`
+	BasicAuthUser        string                          `json:"basicAuthUser,omitempty"`
+	BasicAuthPassword    string                          `json:"basicAuthPassword,omitempty"`
+	BasicAuthPasswordRef *v1.SecretKeySelector           `json:"basicAuthPasswordRef,omitempty"`
+	WithCredentials      bool                            `json:"withCredentials,omitempty"`
+	IsDefault            bool                            `json:"isDefault,omitempty"`
+	JsonData             GrafanaDataSourceJsonData       `json:"jsonData,omitempty"`
+	SecureJsonData       GrafanaDataSourceSecureJsonData `json:"secureJsonData,omitempty"`
+	HttpHeaders          []GrafanaDataSourceHttpHeader   `json:"httpHeaders,omitempty"`
+	Version              int                             `json:"version,omitempty"`
+	Editable             bool                            `json:"editable,omitempty"`
+}
+
+// Custom HTTP headers are unchanged from v1alpha1
+type GrafanaDataSourceHttpHeader = v1alpha1.GrafanaDataSourceHttpHeader
+type GrafanaDataSourceHttpHeaderSource = v1alpha1.GrafanaDataSourceHttpHeaderSource
+
+// The datasource status is unchanged from v1alpha1
+type GrafanaDataSourceStatus = v1alpha1.GrafanaDataSourceStatus
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GrafanaDataSource is the Schema for the grafanadatasources API
+// +k8s:openapi-gen=true
+type GrafanaDataSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaDataSourceSpec   `json:"spec,omitempty"`
+	Status GrafanaDataSourceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GrafanaDataSourceList contains a list of GrafanaDataSource
+type GrafanaDataSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrafanaDataSource `json:"items"`
+}
+
+// The most common json options
+// See https://grafana.com/docs/administration/provisioning/#datasources
+type GrafanaDataSourceJsonData struct {
+	TlsAuth                 bool   `json:"tlsAuth,omitempty"`
+	TlsAuthWithCACert       bool   `json:"tlsAuthWithCACert,omitempty"`
+	TlsSkipVerify           bool   `json:"tlsSkipVerify,omitempty"`
+	GraphiteVersion         string `json:"graphiteVersion,omitempty"`
+	TimeInterval            string `json:"timeInterval,omitempty"`
+	EsVersion               int    `json:"esVersion,omitempty"`
+	TimeField               string `json:"timeField,omitempty"`
+	Interval                string `json:"interval,omitempty"`
+	LogMessageField         string `json:"logMessageField,omitempty"`
+	LogLevelField           string `json:"logLevelField,omitempty"`
+	AuthType                string `json:"authType,omitempty"`
+	AssumeRoleArn           string `json:"assumeRoleArn,omitempty"`
+	DefaultRegion           string `json:"defaultRegion,omitempty"`
+	CustomMetricsNamespaces string `json:"customMetricsNamespaces,omitempty"`
+	TsdbVersion             string `json:"tsdbVersion,omitempty"`
+	TsdbResolution          string `json:"tsdbResolution,omitempty"`
+	Sslmode                 string `json:"sslmode,omitempty"`
+	Encrypt                 string `json:"encrypt,omitempty"`
+	PostgresVersion         int    `json:"postgresVersion,omitempty"`
+	Timescaledb             bool   `json:"timescaledb,omitempty"`
+	MaxOpenConns            int    `json:"maxOpenConns,omitempty"`
+	MaxIdleConns            int    `json:"maxIdleConns,omitempty"`
+	ConnMaxLifetime         int    `json:"connMaxLifetime,omitempty"`
+	//  Usefull fields for clickhouse datasource
+	//  See https://github.com/Vertamedia/clickhouse-grafana/tree/master/dist/README.md#configure-the-datasource-with-provisioning
+	//  See https://github.com/Vertamedia/clickhouse-grafana/tree/master/src/datasource.ts#L44
+	AddCorsHeader               bool   `json:"addCorsHeader,omitempty"`
+	DefaultDatabase             string `json:"defaultDatabase,omitempty"`
+	UsePOST                     bool   `json:"usePOST,omitempty"`
+	UseYandexCloudAuthorization bool   `json:"useYandexCloudAuthorization,omitempty"`
+	XHeaderUser                 string `json:"xHeaderUser,omitempty"`
+	XHeaderKey                  string `json:"xHeaderKey,omitempty"`
+	// Fields for Stackdriver data sources
+	TokenUri           string `json:"tokenUri,omitempty"`
+	ClientEmail        string `json:"clientEmail,omitempty"`
+	AuthenticationType string `json:"authenticationType,omitempty"`
+	DefaultProject     string `json:"defaultProject,omitempty"`
+	// Options without a typed field, e.g. of datasource plugins. Merged with
+	// the typed fields when serialized
+	Extra map[string]interface{} `json:"-"`
+}
+
+// The most common secure json options
+// See https://grafana.com/docs/administration/provisioning/#datasources
+type GrafanaDataSourceSecureJsonData struct {
+	TlsCaCert         string `json:"tlsCACert,omitempty"`
+	TlsClientCert     string `json:"tlsClientCert,omitempty"`
+	TlsClientKey      string `json:"tlsClientKey,omitempty"`
+	Password          string `json:"password,omitempty"`
+	BasicAuthPassword string `json:"basicAuthPassword,omitempty"`
+	AccessKey         string `json:"accessKey,omitempty"`
+	SecretKey         string `json:"secretKey,omitempty"`
+	// Fields for Stackdriver data sources
+	PrivateKey string `json:"privateKey,omitempty"`
+	// Options without a typed field, e.g. of datasource plugins. Merged with
+	// the typed fields when serialized
+	Extra map[string]interface{} `json:"-"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrafanaDataSource{}, &GrafanaDataSourceList{})
+}
@@ -0,0 +1,17 @@
+package v1beta1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// Validation and defaulting is done by the v1alpha1 webhooks, v1beta1 resources
+// only register the conversion webhook. Only GrafanaDataSource is served as v1beta1
+
+func (in *GrafanaDataSource) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		Complete()
+}
+
+var _ conversion.Convertible = &GrafanaDataSource{}
@@ -0,0 +1,10 @@
+package controller
+
+import (
+	"github.com/ucloud/grafana-operator/pkg/controller/grafanaorganization"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, grafanaorganization.Add)
+}
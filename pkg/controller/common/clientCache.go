@@ -0,0 +1,98 @@
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+// Successful health checks of an instance are reused for this long, they are
+// repeated when the client is replaced
+const HealthCheckTTL = 10 * time.Second
+
+type cachedClient struct {
+	fingerprint string
+	client      grafanaClient.GrafanaClient
+	// Time of the last successful health check of the client
+	healthy time.Time
+}
+
+// Clients of the grafana instances, shared by all controllers
+type clientCache struct {
+	sync.Mutex
+	clients map[string]cachedClient
+}
+
+var clients = &clientCache{
+	clients: map[string]cachedClient{},
+}
+
+func (c *clientCache) get(cr *grafanav1alpha1.Grafana, url, username, password string, options grafanaClient.ClientOptions) grafanaClient.GrafanaClient {
+	key := fmt.Sprintf("%v/%v", cr.Namespace, cr.Name)
+	fingerprint := fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%v\x00%v\x00%v\x00%+v", url, username, password, options))))
+
+	c.Lock()
+	defer c.Unlock()
+	if cached, ok := c.clients[key]; ok {
+		if cached.fingerprint == fingerprint {
+			return cached.client
+		}
+		closeIdleConnections(cached.client)
+	}
+
+	client := grafanaClient.NewGrafanaClient(url, username, password, options)
+	c.clients[key] = cachedClient{
+		fingerprint: fingerprint,
+		client:      client,
+	}
+	return client
+}
+
+// Checks the health of the grafana api unless the last check of the cached client
+// of the instance succeeded within the health check TTL. Failed checks are
+// not cached
+func (c *clientCache) checkHealth(ctx context.Context, cr *grafanav1alpha1.Grafana, client grafanaClient.GrafanaClient) error {
+	key := fmt.Sprintf("%v/%v", cr.Namespace, cr.Name)
+
+	c.Lock()
+	cached, ok := c.clients[key]
+	c.Unlock()
+	if ok && cached.client == client && time.Since(cached.healthy) < HealthCheckTTL {
+		return nil
+	}
+
+	if err := client.CheckGrafanaHealth(ctx); err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if cached, ok := c.clients[key]; ok && cached.client == client {
+		cached.healthy = time.Now()
+		c.clients[key] = cached
+	}
+	return nil
+}
+
+// ForgetGrafanaClient removes the client of a deleted grafana instance
+func ForgetGrafanaClient(namespace, name string) {
+	clients.Lock()
+	defer clients.Unlock()
+
+	key := fmt.Sprintf("%v/%v", namespace, name)
+	if cached, ok := clients.clients[key]; ok {
+		closeIdleConnections(cached.client)
+		delete(clients.clients, key)
+	}
+}
+
+func closeIdleConnections(client grafanaClient.GrafanaClient) {
+	if closer, ok := client.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,110 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+func TestClientCache(t *testing.T) {
+	cache := &clientCache{
+		clients: map[string]cachedClient{},
+	}
+	graf := &grafanav1alpha1.Grafana{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana"}}
+	other := &grafanav1alpha1.Grafana{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "other"}}
+	options := grafanaClient.ClientOptions{Timeout: time.Second}
+
+	client := cache.get(graf, "http://grafana:3000", "admin", "secret", options)
+	if cache.get(graf, "http://grafana:3000", "admin", "secret", options) != client {
+		t.Error("expected the cached client for unchanged settings")
+	}
+	if cache.get(other, "http://grafana:3000", "admin", "secret", options) == client {
+		t.Error("expected another client for another instance")
+	}
+
+	// Any change of the url, credentials or options replaces the client
+	changed := []func() grafanaClient.GrafanaClient{
+		func() grafanaClient.GrafanaClient {
+			return cache.get(graf, "http://grafana:3001", "admin", "secret", options)
+		},
+		func() grafanaClient.GrafanaClient {
+			return cache.get(graf, "http://grafana:3001", "root", "secret", options)
+		},
+		func() grafanaClient.GrafanaClient {
+			return cache.get(graf, "http://grafana:3001", "root", "rotated", options)
+		},
+		func() grafanaClient.GrafanaClient {
+			return cache.get(graf, "http://grafana:3001", "root", "rotated", grafanaClient.ClientOptions{Timeout: 2 * time.Second})
+		},
+	}
+	for i, get := range changed {
+		previous := client
+		client = get()
+		if client == previous {
+			t.Errorf("expected a new client for change %v", i)
+		}
+	}
+	if len(cache.clients) != 2 {
+		t.Errorf("expected one client per instance, got %v", len(cache.clients))
+	}
+}
+
+func TestClientCacheHealth(t *testing.T) {
+	checks := 0
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		checks++
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"database": "ok"}`))
+	}))
+	defer server.Close()
+
+	cache := &clientCache{
+		clients: map[string]cachedClient{},
+	}
+	graf := &grafanav1alpha1.Grafana{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana"}}
+	options := grafanaClient.ClientOptions{Timeout: time.Second}
+	ctx := context.Background()
+
+	// Failed checks are repeated
+	healthy = false
+	client := cache.get(graf, server.URL, "admin", "secret", options)
+	for i := 0; i < 2; i++ {
+		if err := cache.checkHealth(ctx, graf, client); err == nil {
+			t.Error("expected an error for an unhealthy instance")
+		}
+	}
+	if checks != 2 {
+		t.Errorf("expected two health checks, got %v", checks)
+	}
+
+	// Successful checks are reused until the client is replaced
+	healthy = true
+	checks = 0
+	for i := 0; i < 2; i++ {
+		if err := cache.checkHealth(ctx, graf, client); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if checks != 1 {
+		t.Errorf("expected one health check, got %v", checks)
+	}
+
+	client = cache.get(graf, server.URL, "admin", "rotated", options)
+	if err := cache.checkHealth(ctx, graf, client); err != nil {
+		t.Fatal(err)
+	}
+	if checks != 2 {
+		t.Errorf("expected another health check for the new client, got %v", checks)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-logr/logr"
 	v13 "github.com/openshift/api/route/v1"
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
 	v12 "k8s.io/api/apps/v1"
 	"k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -19,6 +20,7 @@ import (
 
 type ActionRunner interface {
 	RunAll(desiredState DesiredClusterState) error
+	Readiness() map[string]error
 	create(obj runtime.Object) error
 	update(obj runtime.Object) error
 	delete(obj runtime.Object) error
@@ -49,22 +51,48 @@ func (d *DesiredClusterState) AddActions(actions []ClusterAction) DesiredCluster
 	return *d
 }
 
+// Returned by the readiness actions if the checked resource is not ready
+type NotReadyError struct {
+	Condition string
+	Err       error
+}
+
+func (e *NotReadyError) Error() string {
+	return e.Err.Error()
+}
+
 type ClusterActionRunner struct {
-	scheme *runtime.Scheme
-	client client.Client
-	ctx    context.Context
-	log    logr.Logger
-	cr     runtime.Object
+	scheme    *runtime.Scheme
+	client    client.Client
+	ctx       context.Context
+	log       logr.Logger
+	cr        runtime.Object
+	readiness map[string]error
 }
 
 func NewClusterActionRunner(ctx context.Context, client client.Client, scheme *runtime.Scheme, cr runtime.Object) ActionRunner {
 	return &ClusterActionRunner{
-		scheme: scheme,
-		client: client,
-		log:    logf.Log.WithName("action-runner"),
-		ctx:    ctx,
-		cr:     cr,
+		scheme:    scheme,
+		client:    client,
+		log:       logf.Log.WithName("action-runner"),
+		ctx:       ctx,
+		cr:        cr,
+		readiness: map[string]error{},
+	}
+}
+
+// Readiness returns the results of the readiness actions that have been run,
+// by the condition type they determine
+func (i *ClusterActionRunner) Readiness() map[string]error {
+	return i.readiness
+}
+
+func (i *ClusterActionRunner) setReadiness(condition string, err error) error {
+	if err != nil {
+		err = &NotReadyError{Condition: condition, Err: err}
 	}
+	i.readiness[condition] = err
+	return err
 }
 
 func (i *ClusterActionRunner) RunAll(desiredState DesiredClusterState) error {
@@ -114,29 +142,29 @@ func (i *ClusterActionRunner) delete(obj runtime.Object) error {
 func (i *ClusterActionRunner) routeReady(obj runtime.Object) error {
 	ready := IsRouteReady(obj.(*v13.Route))
 	if !ready {
-		return stdErr.New("route not ready")
+		return i.setReadiness(v1alpha1.ConditionIngressReady, stdErr.New("route not ready"))
 	}
-	return nil
+	return i.setReadiness(v1alpha1.ConditionIngressReady, nil)
 }
 
 func (i *ClusterActionRunner) ingressReady(obj runtime.Object) error {
 	ready := IsIngressReady(obj.(*v1beta1.Ingress))
 	if !ready {
-		return stdErr.New("ingress not ready")
+		return i.setReadiness(v1alpha1.ConditionIngressReady, stdErr.New("ingress not ready"))
 	}
-	return nil
+	return i.setReadiness(v1alpha1.ConditionIngressReady, nil)
 }
 
 func (i *ClusterActionRunner) deploymentReady(obj runtime.Object) error {
 	ready, err := IsDeploymentReady(obj.(*v12.Deployment))
 	if err != nil {
-		return err
+		return i.setReadiness(v1alpha1.ConditionDeploymentAvailable, err)
 	}
 
 	if !ready {
-		return stdErr.New("deployment not ready")
+		return i.setReadiness(v1alpha1.ConditionDeploymentAvailable, stdErr.New("deployment not ready"))
 	}
-	return nil
+	return i.setReadiness(v1alpha1.ConditionDeploymentAvailable, nil)
 }
 
 // An action to create generic kubernetes resources
@@ -2,13 +2,16 @@ package common
 
 import (
 	"context"
+	"fmt"
 
 	v12 "github.com/openshift/api/route/v1"
 	v13 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
@@ -25,6 +28,11 @@ type ClusterState struct {
 	GrafanaIngress                   *v1beta1.Ingress
 	GrafanaDeployment                *v13.Deployment
 	AdminSecret                      *v1.Secret
+	GrafanaNetworkPolicy             *networkingv1.NetworkPolicy
+	ImageRendererDeployment          *v13.Deployment
+	ImageRendererService             *v1.Service
+	ImageRendererSecret              *v1.Secret
+	ConfigSecrets                    map[string]*v1.Secret
 }
 
 func NewClusterState() *ClusterState {
@@ -67,6 +75,21 @@ func (i *ClusterState) Read(ctx context.Context, cr *v1alpha1.Grafana, client cl
 		return err
 	}
 
+	err = i.readGrafanaNetworkPolicy(ctx, cr, client)
+	if err != nil {
+		return err
+	}
+
+	err = i.readImageRenderer(ctx, cr, client)
+	if err != nil {
+		return err
+	}
+
+	err = i.readConfigSecrets(ctx, cr, client)
+	if err != nil {
+		return err
+	}
+
 	if isOpenshift {
 		err = i.readGrafanaRoute(ctx, cr, client)
 	} else {
@@ -76,6 +99,30 @@ func (i *ClusterState) Read(ctx context.Context, cr *v1alpha1.Grafana, client cl
 	return err
 }
 
+// ReadClient only reads the resources needed to access the grafana api: the
+// admin secret and the service, ingress or route the url is taken from
+func (i *ClusterState) ReadClient(ctx context.Context, cr *v1alpha1.Grafana, client client.Client) error {
+	err := i.readGrafanaService(ctx, cr, client)
+	if err != nil {
+		return err
+	}
+
+	err = i.readGrafanaAdminUserSecret(ctx, cr, client)
+	if err != nil {
+		return err
+	}
+
+	if cr.Spec.Client != nil && cr.Spec.Client.PreferService {
+		return nil
+	}
+
+	cfg := config.GetControllerConfig()
+	if cfg.GetConfigBool(config.ConfigOpenshift, false) {
+		return i.readGrafanaRoute(ctx, cr, client)
+	}
+	return i.readGrafanaIngress(ctx, cr, client)
+}
+
 func (i *ClusterState) readGrafanaService(ctx context.Context, cr *v1alpha1.Grafana, client client.Client) error {
 	currentState := &v1.Service{}
 	selector := model.GrafanaServiceSelector(cr)
@@ -190,3 +237,75 @@ func (i *ClusterState) readGrafanaAdminUserSecret(ctx context.Context, cr *v1alp
 	i.AdminSecret = currentState.DeepCopy()
 	return nil
 }
+
+func (i *ClusterState) readGrafanaNetworkPolicy(ctx context.Context, cr *v1alpha1.Grafana, client client.Client) error {
+	currentState := &networkingv1.NetworkPolicy{}
+	selector := model.GrafanaNetworkPolicySelector(cr)
+	err := client.Get(ctx, selector, currentState)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	i.GrafanaNetworkPolicy = currentState.DeepCopy()
+	return nil
+}
+
+func (i *ClusterState) readImageRenderer(ctx context.Context, cr *v1alpha1.Grafana, client client.Client) error {
+	deployment := &v13.Deployment{}
+	err := client.Get(ctx, model.ImageRendererDeploymentSelector(cr), deployment)
+	if err == nil {
+		i.ImageRendererDeployment = deployment.DeepCopy()
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	service := &v1.Service{}
+	err = client.Get(ctx, model.ImageRendererServiceSelector(cr), service)
+	if err == nil {
+		i.ImageRendererService = service.DeepCopy()
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	secret := &v1.Secret{}
+	err = client.Get(ctx, model.ImageRendererSecretSelector(cr), secret)
+	if err == nil {
+		i.ImageRendererSecret = secret.DeepCopy()
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Read the secrets referenced by the grafana config. Their values are hashed to
+// restart grafana when they change
+func (i *ClusterState) readConfigSecrets(ctx context.Context, cr *v1alpha1.Grafana, client client.Client) error {
+	i.ConfigSecrets = map[string]*v1.Secret{}
+
+	for _, ref := range model.GetConfigSecretRefs(cr) {
+		if _, ok := i.ConfigSecrets[ref.Ref.Name]; ok {
+			continue
+		}
+
+		currentState := &v1.Secret{}
+		selector := types.NamespacedName{
+			Namespace: cr.Namespace,
+			Name:      ref.Ref.Name,
+		}
+		err := client.Get(ctx, selector, currentState)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				if ref.Ref.Optional != nil && *ref.Ref.Optional {
+					continue
+				}
+				return fmt.Errorf("secret %v referenced by %v.%v not found", ref.Ref.Name, ref.Section, ref.Key)
+			}
+			return err
+		}
+		i.ConfigSecrets[ref.Ref.Name] = currentState.DeepCopy()
+	}
+	return nil
+}
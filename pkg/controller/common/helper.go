@@ -7,14 +7,14 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
-	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/config"
 	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
 	"github.com/ucloud/grafana-operator/pkg/controller/model"
 )
@@ -26,6 +26,12 @@ type MatchType string
 const (
 	MatchByDashboard  MatchType = "dashboard"
 	MatchByDataSource MatchType = "dataSource"
+	// Matches the organizations, see GrafanaSpec.OrganizationLabelSelector
+	MatchByOrganization MatchType = "organization"
+	MatchByUser         MatchType = "user"
+	MatchByTeam         MatchType = "team"
+	// Matches the service accounts, see GrafanaSpec.ServiceAccountLabelSelector
+	MatchByServiceAccount MatchType = "serviceAccount"
 )
 
 func matchesSelector(l map[string]string, s *metav1.LabelSelector) (bool, error) {
@@ -53,39 +59,72 @@ func MatchesSelectors(l map[string]string, s []*metav1.LabelSelector) (bool, err
 	return result, nil
 }
 
+// SelectsNamespace returns true if the grafana instance syncs the dashboards and
+// datasources of the namespace. Instances with namespaceOrgs tenancy select other
+// namespaces if their labels match the tenant namespace selector
+func SelectsNamespace(ctx context.Context, kubeclient client.Client, cr *grafanav1alpha1.Grafana, namespace string) (bool, error) {
+	if namespace == cr.Namespace {
+		return true, nil
+	}
+	if cr.Spec.Tenancy != grafanav1alpha1.TenancyNamespaceOrgs || cr.Spec.TenantNamespaceSelector == nil {
+		return false, nil
+	}
+
+	ns := &v1.Namespace{}
+	if err := kubeclient.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return matchesSelector(ns.Labels, cr.Spec.TenantNamespaceSelector)
+}
+
+// MatchGrafana returns the grafana instances selecting a resource of the namespace.
+// Instances with namespaceOrgs tenancy select dashboards and datasources of the
+// namespaces matching their tenant namespace selector, see SelectsNamespace
 func MatchGrafana(ctx context.Context, kubeclient client.Client, reqLogger logr.Logger, namespace string, label map[string]string, t MatchType) ([]*grafanav1alpha1.Grafana, error) {
 	foundGrafanas := &grafanav1alpha1.GrafanaList{}
-	err := kubeclient.List(ctx, foundGrafanas, client.InNamespace(namespace))
+	err := kubeclient.List(ctx, foundGrafanas)
 	if err != nil {
 		return nil, err
 	}
 
 	var result []*grafanav1alpha1.Grafana
 	for _, item := range foundGrafanas.Items {
+		crossNamespace := t == MatchByDashboard || t == MatchByDataSource
+		if item.Namespace != namespace && !crossNamespace {
+			continue
+		}
+
 		s := item.Spec.DashboardLabelSelector
-		if t == MatchByDataSource {
+		switch t {
+		case MatchByDataSource:
 			s = item.Spec.DatasourceLabelSelector
+		case MatchByOrganization:
+			s = item.Spec.OrganizationLabelSelector
+		case MatchByUser:
+			s = item.Spec.UserLabelSelector
+		case MatchByTeam:
+			s = item.Spec.TeamLabelSelector
+		case MatchByServiceAccount:
+			s = item.Spec.ServiceAccountLabelSelector
 		}
 		match, err := MatchesSelectors(label, s)
 		if err != nil {
 			return nil, err
 		}
-		if match {
-			grafanaDeployment := &appsv1.Deployment{}
-			if err := kubeclient.Get(ctx, types.NamespacedName{
-				Namespace: namespace,
-				Name:      model.GetGrafanaDeploymentName(&item),
-			}, grafanaDeployment); err != nil {
-				if errors.IsNotFound(err) {
-					continue
-				}
-				return nil, err
-			}
-			if grafanaDeployment.Status.ReadyReplicas != *grafanaDeployment.Spec.Replicas {
-				reqLogger.V(4).Info("grafanaDeployment not ready", "deployment", model.GetGrafanaDeploymentName(&item),
-					"readyReplicas", grafanaDeployment.Status.ReadyReplicas, "expectReplicas", *grafanaDeployment.Spec.Replicas)
-				continue
-			}
+		// Deleted instances are skipped, their dashboards and datasources are
+		// deleted along with them
+		if !match || item.DeletionTimestamp != nil {
+			continue
+		}
+
+		selected, err := SelectsNamespace(ctx, kubeclient, &item, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if selected {
 			result = append(result, item.DeepCopy())
 		}
 	}
@@ -137,7 +176,21 @@ func getGrafanaAdminUrl(cr *grafanav1alpha1.Grafana, state *ClusterState) (strin
 	return "", stdErr.New("failed to find admin url")
 }
 
+func getClientTimeout(cr *grafanav1alpha1.Grafana) time.Duration {
+	if cr.Spec.Client != nil && cr.Spec.Client.TimeoutSeconds != nil && *cr.Spec.Client.TimeoutSeconds > 0 {
+		return time.Duration(*cr.Spec.Client.TimeoutSeconds) * time.Second
+	}
+	return DefaultClientTimeout
+}
+
+// NewGrafanaClient returns the client of the grafana instance. Clients are cached
+// per instance to reuse their connections and are only replaced when the url,
+// the credentials or the client settings change
 func NewGrafanaClient(cr *grafanav1alpha1.Grafana, state *ClusterState) (grafanaClient.GrafanaClient, error) {
+	if state.AdminSecret == nil {
+		return nil, stdErr.New("admin secret not found")
+	}
+
 	username := string(state.AdminSecret.Data[model.GrafanaAdminUserEnvVar])
 	password := string(state.AdminSecret.Data[model.GrafanaAdminPasswordEnvVar])
 	url, err := getGrafanaAdminUrl(cr, state)
@@ -155,5 +208,63 @@ func NewGrafanaClient(cr *grafanav1alpha1.Grafana, state *ClusterState) (grafana
 		return nil, stdErr.New("invalid credentials (password)")
 	}
 
-	return grafanaClient.NewGrafanaClient(url, username, password, DefaultClientTimeout), nil
+	cfg := config.GetControllerConfig()
+	options := grafanaClient.ClientOptions{
+		Timeout:   getClientTimeout(cr),
+		Retries:   cfg.GetConfigItem(config.ConfigGrafanaApiRetries, config.GrafanaApiRetries).(int),
+		RateLimit: cfg.GetConfigItem(config.ConfigGrafanaApiRateLimit, 0.0).(float64),
+		Burst:     cfg.GetConfigItem(config.ConfigGrafanaApiBurst, config.GrafanaApiBurst).(int),
+	}
+
+	return clients.get(cr, url, username, password, options), nil
+}
+
+// NewReadyGrafanaClient reads the resources needed to access the grafana
+// instance and returns its client if the grafana api is healthy. Dashboards and
+// datasources are only synced to healthy instances, the replica counts of the
+// deployment are not taken into account so that rolling updates don't block
+// them. Successful health checks are cached for a short time, see HealthCheckTTL
+func NewReadyGrafanaClient(ctx context.Context, kubeclient client.Client, cr *grafanav1alpha1.Grafana) (grafanaClient.GrafanaClient, error) {
+	state := NewClusterState()
+	if err := state.ReadClient(ctx, cr, kubeclient); err != nil {
+		return nil, err
+	}
+
+	grafana, err := NewGrafanaClient(cr, state)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clients.checkHealth(ctx, cr, grafana); err != nil {
+		return nil, fmt.Errorf("grafana %v is not ready: %v", cr.Name, err)
+	}
+	return grafana, nil
+}
+
+// InstanceStatus returns the sync state of a dashboard or datasource in the
+// given grafana from the response of grafana. Fields missing in the response
+// and the last sync time are kept from the previous state unless synced is true
+func InstanceStatus(cr *grafanav1alpha1.Grafana, response grafanaClient.GrafanaResponse, previous []grafanav1alpha1.GrafanaInstanceStatus, synced bool) grafanav1alpha1.GrafanaInstanceStatus {
+	status := grafanav1alpha1.GrafanaInstanceStatus{
+		Namespace: cr.Namespace,
+		Name:      cr.Name,
+	}
+	if existing := grafanav1alpha1.FindInstanceStatus(previous, cr.Namespace, cr.Name); existing != nil {
+		existing.DeepCopyInto(&status)
+	}
+
+	if response.ID != nil {
+		status.ID = *response.ID
+	}
+	if response.UID != nil {
+		status.UID = *response.UID
+	}
+	if response.Version != nil {
+		status.Version = *response.Version
+	}
+	if synced {
+		now := metav1.Now()
+		status.LastSync = &now
+	}
+	return status
 }
@@ -0,0 +1,88 @@
+package common
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+)
+
+func newMatchGrafana(namespace, name string, tenancy grafanav1alpha1.GrafanaTenancy, selector *metav1.LabelSelector) *grafanav1alpha1.Grafana {
+	return &grafanav1alpha1.Grafana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: grafanav1alpha1.GrafanaSpec{
+			DashboardLabelSelector:  []*metav1.LabelSelector{{MatchLabels: map[string]string{"app": "grafana"}}},
+			UserLabelSelector:       []*metav1.LabelSelector{{MatchLabels: map[string]string{"app": "grafana"}}},
+			Tenancy:                 tenancy,
+			TenantNamespaceSelector: selector,
+		},
+	}
+}
+
+func TestMatchGrafana(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := grafanav1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	tenants := &metav1.LabelSelector{MatchLabels: map[string]string{"grafana": "shared"}}
+	objects := []runtime.Object{
+		newMatchGrafana("monitoring", "shared", grafanav1alpha1.TenancyShared, nil),
+		newMatchGrafana("monitoring", "unselected", grafanav1alpha1.TenancyNamespaceOrgs, nil),
+		newMatchGrafana("monitoring", "selected", grafanav1alpha1.TenancyNamespaceOrgs, tenants),
+		newMatchGrafana("monitoring", "all", grafanav1alpha1.TenancyNamespaceOrgs, &metav1.LabelSelector{}),
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"grafana": "shared"}}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+	}
+	kubeclient := fake.NewFakeClientWithScheme(s, objects...)
+	reqLogger := logf.Log.WithName("test")
+
+	match := func(namespace string, labels map[string]string, matchType MatchType) []string {
+		grafanas, err := MatchGrafana(context.Background(), kubeclient, reqLogger, namespace, labels, matchType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, graf := range grafanas {
+			names = append(names, graf.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	labels := map[string]string{"app": "grafana"}
+	tests := []struct {
+		namespace string
+		labels    map[string]string
+		matchType MatchType
+		expected  []string
+	}{
+		{"monitoring", labels, MatchByDashboard, []string{"all", "selected", "shared", "unselected"}},
+		{"monitoring", map[string]string{"app": "other"}, MatchByDashboard, nil},
+		// Other namespaces are only selected by namespaceOrgs tenancy with a
+		// matching tenant namespace selector
+		{"team-a", labels, MatchByDashboard, []string{"all", "selected"}},
+		{"team-b", labels, MatchByDashboard, []string{"all"}},
+		{"deleted", labels, MatchByDashboard, nil},
+		// Users are never matched across namespaces
+		{"team-a", labels, MatchByUser, nil},
+		{"monitoring", labels, MatchByUser, []string{"all", "selected", "shared", "unselected"}},
+	}
+	for _, test := range tests {
+		if names := match(test.namespace, test.labels, test.matchType); !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("expected %v to match %v in %v, got %v", test.matchType, test.expected, test.namespace, names)
+		}
+	}
+}
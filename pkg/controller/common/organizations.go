@@ -0,0 +1,103 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+	"github.com/ucloud/grafana-operator/pkg/controller/model"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OrganizationNotFoundError is returned if the organization of a resource does
+// not exist in grafana
+type OrganizationNotFoundError struct {
+	Organization string
+}
+
+func (e *OrganizationNotFoundError) Error() string {
+	return fmt.Sprintf("organization %v does not exist", e.Organization)
+}
+
+// OrganizationClient returns a client that acts in the named organization of the
+// grafana instance, or the client itself if no organization is given. The admin
+// user has to be a member of the organization, which is the case for all
+// organizations created from a GrafanaOrganization resource
+func OrganizationClient(ctx context.Context, grafana grafanaClient.GrafanaClient, organization string) (grafanaClient.GrafanaClient, error) {
+	if organization == "" {
+		return grafana, nil
+	}
+
+	org, err := grafana.GetOrganizationByName(ctx, organization)
+	if err != nil {
+		if err == grafanaClient.NotFoundError {
+			return nil, &OrganizationNotFoundError{Organization: organization}
+		}
+		return nil, err
+	}
+	return grafana.WithOrganization(org.ID), nil
+}
+
+// ResourceOrganization returns the name of the organization of a dashboard or
+// datasource in the namespace: the namespace itself for grafana instances with
+// namespaceOrgs tenancy, the organization named in the resource otherwise
+func ResourceOrganization(cr *grafanav1alpha1.Grafana, namespace, organization string) string {
+	if cr.Spec.Tenancy == grafanav1alpha1.TenancyNamespaceOrgs {
+		return namespace
+	}
+	return organization
+}
+
+// ResourceClient returns a client that acts in the organization of a dashboard
+// or datasource, see ResourceOrganization. The organizations of namespaces are
+// only used once the operator created them, see TenantOrganization
+func ResourceClient(ctx context.Context, grafana grafanaClient.GrafanaClient, cr *grafanav1alpha1.Grafana, namespace, organization string) (grafanaClient.GrafanaClient, error) {
+	if cr.Spec.Tenancy != grafanav1alpha1.TenancyNamespaceOrgs {
+		return OrganizationClient(ctx, grafana, organization)
+	}
+
+	orgId := TenantOrgID(cr, namespace)
+	if orgId == 0 {
+		return nil, &OrganizationNotFoundError{Organization: namespace}
+	}
+	return grafana.WithOrganization(orgId), nil
+}
+
+// TenantOrgID returns the id of the organization the operator created for the
+// namespace as recorded in the status of the grafana instance, zero if there is
+// none yet
+func TenantOrgID(cr *grafanav1alpha1.Grafana, namespace string) uint {
+	for _, tenant := range cr.Status.Tenants {
+		if tenant.Namespace == namespace {
+			return tenant.OrgID
+		}
+	}
+	return 0
+}
+
+// TenantOrganization returns the id of the organization of the namespace and
+// creates it unless the operator created it already. Existing organizations
+// named after the namespace are not used, they may belong to someone else
+func TenantOrganization(ctx context.Context, grafana grafanaClient.GrafanaClient, namespace string, orgId uint) (uint, error) {
+	if orgId > 0 {
+		return orgId, nil
+	}
+
+	orgId, err := grafana.CreateOrganization(ctx, namespace)
+	if err == grafanaClient.ConflictError {
+		return 0, fmt.Errorf("organization %v already exists and was not created by the operator", namespace)
+	}
+	return orgId, err
+}
+
+// AdminLogin returns the login of the admin user the operator uses to access the
+// grafana instance
+func AdminLogin(ctx context.Context, kubeclient client.Client, cr *grafanav1alpha1.Grafana) (string, error) {
+	secret := &v1.Secret{}
+	if err := kubeclient.Get(ctx, model.AdminSecretSelector(cr), secret); err != nil {
+		return "", err
+	}
+	return string(secret.Data[model.GrafanaAdminUserEnvVar]), nil
+}
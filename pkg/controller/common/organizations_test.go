@@ -0,0 +1,114 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+func TestResourceClient(t *testing.T) {
+	var orgIds []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/orgs/name/main":
+			w.Write([]byte(`{"id": 3, "name": "main"}`))
+		case "/api/orgs/name/missing", "/api/orgs/name/team-a":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			orgIds = append(orgIds, req.Header.Get("X-Grafana-Org-Id"))
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second})
+	graf := &grafanav1alpha1.Grafana{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana"}}
+
+	// The organization of the resource is looked up by its name
+	if _, err := ResourceClient(ctx, client, graf, "team-a", "missing"); err == nil {
+		t.Error("expected an error for a missing organization")
+	} else if _, ok := err.(*OrganizationNotFoundError); !ok {
+		t.Errorf("expected an organization not found error, got %v", err)
+	}
+
+	resourceClient, err := ResourceClient(ctx, client, graf, "team-a", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resourceClient.ListDashboards(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Namespace organizations are only used once the operator created them
+	graf.Spec.Tenancy = grafanav1alpha1.TenancyNamespaceOrgs
+	if _, err := ResourceClient(ctx, client, graf, "team-a", "main"); err == nil {
+		t.Error("expected an error for a namespace without tenant")
+	} else if _, ok := err.(*OrganizationNotFoundError); !ok {
+		t.Errorf("expected an organization not found error, got %v", err)
+	}
+
+	graf.Status.Tenants = []*grafanav1alpha1.GrafanaTenant{{Namespace: "team-a", OrgID: 7}}
+	resourceClient, err = ResourceClient(ctx, client, graf, "team-a", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resourceClient.ListDashboards(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"3", "7"}; !reflect.DeepEqual(orgIds, expected) {
+		t.Errorf("expected requests in organizations %v, got %v", expected, orgIds)
+	}
+}
+
+func TestTenantOrganization(t *testing.T) {
+	var created []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.URL.Path != "/api/orgs" {
+			t.Errorf("unexpected request %v %v", req.Method, req.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		created = append(created, req.URL.Path)
+		if len(created) > 1 {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"message": "Organization name taken"}`))
+			return
+		}
+		w.Write([]byte(`{"orgId": 5}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second})
+
+	orgId, err := TenantOrganization(ctx, client, "team-a", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orgId != 5 {
+		t.Errorf("expected the created organization 5, got %v", orgId)
+	}
+
+	// Organizations that were created already are used without requests
+	if orgId, err := TenantOrganization(ctx, client, "team-a", 5); err != nil || orgId != 5 {
+		t.Errorf("expected the recorded organization 5, got %v (%v)", orgId, err)
+	}
+
+	// Existing organizations are not adopted
+	if _, err := TenantOrganization(ctx, client, "team-b", 0); err == nil {
+		t.Error("expected an error for an existing organization")
+	}
+	if len(created) != 2 {
+		t.Errorf("expected two create requests, got %v", len(created))
+	}
+}
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"fmt"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+)
+
+// Dashboards and datasources created by the operator are marked with the grafana
+// instance they were created for, so that the ones no longer backed by a custom
+// resource can be told apart from objects created in grafana by other means
+const (
+	ManagedDashboardTagPrefix = "managed-by:"
+	ManagedDatasourceKey      = "managedBy"
+)
+
+// ManagedMarker returns the value that marks the objects the operator creates in
+// the grafana instance, grafana-operator/<namespace>/<name>
+func ManagedMarker(cr *grafanav1alpha1.Grafana) string {
+	return fmt.Sprintf("grafana-operator/%v/%v", cr.Namespace, cr.Name)
+}
+
+// ManagedDashboardTag returns the tag of the dashboards the operator creates in
+// the grafana instance
+func ManagedDashboardTag(cr *grafanav1alpha1.Grafana) string {
+	return ManagedDashboardTagPrefix + ManagedMarker(cr)
+}
+
+// IsManagedDashboard returns true if the dashboard tags contain the tag of the
+// grafana instance
+func IsManagedDashboard(cr *grafanav1alpha1.Grafana, tags []string) bool {
+	for _, tag := range tags {
+		if tag == ManagedDashboardTag(cr) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsManagedDatasource returns true if the json data of the datasource contains
+// the marker of the grafana instance
+func IsManagedDatasource(cr *grafanav1alpha1.Grafana, jsonData map[string]interface{}) bool {
+	return jsonData[ManagedDatasourceKey] == ManagedMarker(cr)
+}
@@ -0,0 +1,133 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
+)
+
+// SyncedResourceReconciler handles the finalizer, the status and the requeueing
+// of resources that are created in every matching grafana instance, like
+// organizations, teams, users and service accounts
+type SyncedResourceReconciler struct {
+	Client   client.Client
+	Context  context.Context
+	Recorder record.EventRecorder
+	Log      logr.Logger
+	// The kind of the resource in metrics and logs, e.g. metrics.KindGrafanaTeam
+	Kind      string
+	Finalizer string
+}
+
+// Reconcile gets the resource of the request into instance. Resources that are
+// being deleted keep the finalizer until finalize succeeded, e.g. deleted them
+// from every matching grafana. Other resources get the finalizer and are passed
+// to sync, which is retried after the requeue delay if it failed and repeated
+// every resync period
+func (r *SyncedResourceReconciler) Reconcile(request reconcile.Request, instance grafanav1alpha1.SyncedResource, sync, finalize func(reqLogger logr.Logger) error) (reconcile.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info(fmt.Sprintf("Reconciling %v", r.Kind))
+
+	err := r.Client.Get(r.Context, request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if instance.GetDeletionTimestamp() != nil {
+		if !containsString(instance.GetFinalizers(), r.Finalizer) {
+			return reconcile.Result{}, nil
+		}
+
+		if err := finalize(reqLogger); err != nil {
+			r.ManageError(instance, err)
+			return reconcile.Result{}, err
+		}
+
+		instance.SetFinalizers(removeString(instance.GetFinalizers(), r.Finalizer))
+		if err := r.Client.Update(r.Context, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+		metrics.ForgetResource(r.Kind, instance.GetNamespace(), instance.GetName())
+		return reconcile.Result{}, nil
+	}
+
+	if !containsString(instance.GetFinalizers(), r.Finalizer) {
+		instance.SetFinalizers(append(instance.GetFinalizers(), r.Finalizer))
+		if err := r.Client.Update(r.Context, instance); err != nil {
+			r.ManageError(instance, err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err := sync(reqLogger); err != nil {
+		r.ManageError(instance, err)
+		return reconcile.Result{RequeueAfter: config.RequeueDelay}, nil
+	}
+
+	r.ManageSuccess(instance)
+	return reconcile.Result{RequeueAfter: ResyncPeriod()}, nil
+}
+
+// ManageError records the error as event and in the status of the resource
+func (r *SyncedResourceReconciler) ManageError(instance grafanav1alpha1.SyncedResource, issue error) {
+	r.Recorder.Event(instance, "Warning", "ProcessingError", issue.Error())
+
+	// Ignore conflicts. Resource might just be outdated.
+	if errors.IsConflict(issue) {
+		return
+	}
+	r.Log.Error(issue, fmt.Sprintf("error updating %v", r.Kind))
+	r.updateStatus(instance, grafanav1alpha1.PhaseFailing, issue.Error(), issue)
+}
+
+// ManageSuccess records that the resource exists in all matching grafanas
+func (r *SyncedResourceReconciler) ManageSuccess(instance grafanav1alpha1.SyncedResource) {
+	r.Log.Info(fmt.Sprintf("%v %v/%v successfully reconciled", r.Kind, instance.GetNamespace(), instance.GetName()))
+	r.updateStatus(instance, grafanav1alpha1.PhaseReconciling, "success", nil)
+	metrics.SetLastSuccessfulSync(r.Kind, instance.GetNamespace(), instance.GetName())
+}
+
+// Only write the status when it changed to not trigger another reconciliation
+func (r *SyncedResourceReconciler) updateStatus(instance grafanav1alpha1.SyncedResource, phase grafanav1alpha1.StatusPhase, message string, issue error) {
+	previous := instance.DeepCopyObject()
+	instance.SetSyncStatus(phase, message, issue)
+	if reflect.DeepEqual(previous, instance) {
+		return
+	}
+
+	err := r.Client.Status().Update(r.Context, instance)
+	if err != nil && !errors.IsConflict(err) {
+		r.Log.Error(err, fmt.Sprintf("error updating %v status", r.Kind))
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, s string) []string {
+	for i, v := range list {
+		if v == s {
+			list = append(list[:i], list[i+1:]...)
+		}
+	}
+	return list
+}
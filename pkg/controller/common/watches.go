@@ -0,0 +1,91 @@
+package common
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// GrafanaBecameReady passes the events of grafana deployments that turned ready.
+// Syncs to unready grafana instances fail and are retried, the dashboards and
+// datasources are reconciled right away once the instance is back
+var GrafanaBecameReady = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return isGrafanaOwned(e.Meta) && deploymentReady(e.Object)
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return isGrafanaOwned(e.MetaNew) && !deploymentReady(e.ObjectOld) && deploymentReady(e.ObjectNew)
+	},
+	DeleteFunc: func(event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(event.GenericEvent) bool {
+		return false
+	},
+}
+
+func isGrafanaOwned(meta metav1.Object) bool {
+	owner := metav1.GetControllerOf(meta)
+	return owner != nil && owner.Kind == "Grafana" && owner.APIVersion == grafanav1alpha1.SchemeGroupVersion.String()
+}
+
+func deploymentReady(o runtime.Object) bool {
+	deployment, ok := o.(*appsv1.Deployment)
+	if !ok || deployment.Spec.Replicas == nil {
+		return false
+	}
+	return deployment.Status.ReadyReplicas == *deployment.Spec.Replicas
+}
+
+// OrganizationChanged passes the events of organizations that were created or
+// renamed in grafana. Dashboards and datasources of an organization that did not
+// exist yet are synced right away
+var OrganizationChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		previous := e.ObjectOld.(*grafanav1alpha1.GrafanaOrganization)
+		current := e.ObjectNew.(*grafanav1alpha1.GrafanaOrganization)
+		return previous.OrganizationName() != current.OrganizationName() || !reflect.DeepEqual(organizationIds(previous), organizationIds(current))
+	},
+}
+
+func organizationIds(cr *grafanav1alpha1.GrafanaOrganization) map[string]uint {
+	ids := map[string]uint{}
+	for _, instance := range cr.Status.Instances {
+		ids[instance.Namespace+"/"+instance.Name] = instance.ID
+	}
+	return ids
+}
+
+// OwningGrafana returns the grafana controlling the object or nil if the object is
+// not controlled by a grafana that still exists
+func OwningGrafana(ctx context.Context, c client.Client, meta metav1.Object) *grafanav1alpha1.Grafana {
+	if !isGrafanaOwned(meta) {
+		return nil
+	}
+
+	grafana := &grafanav1alpha1.Grafana{}
+	err := c.Get(ctx, types.NamespacedName{
+		Namespace: meta.GetNamespace(),
+		Name:      metav1.GetControllerOf(meta).Name,
+	}, grafana)
+	if err != nil {
+		return nil
+	}
+	return grafana
+}
+
+// ResyncPeriod returns the interval after which dashboards and datasources are
+// reconciled even if none of the watched resources changed
+func ResyncPeriod() time.Duration {
+	return config.GetControllerConfig().GetConfigItem(config.ConfigResyncPeriod, config.ResyncPeriod).(time.Duration)
+}
@@ -33,6 +33,35 @@ const (
 	JsonnetBasePath                 = "/opt/jsonnet"
 )
 
+const (
+	ConfigDatasourceHealthCheckInterval = "datasource.healthcheck.interval"
+	DatasourceHealthCheckInterval       = time.Minute
+)
+
+const (
+	ConfigGarbageCollectionMode     = "garbagecollection.mode"
+	ConfigGarbageCollectionInterval = "garbagecollection.interval"
+	GarbageCollectionInterval       = 5 * time.Minute
+	// Operator managed objects not backed by a custom resource are only reported
+	GarbageCollectionDryRun = "dry-run"
+	// Operator managed objects not backed by a custom resource are deleted
+	GarbageCollectionEnabled  = "enabled"
+	GarbageCollectionDisabled = "disabled"
+)
+
+const (
+	ConfigResyncPeriod = "resync.period"
+	ResyncPeriod       = 10 * time.Minute
+)
+
+const (
+	ConfigGrafanaApiRetries   = "grafana.api.retries"
+	ConfigGrafanaApiRateLimit = "grafana.api.ratelimit"
+	ConfigGrafanaApiBurst     = "grafana.api.burst"
+	GrafanaApiRetries         = 3
+	GrafanaApiBurst           = 10
+)
+
 type ControllerConfig struct {
 	*sync.Mutex
 	Values     map[string]interface{}
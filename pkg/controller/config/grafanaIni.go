@@ -8,19 +8,106 @@ import (
 	"strings"
 
 	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	v1 "k8s.io/api/core/v1"
 )
 
 type GrafanaIni struct {
 	cfg *v1alpha1.GrafanaConfig
 }
 
+// GrafanaIniSecretRef is a grafana.ini key whose value is read from a secret
+// instead of being written to the config map
+type GrafanaIniSecretRef struct {
+	Section string
+	Key     string
+	Ref     *v1.SecretKeySelector
+}
+
+// EnvVarName returns the name of the environment variable that overrides the key
+// in grafana.ini, e.g. GF_AUTH_GENERIC_OAUTH_CLIENT_SECRET
+func (r GrafanaIniSecretRef) EnvVarName() string {
+	name := fmt.Sprintf("GF_%s_%s", r.Section, r.Key)
+	name = strings.NewReplacer(".", "_", "-", "_").Replace(name)
+	return strings.ToUpper(name)
+}
+
 func NewGrafanaIni(cfg *v1alpha1.GrafanaConfig) *GrafanaIni {
 	return &GrafanaIni{
 		cfg: cfg,
 	}
 }
 
-func (i *GrafanaIni) Write() (string, string) {
+// SecretRefs returns all keys of the config that are read from secrets
+func (i *GrafanaIni) SecretRefs() []GrafanaIniSecretRef {
+	var refs []GrafanaIniSecretRef
+
+	appendRef := func(section, key string, ref *v1.SecretKeySelector) {
+		if ref != nil {
+			refs = append(refs, GrafanaIniSecretRef{
+				Section: section,
+				Key:     key,
+				Ref:     ref,
+			})
+		}
+	}
+
+	if i.cfg.Database != nil {
+		appendRef("database", "password", i.cfg.Database.PasswordRef)
+	}
+
+	if i.cfg.RemoteCache != nil {
+		appendRef("remote_cache", "connstr", i.cfg.RemoteCache.ConnStrRef)
+	}
+
+	if i.cfg.Security != nil {
+		appendRef("security", "secret_key", i.cfg.Security.SecretKeyRef)
+	}
+
+	if i.cfg.AuthGoogle != nil {
+		appendRef("auth.google", "client_secret", i.cfg.AuthGoogle.ClientSecretRef)
+	}
+
+	if i.cfg.AuthGithub != nil {
+		appendRef("auth.github", "client_secret", i.cfg.AuthGithub.ClientSecretRef)
+	}
+
+	if i.cfg.AuthGitlab != nil {
+		appendRef("auth.gitlab", "client_secret", i.cfg.AuthGitlab.ClientSecretRef)
+	}
+
+	if i.cfg.AuthGenericOauth != nil {
+		appendRef("auth.generic_oauth", "client_secret", i.cfg.AuthGenericOauth.ClientSecretRef)
+	}
+
+	if i.cfg.Smtp != nil {
+		appendRef("smtp", "password", i.cfg.Smtp.PasswordRef)
+	}
+
+	if i.cfg.Metrics != nil {
+		appendRef("metrics", "basic_auth_password", i.cfg.Metrics.BasicAuthPasswordRef)
+	}
+
+	if i.cfg.ExternalImageStorageS3 != nil {
+		appendRef("external_image_storage.s3", "access_key", i.cfg.ExternalImageStorageS3.AccessKeyRef)
+		appendRef("external_image_storage.s3", "secret_key", i.cfg.ExternalImageStorageS3.SecretKeyRef)
+	}
+
+	if i.cfg.ExternalImageStorageWebdav != nil {
+		appendRef("external_image_storage.webdav", "password", i.cfg.ExternalImageStorageWebdav.PasswordRef)
+	}
+
+	if i.cfg.ExternalImageStorageAzureBlob != nil {
+		appendRef("external_image_storage.azure_blob", "account_key", i.cfg.ExternalImageStorageAzureBlob.AccountKeyRef)
+	}
+
+	return refs
+}
+
+func (i *GrafanaIni) Write() (string, string, error) {
+	if err := i.cfg.ValidateExtra(); err != nil {
+		return "", "", err
+	}
+
 	config := map[string][]string{}
 
 	appendStr := func(l []string, key, value string) []string {
@@ -380,6 +467,33 @@ func (i *GrafanaIni) Write() (string, string) {
 		config["plugins"] = items
 	}
 
+	if i.cfg.Rendering != nil {
+		var items []string
+		items = appendStr(items, "server_url", i.cfg.Rendering.ServerUrl)
+		items = appendStr(items, "callback_url", i.cfg.Rendering.CallbackUrl)
+		items = appendInt(items, "concurrent_render_request_limit", i.cfg.Rendering.ConcurrentRenderRequestLimit)
+		config["rendering"] = items
+	}
+
+	// Merge the extra sections, the keys are sorted together with the typed keys
+	for section, values := range i.cfg.Extra {
+		for key, value := range values {
+			config[section] = append(config[section], fmt.Sprintf("%v = %v", key, value))
+		}
+	}
+
+	// Keys read from secrets are injected as environment variables and must
+	// not end up in the config map
+	for _, ref := range i.SecretRefs() {
+		var items []string
+		for _, item := range config[ref.Section] {
+			if !strings.HasPrefix(item, fmt.Sprintf("%v = ", ref.Key)) {
+				items = append(items, item)
+			}
+		}
+		config[ref.Section] = items
+	}
+
 	sb := strings.Builder{}
 
 	var keys []string
@@ -409,5 +523,5 @@ func (i *GrafanaIni) Write() (string, string) {
 	hash := sha256.New()
 	io.WriteString(hash, sb.String())
 
-	return sb.String(), fmt.Sprintf("%x", hash.Sum(nil))
+	return sb.String(), fmt.Sprintf("%x", hash.Sum(nil)), nil
 }
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGrafanaIniExtra(t *testing.T) {
+	cfg := &v1alpha1.GrafanaConfig{
+		Log: &v1alpha1.GrafanaConfigLog{
+			Mode: "console",
+		},
+		Extra: map[string]map[string]string{
+			"feature_toggles": {
+				"enable": "ngalert",
+			},
+			"log": {
+				"console_format": "json",
+			},
+		},
+	}
+
+	config, hash, err := NewGrafanaIni(cfg).Write()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(config, "[feature_toggles]\nenable = ngalert\n") {
+		t.Errorf("extra section missing in config:\n%s", config)
+	}
+
+	if !strings.Contains(config, "[log]\nconsole_format = json\nmode = console\n") {
+		t.Errorf("extra key not merged into typed section:\n%s", config)
+	}
+
+	// The output has to be stable to not restart grafana on every reconciliation
+	for i := 0; i < 10; i++ {
+		_, next, _ := NewGrafanaIni(cfg).Write()
+		if next != hash {
+			t.Fatalf("config hash is not deterministic")
+		}
+	}
+
+	cfg.Extra["feature_toggles"]["enable"] = "live"
+	_, next, _ := NewGrafanaIni(cfg).Write()
+	if next == hash {
+		t.Errorf("config hash does not include the extra config")
+	}
+}
+
+func TestGrafanaIniExtraConflicts(t *testing.T) {
+	conflicts := []map[string]map[string]string{
+		{"log": {"mode": "file"}},
+		{"paths": {"data": "/tmp"}},
+		{"auth.generic_oauth": {"client_secret": "secret"}},
+		{"server": {"domain": "x\n[security]\nadmin_password = y"}},
+		{"server": {"domain\r\nadmin_password": "y"}},
+		{"server": {"[security] admin_password": "y"}},
+		{"server": {"admin_password = y; domain": "x"}},
+		{"server]\n[security": {"admin_password": "y"}},
+	}
+
+	for _, extra := range conflicts {
+		cfg := &v1alpha1.GrafanaConfig{
+			Extra: extra,
+		}
+		if _, _, err := NewGrafanaIni(cfg).Write(); err == nil {
+			t.Errorf("expected conflict for %v", extra)
+		}
+	}
+}
+
+func TestGrafanaIniSecretRefs(t *testing.T) {
+	cfg := &v1alpha1.GrafanaConfig{
+		Database: &v1alpha1.GrafanaConfigDatabase{
+			User:     "grafana",
+			Password: "plaintext",
+			PasswordRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "db-credentials"},
+				Key:                  "password",
+			},
+		},
+		AuthGenericOauth: &v1alpha1.GrafanaConfigAuthGenericOauth{
+			ClientSecretRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "oauth"},
+				Key:                  "client-secret",
+			},
+		},
+	}
+
+	ini := NewGrafanaIni(cfg)
+	config, _, err := ini.Write()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(config, "plaintext") {
+		t.Errorf("secret value written to config:\n%s", config)
+	}
+
+	expected := map[string]bool{
+		"GF_DATABASE_PASSWORD":                true,
+		"GF_AUTH_GENERIC_OAUTH_CLIENT_SECRET": true,
+	}
+
+	refs := ini.SecretRefs()
+	if len(refs) != len(expected) {
+		t.Fatalf("expected %v secret refs, got %v", len(expected), len(refs))
+	}
+
+	for _, ref := range refs {
+		if !expected[ref.EnvVarName()] {
+			t.Errorf("unexpected env var %v", ref.EnvVarName())
+		}
+	}
+}
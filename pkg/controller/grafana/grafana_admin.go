@@ -0,0 +1,85 @@
+package grafana
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+	"github.com/ucloud/grafana-operator/pkg/controller/model"
+)
+
+// Rotates the password of the admin user, see model.AdminPasswordRotation. Grafana
+// only reads the password of the admin secret when its database is initialized,
+// so the password is changed through the api before it is written to the secret.
+// The new password is kept in the secret as pending until then, a rotation that
+// failed halfway is resumed by the next reconciliation
+func (r *ReconcileGrafana) rotateAdminPassword(cr *grafanav1alpha1.Grafana, state *common.ClusterState) error {
+	rotation := cr.Annotations[grafanav1alpha1.RotateAdminPasswordAnnotation]
+	password := model.AdminPasswordRotation(cr, state.AdminSecret)
+	if password == "" {
+		cr.Status.AdminPasswordRotation = rotation
+		return nil
+	}
+
+	// The secret may have been updated by the actions of this reconciliation
+	secret := &v1.Secret{}
+	if err := r.client.Get(r.context, model.AdminSecretSelector(cr), secret); err != nil {
+		return err
+	}
+	if string(secret.Data[model.GrafanaAdminPendingPasswordKey]) != password {
+		secret.Data[model.GrafanaAdminPendingPasswordKey] = []byte(password)
+		if err := r.client.Update(r.context, secret); err != nil {
+			return err
+		}
+	}
+
+	client, err := common.NewGrafanaClient(cr, state)
+	if err != nil {
+		return err
+	}
+	pendingState := *state
+	pendingState.AdminSecret = secret.DeepCopy()
+	pendingState.AdminSecret.Data[model.GrafanaAdminPasswordEnvVar] = []byte(password)
+
+	login := string(secret.Data[model.GrafanaAdminUserEnvVar])
+	err = changeAdminPassword(r.context, client, func() (grafanaClient.GrafanaClient, error) {
+		return common.NewGrafanaClient(cr, &pendingState)
+	}, login, password)
+	if err != nil {
+		r.recorder.Eventf(cr, "Warning", "AdminPasswordRotationFailed", "cannot change the password of %v: %v", login, err)
+		return err
+	}
+
+	secret.Data[model.GrafanaAdminPasswordEnvVar] = []byte(password)
+	delete(secret.Data, model.GrafanaAdminPendingPasswordKey)
+	if err := r.client.Update(r.context, secret); err != nil {
+		return err
+	}
+	r.recorder.Eventf(cr, "Normal", "AdminPasswordRotated", "password of %v changed", login)
+
+	state.AdminSecret = secret
+	cr.Status.AdminPasswordRotation = rotation
+	return nil
+}
+
+// Changes the password of the admin user with the current credentials. If they
+// are rejected the password may have been changed by a previous attempt, which
+// is the case if the new credentials are accepted
+func changeAdminPassword(ctx context.Context, client grafanaClient.GrafanaClient, pendingClient func() (grafanaClient.GrafanaClient, error), login, password string) error {
+	user, err := client.GetUserByLogin(ctx, login)
+	if err == nil {
+		return client.UpdateUserPassword(ctx, user.ID, password)
+	}
+
+	pending, pendingErr := pendingClient()
+	if pendingErr != nil {
+		return err
+	}
+	if _, pendingErr := pending.GetUserByLogin(ctx, login); pendingErr != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+func TestChangeAdminPassword(t *testing.T) {
+	password := "old"
+	var changes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, pass, _ := req.BasicAuth(); pass != password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.Method == http.MethodPut && req.URL.Path == "/api/admin/users/1/password" {
+			changes++
+			password = "new"
+		}
+		w.Write([]byte(`{"id": 1, "login": "admin"}`))
+	}))
+	defer server.Close()
+
+	options := grafanaClient.ClientOptions{Timeout: time.Second}
+	current := grafanaClient.NewGrafanaClient(server.URL, "admin", "old", options)
+	pending := func() (grafanaClient.GrafanaClient, error) {
+		return grafanaClient.NewGrafanaClient(server.URL, "admin", "new", options), nil
+	}
+
+	if err := changeAdminPassword(context.Background(), current, pending, "admin", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if changes != 1 || password != "new" {
+		t.Fatalf("expected the password to be changed once, got %v changes", changes)
+	}
+
+	// The secret could not be updated, the next attempt finds the new password
+	// accepted by grafana
+	if err := changeAdminPassword(context.Background(), current, pending, "admin", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if changes != 1 {
+		t.Errorf("expected no further change, got %v changes", changes)
+	}
+
+	unknown := func() (grafanaClient.GrafanaClient, error) {
+		return grafanaClient.NewGrafanaClient(server.URL, "admin", "other", options), nil
+	}
+	if err := changeAdminPassword(context.Background(), current, unknown, "admin", "other"); err == nil {
+		t.Error("expected an error if neither password is accepted")
+	}
+}
@@ -0,0 +1,81 @@
+package grafana
+
+import (
+	"fmt"
+	"strings"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	ReasonResourceReady      = "ResourceReady"
+	ReasonResourceNotReady   = "ResourceNotReady"
+	ReasonReadinessUnchecked = "ReadinessUnchecked"
+	ReasonPluginsInstalled   = "PluginsInstalled"
+	ReasonPluginsFailed      = "PluginsFailed"
+)
+
+// Sets the standard conditions from the result of the reconciliation and the
+// readiness actions that have been run
+func setConditions(cr *grafanav1alpha1.Grafana, readiness map[string]error, issue error) {
+	conditions := &cr.Status.Conditions
+	ready := []string{grafanav1alpha1.ConditionSynced, grafanav1alpha1.ConditionDeploymentAvailable}
+
+	grafanav1alpha1.SetSyncedCondition(conditions, cr.Generation, issue)
+	setReadinessCondition(cr, grafanav1alpha1.ConditionDeploymentAvailable, readiness)
+
+	if cr.IngressReadinessRequired() {
+		setReadinessCondition(cr, grafanav1alpha1.ConditionIngressReady, readiness)
+		ready = append(ready, grafanav1alpha1.ConditionIngressReady)
+	} else {
+		grafanav1alpha1.RemoveCondition(conditions, grafanav1alpha1.ConditionIngressReady)
+	}
+
+	setPluginsCondition(cr)
+	grafanav1alpha1.SetReadyCondition(conditions, cr.Generation, ready...)
+}
+
+func setReadinessCondition(cr *grafanav1alpha1.Grafana, conditionType string, readiness map[string]error) {
+	condition := grafanav1alpha1.Condition{
+		Type:               conditionType,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: cr.Generation,
+		Reason:             ReasonResourceReady,
+	}
+
+	err, checked := readiness[conditionType]
+	switch {
+	case !checked:
+		// An earlier action failed before the readiness could be checked
+		condition.Status = v1.ConditionUnknown
+		condition.Reason = ReasonReadinessUnchecked
+	case err != nil:
+		condition.Status = v1.ConditionFalse
+		condition.Reason = ReasonResourceNotReady
+		condition.Message = err.Error()
+	}
+
+	grafanav1alpha1.SetCondition(&cr.Status.Conditions, condition)
+}
+
+func setPluginsCondition(cr *grafanav1alpha1.Grafana) {
+	condition := grafanav1alpha1.Condition{
+		Type:               grafanav1alpha1.ConditionPluginsInstalled,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: cr.Generation,
+		Reason:             ReasonPluginsInstalled,
+	}
+
+	if len(cr.Status.FailedPlugins) > 0 {
+		var failed []string
+		for _, plugin := range cr.Status.FailedPlugins {
+			failed = append(failed, fmt.Sprintf("%v:%v", plugin.Name, plugin.Version))
+		}
+		condition.Status = v1.ConditionFalse
+		condition.Reason = ReasonPluginsFailed
+		condition.Message = fmt.Sprintf("failed to install plugins %v", strings.Join(failed, ", "))
+	}
+
+	grafanav1alpha1.SetCondition(&cr.Status.Conditions, condition)
+}
@@ -8,9 +8,12 @@ import (
 	v12 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	v1beta12 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -23,6 +26,8 @@ import (
 	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
 	"github.com/ucloud/grafana-operator/pkg/controller/common"
 	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
+	"github.com/ucloud/grafana-operator/pkg/controller/model"
 )
 
 const ControllerName = "grafana-controller"
@@ -48,6 +53,8 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		cancel:   cancel,
 		config:   config.GetControllerConfig(),
 		recorder: mgr.GetEventRecorderFor(ControllerName),
+		gc:       newGarbageCollector(),
+		tenants:  newCreatedTenants(),
 	}
 }
 
@@ -85,6 +92,21 @@ func add(mgr manager.Manager, r reconcile.Reconciler, autodetectChannel chan sch
 		return err
 	}
 
+	if err = watchSecondaryResource(c, &networkingv1.NetworkPolicy{}); err != nil {
+		return err
+	}
+
+	// Watch secrets referenced by the grafana config, they are not owned by the
+	// Grafana CR
+	err = c.Watch(&source.Kind{Type: &v1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return mapSecretToGrafanas(mgr.GetClient(), o)
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
 	go func() {
 		for gvk := range autodetectChannel {
 			cfg := config.GetControllerConfig()
@@ -122,6 +144,8 @@ type ReconcileGrafana struct {
 	cancel   context.CancelFunc
 	config   *config.ControllerConfig
 	recorder record.EventRecorder
+	gc       *garbageCollector
+	tenants  *createdTenants
 }
 
 func watchSecondaryResource(c controller.Controller, resource runtime.Object) error {
@@ -131,6 +155,34 @@ func watchSecondaryResource(c controller.Controller, resource runtime.Object) er
 	})
 }
 
+// Returns a request for every Grafana CR that references the secret in its config
+func mapSecretToGrafanas(c client.Client, o handler.MapObject) []reconcile.Request {
+	var requests []reconcile.Request
+
+	grafanas := &grafanav1alpha1.GrafanaList{}
+	err := c.List(context.Background(), grafanas, client.InNamespace(o.Meta.GetNamespace()))
+	if err != nil {
+		log.Error(err, "error listing grafanas")
+		return requests
+	}
+
+	for _, grafana := range grafanas.Items {
+		for _, ref := range model.GetConfigSecretRefs(&grafana) {
+			if ref.Ref.Name == o.Meta.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: grafana.Namespace,
+						Name:      grafana.Name,
+					},
+				})
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
 // Reconcile reads that state of the cluster for a Grafana object and makes changes based on the state read
 // and what is in the Grafana.Spec
 func (r *ReconcileGrafana) Reconcile(request reconcile.Request) (reconcile.Result, error) {
@@ -141,6 +193,10 @@ func (r *ReconcileGrafana) Reconcile(request reconcile.Request) (reconcile.Resul
 			// Stop the dashboard controller from reconciling when grafana is not installed
 			r.config.RemoveConfigItem(config.ConfigDashboardLabelSelector)
 			r.config.Cleanup(true)
+			metrics.ForgetResource(metrics.KindGrafana, request.Namespace, request.Name)
+			r.gc.forget(request.Namespace, request.Name)
+			r.tenants.forget(request.Namespace, request.Name)
+			common.ForgetGrafanaClient(request.Namespace, request.Name)
 
 			return reconcile.Result{}, nil
 		}
@@ -154,34 +210,50 @@ func (r *ReconcileGrafana) Reconcile(request reconcile.Request) (reconcile.Resul
 	err = currentState.Read(r.context, cr, r.client)
 	if err != nil {
 		log.Error(err, "error reading state")
-		return r.manageError(cr, err)
+		return r.manageError(cr, err, nil)
 	}
 
 	// Get the actions required to reach the desired state
 	reconciler := NewGrafanaReconciler()
-	desiredState := reconciler.Reconcile(currentState, cr)
+	desiredState, err := reconciler.Reconcile(currentState, cr)
+	if err != nil {
+		log.Error(err, "error computing desired state")
+		return r.manageError(cr, err, nil)
+	}
 
 	// Run the actions to reach the desired state
 	actionRunner := common.NewClusterActionRunner(r.context, r.client, r.scheme, cr)
 	err = actionRunner.RunAll(desiredState)
 	if err != nil {
-		return r.manageError(cr, err)
+		return r.manageError(cr, err, actionRunner.Readiness())
+	}
+
+	// Change the admin password through the grafana api before updating the secret
+	err = r.rotateAdminPassword(cr, currentState)
+	if err != nil {
+		return r.manageError(cr, err, actionRunner.Readiness())
 	}
 
 	// Run the config map reconciler to discover jsonnet libraries
 	err = reconcileConfigMaps(cr, r)
 	if err != nil {
-		return r.manageError(cr, err)
+		return r.manageError(cr, err, actionRunner.Readiness())
 	}
 
-	return r.manageSuccess(cr, currentState)
+	return r.manageSuccess(cr, currentState, actionRunner.Readiness())
 }
 
-func (r *ReconcileGrafana) manageError(cr *grafanav1alpha1.Grafana, issue error) (reconcile.Result, error) {
+func (r *ReconcileGrafana) manageError(cr *grafanav1alpha1.Grafana, issue error, readiness map[string]error) (reconcile.Result, error) {
 	r.recorder.Event(cr, "Warning", "ProcessingError", issue.Error())
 	cr.Status.Phase = grafanav1alpha1.PhaseFailing
 	cr.Status.Message = issue.Error()
 
+	// A resource that is not ready yet does not fail the reconciliation itself
+	if _, notReady := issue.(*common.NotReadyError); notReady {
+		issue = nil
+	}
+	setConditions(cr, readiness, issue)
+
 	err := r.client.Status().Update(r.context, cr)
 	if err != nil {
 		// Ignore conflicts, resource might just be outdated.
@@ -194,50 +266,208 @@ func (r *ReconcileGrafana) manageError(cr *grafanav1alpha1.Grafana, issue error)
 	return reconcile.Result{RequeueAfter: config.RequeueDelay}, nil
 }
 
-func (r *ReconcileGrafana) manageSuccess(cr *grafanav1alpha1.Grafana, state *common.ClusterState) (reconcile.Result, error) {
+func (r *ReconcileGrafana) manageSuccess(cr *grafanav1alpha1.Grafana, state *common.ClusterState, readiness map[string]error) (reconcile.Result, error) {
 	cr.Status.Phase = grafanav1alpha1.PhaseReconciling
 	cr.Status.Message = "success"
 
-	r.updateStatus(cr)
+	if err := r.updateStatus(cr, state); err != nil {
+		return r.manageError(cr, err, readiness)
+	}
+	setConditions(cr, readiness, nil)
 
 	err := r.client.Status().Update(r.context, cr)
 	if err != nil {
-		return r.manageError(cr, err)
+		return r.manageError(cr, err, readiness)
 	}
 
+	metrics.SetLastSuccessfulSync(metrics.KindGrafana, cr.Namespace, cr.Name)
 	log.Info("desired cluster state met")
 
 	return reconcile.Result{RequeueAfter: config.RequeueDelay}, nil
 }
 
-func (r *ReconcileGrafana) updateStatus(cr *grafanav1alpha1.Grafana) error {
+// Lists the dashboards and datasources synced to this instance as reported by
+// their controllers and the objects in grafana that are not backed by any of them
+func (r *ReconcileGrafana) updateStatus(cr *grafanav1alpha1.Grafana, state *common.ClusterState) error {
+	backing := newBackingObjects()
+	selectedNamespaces := map[string]bool{}
 	var installedDashboards []*grafanav1alpha1.GrafanaDashboardRef
 	dashboards := &grafanav1alpha1.GrafanaDashboardList{}
-	r.client.List(r.context, dashboards, client.InNamespace(cr.Namespace))
+	if err := r.client.List(r.context, dashboards, client.InNamespace(cr.ResourceNamespace())); err != nil {
+		return err
+	}
 	for _, dashboard := range dashboards.Items {
-		if match, err := common.MatchesSelectors(dashboard.Labels, cr.Spec.DashboardLabelSelector); err != nil {
+		match, err := common.MatchesSelectors(dashboard.Labels, cr.Spec.DashboardLabelSelector)
+		if err != nil {
 			return err
-		} else if match {
-			installedDashboards = append(installedDashboards, &grafanav1alpha1.GrafanaDashboardRef{
-				Name: dashboard.Name,
-			})
 		}
+		if !match {
+			continue
+		}
+
+		selected, err := r.selectsNamespace(cr, dashboard.Namespace, selectedNamespaces)
+		if err != nil {
+			return err
+		}
+		if !selected {
+			continue
+		}
+		backing.namespaces[dashboard.Namespace] = true
+
+		// The uid of url, config map and jsonnet dashboards is only known from
+		// the synced contents
+		org := resourceOrganization(cr, dashboard.Namespace, dashboard.Spec.Organization)
+		if dashboard.Spec.Json != "" {
+			backing.dashboards.add(org, dashboard.UID())
+		}
+		instance := grafanav1alpha1.FindInstanceStatus(dashboard.Status.Instances, cr.Namespace, cr.Name)
+		if instance == nil || instance.UID == "" {
+			backing.pendingDashboards.add(org, dashboard.Namespace+"/"+dashboard.Name)
+			continue
+		}
+		backing.dashboards.add(org, instance.UID)
+
+		backing.installedDashboards.add(org, instance.UID)
+		installedDashboards = append(installedDashboards, &grafanav1alpha1.GrafanaDashboardRef{
+			Name:      dashboard.Name,
+			Namespace: dashboard.Namespace,
+			UID:       instance.UID,
+			ID:        instance.ID,
+			Version:   instance.Version,
+			LastSync:  instance.LastSync,
+		})
 	}
 
 	var installedDataSources []*grafanav1alpha1.GrafanaDatasourceRef
 	dataSources := &grafanav1alpha1.GrafanaDataSourceList{}
-	r.client.List(r.context, dataSources, client.InNamespace(cr.Namespace))
+	if err := r.client.List(r.context, dataSources, client.InNamespace(cr.ResourceNamespace())); err != nil {
+		return err
+	}
 	for _, dataSource := range dataSources.Items {
-		if match, err := common.MatchesSelectors(dataSource.Labels, cr.Spec.DatasourceLabelSelector); err != nil {
+		match, err := common.MatchesSelectors(dataSource.Labels, cr.Spec.DatasourceLabelSelector)
+		if err != nil {
+			return err
+		}
+		if !match {
+			continue
+		}
+
+		selected, err := r.selectsNamespace(cr, dataSource.Namespace, selectedNamespaces)
+		if err != nil {
 			return err
-		} else if match {
+		}
+		if !selected {
+			continue
+		}
+		backing.namespaces[dataSource.Namespace] = true
+
+		// Removed datasources are backed until the datasource controller deleted them
+		for _, fields := range dataSource.Spec.Datasources {
+			backing.datasources.add(datasourceOrganization(cr, &dataSource, fields.OrgId), fields.Name)
+		}
+
+		for _, item := range dataSource.Status.Datasources {
+			org := datasourceOrganization(cr, &dataSource, item.OrgID)
+			backing.datasources.add(org, item.Name)
+			instance := grafanav1alpha1.FindInstanceStatus(item.Instances, cr.Namespace, cr.Name)
+			if instance == nil {
+				continue
+			}
+			backing.installedDatasources.add(org, item.Name)
+
 			installedDataSources = append(installedDataSources, &grafanav1alpha1.GrafanaDatasourceRef{
-				Name: dataSource.Name,
+				Name:      item.Name,
+				ID:        fmt.Sprint(instance.ID),
+				Namespace: dataSource.Namespace,
+				Resource:  dataSource.Name,
+				UID:       instance.UID,
+				Version:   instance.Version,
+				LastSync:  instance.LastSync,
 			})
 		}
 	}
 
 	cr.Status.InstalledDashboards = installedDashboards
 	cr.Status.InstalledDatasources = installedDataSources
+	cr.Status.DashboardCount = len(installedDashboards)
+	cr.Status.DatasourceCount = len(installedDataSources)
+	cr.Status.LastSyncTime = lastSyncTime(installedDashboards, installedDataSources)
+
+	// Orphans are informational, the previous ones are kept if grafana can't be reached
+	if err := r.updateOrphans(cr, state, backing); err != nil {
+		log.Error(err, "error listing the contents of grafana")
+	}
+
+	// Failed tenants are retried with the next reconciliation. The tenants are
+	// kept if the tenancy changes, the organizations were created by the operator
+	if cr.Spec.Tenancy == grafanav1alpha1.TenancyNamespaceOrgs {
+		if err := r.updateTenants(cr, state, backing.namespaces); err != nil {
+			log.Error(err, "error updating the organizations of the namespaces")
+		}
+	}
+	return nil
+}
+
+// Returns true if the instance selects the namespace, the selected namespaces
+// are cached to read every namespace only once
+func (r *ReconcileGrafana) selectsNamespace(cr *grafanav1alpha1.Grafana, namespace string, selected map[string]bool) (bool, error) {
+	if result, ok := selected[namespace]; ok {
+		return result, nil
+	}
+
+	result, err := common.SelectsNamespace(r.context, r.client, cr, namespace)
+	if err != nil {
+		return false, err
+	}
+	selected[namespace] = result
+	return result, nil
+}
+
+func lastSyncTime(dashboards []*grafanav1alpha1.GrafanaDashboardRef, datasources []*grafanav1alpha1.GrafanaDatasourceRef) *metav1.Time {
+	var last *metav1.Time
+	later := func(t *metav1.Time) {
+		if t != nil && (last == nil || last.Before(t)) {
+			last = t
+		}
+	}
+
+	for _, dashboard := range dashboards {
+		later(dashboard.LastSync)
+	}
+	for _, datasource := range datasources {
+		later(datasource.LastSync)
+	}
+	return last
+}
+
+// Lists the dashboards and datasources in the organizations of grafana that are
+// not installed by any custom resource and collects the ones managed by the
+// operator. The previous orphans of an organization that can't be listed are kept
+func (r *ReconcileGrafana) updateOrphans(cr *grafanav1alpha1.Grafana, state *common.ClusterState, backing *backingObjects) error {
+	grafanaClient, err := common.NewGrafanaClient(cr, state)
+	if err != nil {
+		return err
+	}
+
+	orgs, err := grafanaClient.ListOrganizations(r.context)
+	if err != nil {
+		return err
+	}
+
+	var orphanedDashboards []*grafanav1alpha1.GrafanaDashboardRef
+	var orphanedDatasources []*grafanav1alpha1.GrafanaDatasourceRef
+	for _, org := range orgs {
+		dashboards, datasources, err := r.listOrphans(cr, grafanaClient.WithOrganization(org.ID), org, backing)
+		if err != nil {
+			log.Error(err, "error listing the contents of an organization", "orgId", org.ID)
+			dashboards, datasources = previousOrphans(cr, org.ID)
+		}
+		orphanedDashboards = append(orphanedDashboards, dashboards...)
+		orphanedDatasources = append(orphanedDatasources, datasources...)
+	}
+
+	cr.Status.OrphanedDashboards = orphanedDashboards
+	cr.Status.OrphanedDatasources = orphanedDatasources
+	r.collectGarbage(cr, grafanaClient, orgs, backing)
 	return nil
 }
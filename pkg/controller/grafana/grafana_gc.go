@@ -0,0 +1,269 @@
+package grafana
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+// Organization of a dashboard or datasource in grafana, by id if the datasource
+// sets an orgId and by name otherwise. Resources without organization are
+// created in the current organization of the admin user, the default one
+type organization struct {
+	id   uint
+	name string
+}
+
+func (o organization) is(org grafanaClient.GrafanaOrganizationResponse) bool {
+	if o.id > 0 {
+		return o.id == org.ID
+	}
+	if o.name == "" {
+		return org.ID == grafanaClient.DefaultOrganizationId
+	}
+	return o.name == org.Name
+}
+
+// Returns the organization of a datasource of the custom resource, the orgId of
+// the datasource takes precedence unless grafana separates the namespaces into
+// organizations
+func datasourceOrganization(cr *grafanav1alpha1.Grafana, dataSource *grafanav1alpha1.GrafanaDataSource, orgId int) organization {
+	if orgId > 0 && cr.Spec.Tenancy != grafanav1alpha1.TenancyNamespaceOrgs {
+		return organization{id: uint(orgId)}
+	}
+	return resourceOrganization(cr, dataSource.Namespace, dataSource.Spec.Organization)
+}
+
+// Returns the organization of a dashboard or datasource of the namespace. The
+// organizations of namespaces are identified by the id recorded when the
+// operator created them, organizations named after the namespace are not theirs
+func resourceOrganization(cr *grafanav1alpha1.Grafana, namespace, name string) organization {
+	if orgId := common.TenantOrgID(cr, namespace); orgId > 0 && cr.Spec.Tenancy == grafanav1alpha1.TenancyNamespaceOrgs {
+		return organization{id: orgId}
+	}
+	return organization{name: common.ResourceOrganization(cr, namespace, name)}
+}
+
+// Uids of dashboards or names of datasources by their organization
+type orgObjects map[organization]map[string]bool
+
+func (o orgObjects) add(org organization, name string) {
+	if o[org] == nil {
+		o[org] = map[string]bool{}
+	}
+	o[org][name] = true
+}
+
+// Returns the objects in the organization
+func (o orgObjects) in(org grafanaClient.GrafanaOrganizationResponse) map[string]bool {
+	objects := map[string]bool{}
+	for ref, names := range o {
+		if !ref.is(org) {
+			continue
+		}
+		for name := range names {
+			objects[name] = true
+		}
+	}
+	return objects
+}
+
+// The dashboards and datasources of the custom resources matching a grafana,
+// whether they are synced or not, the ones installed in the grafana and the
+// namespaces of the custom resources. The uid of a dashboard is only known once
+// it is synced, no dashboards are collected in organizations with dashboards
+// that are not synced yet
+type backingObjects struct {
+	dashboards           orgObjects
+	pendingDashboards    orgObjects
+	datasources          orgObjects
+	installedDashboards  orgObjects
+	installedDatasources orgObjects
+	namespaces           map[string]bool
+}
+
+func newBackingObjects() *backingObjects {
+	return &backingObjects{
+		dashboards:           orgObjects{},
+		pendingDashboards:    orgObjects{},
+		datasources:          orgObjects{},
+		installedDashboards:  orgObjects{},
+		installedDatasources: orgObjects{},
+		namespaces:           map[string]bool{},
+	}
+}
+
+// Remembers when the grafana instances were last swept, the sweep runs less
+// often than grafana is reconciled
+type garbageCollector struct {
+	sync.Mutex
+	lastRuns map[string]time.Time
+}
+
+func newGarbageCollector() *garbageCollector {
+	return &garbageCollector{
+		lastRuns: map[string]time.Time{},
+	}
+}
+
+// Returns the garbage collection mode if the last sweep of the instance is older
+// than the interval and records the sweep, disabled otherwise
+func (g *garbageCollector) due(cr *grafanav1alpha1.Grafana) string {
+	cfg := config.GetControllerConfig()
+	mode := cfg.GetConfigString(config.ConfigGarbageCollectionMode, config.GarbageCollectionDryRun)
+	interval := cfg.GetConfigItem(config.ConfigGarbageCollectionInterval, config.GarbageCollectionInterval).(time.Duration)
+	if mode == config.GarbageCollectionDisabled {
+		return mode
+	}
+
+	key := fmt.Sprintf("%v/%v", cr.Namespace, cr.Name)
+	g.Lock()
+	defer g.Unlock()
+	if last, ok := g.lastRuns[key]; ok && time.Since(last) < interval {
+		return config.GarbageCollectionDisabled
+	}
+	g.lastRuns[key] = time.Now()
+	return mode
+}
+
+func (g *garbageCollector) forget(namespace, name string) {
+	g.Lock()
+	defer g.Unlock()
+	delete(g.lastRuns, fmt.Sprintf("%v/%v", namespace, name))
+}
+
+// Lists the dashboards and datasources in the organization that are not
+// installed by any custom resource
+func (r *ReconcileGrafana) listOrphans(cr *grafanav1alpha1.Grafana, client grafanaClient.GrafanaClient, org grafanaClient.GrafanaOrganizationResponse, backing *backingObjects) ([]*grafanav1alpha1.GrafanaDashboardRef, []*grafanav1alpha1.GrafanaDatasourceRef, error) {
+	dashboards, err := client.ListDashboards(r.context)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	datasources, err := client.ListDatasources(r.context)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	installedDashboards := backing.installedDashboards.in(org)
+	var orphanedDashboards []*grafanav1alpha1.GrafanaDashboardRef
+	for _, dashboard := range dashboards {
+		if installedDashboards[dashboard.UID] {
+			continue
+		}
+		orphanedDashboards = append(orphanedDashboards, &grafanav1alpha1.GrafanaDashboardRef{
+			Name:      dashboard.Title,
+			Namespace: dashboard.FolderTitle,
+			UID:       dashboard.UID,
+			ID:        dashboard.ID,
+			Managed:   common.IsManagedDashboard(cr, dashboard.Tags),
+			OrgID:     org.ID,
+		})
+	}
+
+	installedDatasources := backing.installedDatasources.in(org)
+	var orphanedDatasources []*grafanav1alpha1.GrafanaDatasourceRef
+	for _, datasource := range datasources {
+		if installedDatasources[datasource.Name] {
+			continue
+		}
+		orphanedDatasources = append(orphanedDatasources, &grafanav1alpha1.GrafanaDatasourceRef{
+			Name:    datasource.Name,
+			ID:      fmt.Sprint(datasource.ID),
+			UID:     datasource.UID,
+			Managed: common.IsManagedDatasource(cr, datasource.JsonData),
+			OrgID:   org.ID,
+		})
+	}
+	return orphanedDashboards, orphanedDatasources, nil
+}
+
+// Returns the orphans of the organization from the status
+func previousOrphans(cr *grafanav1alpha1.Grafana, orgId uint) ([]*grafanav1alpha1.GrafanaDashboardRef, []*grafanav1alpha1.GrafanaDatasourceRef) {
+	var dashboards []*grafanav1alpha1.GrafanaDashboardRef
+	for _, dashboard := range cr.Status.OrphanedDashboards {
+		if dashboard.OrgID == orgId {
+			dashboards = append(dashboards, dashboard)
+		}
+	}
+
+	var datasources []*grafanav1alpha1.GrafanaDatasourceRef
+	for _, datasource := range cr.Status.OrphanedDatasources {
+		if datasource.OrgID == orgId {
+			datasources = append(datasources, datasource)
+		}
+	}
+	return dashboards, datasources
+}
+
+// Deletes the orphans managed by the operator that are not backed by a matching
+// custom resource in their organization, or only reports them in dry-run mode.
+// Deleted orphans are removed from the status
+func (r *ReconcileGrafana) collectGarbage(cr *grafanav1alpha1.Grafana, client grafanaClient.GrafanaClient, orgs []grafanaClient.GrafanaOrganizationResponse, backing *backingObjects) {
+	mode := r.gc.due(cr)
+	if mode == config.GarbageCollectionDisabled {
+		return
+	}
+	dryRun := mode != config.GarbageCollectionEnabled
+
+	backedDashboards := map[uint]map[string]bool{}
+	backedDatasources := map[uint]map[string]bool{}
+	pendingDashboards := map[uint]bool{}
+	for _, org := range orgs {
+		backedDashboards[org.ID] = backing.dashboards.in(org)
+		backedDatasources[org.ID] = backing.datasources.in(org)
+		pendingDashboards[org.ID] = len(backing.pendingDashboards.in(org)) > 0
+	}
+
+	var dashboards []*grafanav1alpha1.GrafanaDashboardRef
+	for _, dashboard := range cr.Status.OrphanedDashboards {
+		backed, ok := backedDashboards[dashboard.OrgID]
+		if !dashboard.Managed || !ok || backed[dashboard.UID] || pendingDashboards[dashboard.OrgID] {
+			dashboards = append(dashboards, dashboard)
+			continue
+		}
+
+		if dryRun {
+			r.recorder.Eventf(cr, "Normal", "GarbageCollectionDryRun", "dashboard %v (%v) in organization %v would be deleted", dashboard.Name, dashboard.UID, dashboard.OrgID)
+			dashboards = append(dashboards, dashboard)
+			continue
+		}
+
+		if _, err := client.WithOrganization(dashboard.OrgID).DeleteDashboardByUID(r.context, dashboard.UID); err != nil && err != grafanaClient.NotFoundError {
+			log.Error(err, "error deleting orphaned dashboard", "uid", dashboard.UID, "orgId", dashboard.OrgID)
+			dashboards = append(dashboards, dashboard)
+			continue
+		}
+		r.recorder.Eventf(cr, "Normal", "GarbageCollected", "deleted orphaned dashboard %v (%v) in organization %v", dashboard.Name, dashboard.UID, dashboard.OrgID)
+	}
+
+	var datasources []*grafanav1alpha1.GrafanaDatasourceRef
+	for _, datasource := range cr.Status.OrphanedDatasources {
+		backed, ok := backedDatasources[datasource.OrgID]
+		if !datasource.Managed || !ok || backed[datasource.Name] {
+			datasources = append(datasources, datasource)
+			continue
+		}
+
+		if dryRun {
+			r.recorder.Eventf(cr, "Normal", "GarbageCollectionDryRun", "datasource %v in organization %v would be deleted", datasource.Name, datasource.OrgID)
+			datasources = append(datasources, datasource)
+			continue
+		}
+
+		if _, err := client.WithOrganization(datasource.OrgID).DeleteDatasourceByName(r.context, datasource.Name); err != nil && err != grafanaClient.NotFoundError {
+			log.Error(err, "error deleting orphaned datasource", "name", datasource.Name, "orgId", datasource.OrgID)
+			datasources = append(datasources, datasource)
+			continue
+		}
+		r.recorder.Eventf(cr, "Normal", "GarbageCollected", "deleted orphaned datasource %v in organization %v", datasource.Name, datasource.OrgID)
+	}
+
+	cr.Status.OrphanedDashboards = dashboards
+	cr.Status.OrphanedDatasources = datasources
+}
@@ -0,0 +1,114 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestCollectGarbage(t *testing.T) {
+	deleted := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodDelete {
+			deleted[req.Header.Get("X-Grafana-Org-Id")+" "+req.URL.Path] = true
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cfg := config.GetControllerConfig()
+	cfg.AddConfigItem(config.ConfigGarbageCollectionMode, config.GarbageCollectionEnabled)
+	defer cfg.RemoveConfigItem(config.ConfigGarbageCollectionMode)
+
+	cr := &grafanav1alpha1.Grafana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana"},
+		Status: grafanav1alpha1.GrafanaStatus{
+			OrphanedDashboards: []*grafanav1alpha1.GrafanaDashboardRef{
+				{Name: "removed", UID: "removed", Managed: true, OrgID: 1},
+				{Name: "pending", UID: "pending", Managed: true, OrgID: 1},
+				{Name: "pending", UID: "pending", Managed: true, OrgID: 2},
+				{Name: "manual", UID: "manual", OrgID: 1},
+			},
+			OrphanedDatasources: []*grafanav1alpha1.GrafanaDatasourceRef{
+				{Name: "removed", Managed: true, OrgID: 2},
+				{Name: "backed", Managed: true, OrgID: 2},
+				{Name: "manual", OrgID: 1},
+			},
+		},
+	}
+
+	// Backing objects are only backed in their organization
+	backing := newBackingObjects()
+	backing.dashboards.add(organization{}, "pending")
+	backing.datasources.add(organization{name: "team a"}, "backed")
+	orgs := []grafanaClient.GrafanaOrganizationResponse{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "team a"}}
+
+	r := &ReconcileGrafana{context: context.Background(), recorder: record.NewFakeRecorder(10), gc: newGarbageCollector()}
+	r.collectGarbage(cr, grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second}), orgs, backing)
+
+	expected := []string{"1 /api/dashboards/uid/removed", "2 /api/dashboards/uid/pending", "2 /api/datasources/name/removed"}
+	for _, request := range expected {
+		if !deleted[request] {
+			t.Errorf("expected deletion %v", request)
+		}
+	}
+	if len(deleted) != len(expected) {
+		t.Errorf("unexpected deletions %v", deleted)
+	}
+
+	if len(cr.Status.OrphanedDashboards) != 2 || len(cr.Status.OrphanedDatasources) != 2 {
+		t.Errorf("deleted orphans not removed from the status: %+v", cr.Status)
+	}
+
+	// The sweep runs once per interval
+	r.collectGarbage(cr, nil, orgs, newBackingObjects())
+}
+
+// Dashboards are not collected while a dashboard of their organization is not
+// synced, its uid is not known yet
+func TestCollectGarbagePendingDashboards(t *testing.T) {
+	deleted := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodDelete {
+			deleted[req.Header.Get("X-Grafana-Org-Id")+" "+req.URL.Path] = true
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cfg := config.GetControllerConfig()
+	cfg.AddConfigItem(config.ConfigGarbageCollectionMode, config.GarbageCollectionEnabled)
+	defer cfg.RemoveConfigItem(config.ConfigGarbageCollectionMode)
+
+	cr := &grafanav1alpha1.Grafana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana"},
+		Status: grafanav1alpha1.GrafanaStatus{
+			OrphanedDashboards: []*grafanav1alpha1.GrafanaDashboardRef{
+				{Name: "from-url", UID: "downloaded", Managed: true, OrgID: 1},
+				{Name: "removed", UID: "removed", Managed: true, OrgID: 2},
+			},
+		},
+	}
+
+	backing := newBackingObjects()
+	backing.pendingDashboards.add(organization{}, "monitoring/from-url")
+	orgs := []grafanaClient.GrafanaOrganizationResponse{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "team a"}}
+
+	r := &ReconcileGrafana{context: context.Background(), recorder: record.NewFakeRecorder(10), gc: newGarbageCollector()}
+	r.collectGarbage(cr, grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second}), orgs, backing)
+
+	if len(deleted) != 1 || !deleted["2 /api/dashboards/uid/removed"] {
+		t.Errorf("expected only the dashboard of the other organization to be deleted, got %v", deleted)
+	}
+	if len(cr.Status.OrphanedDashboards) != 1 || cr.Status.OrphanedDashboards[0].Name != "from-url" {
+		t.Errorf("expected the pending orphan to be kept, got %+v", cr.Status.OrphanedDashboards)
+	}
+}
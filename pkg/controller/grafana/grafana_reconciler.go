@@ -1,31 +1,39 @@
 package grafana
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"io"
 
 	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
 	"github.com/ucloud/grafana-operator/pkg/controller/common"
 	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
 	"github.com/ucloud/grafana-operator/pkg/controller/model"
 )
 
 type GrafanaReconciler struct {
-	DsHash     string
-	ConfigHash string
-	PluginsEnv string
-	Plugins    *PluginsHelperImpl
+	DsHash      string
+	ConfigHash  string
+	SecretsHash string
+	PluginsEnv  string
+	Plugins     *PluginsHelperImpl
 }
 
 func NewGrafanaReconciler() *GrafanaReconciler {
 	return &GrafanaReconciler{
-		DsHash:     "",
-		ConfigHash: "",
-		PluginsEnv: "",
-		Plugins:    newPluginsHelper(),
+		DsHash:      "",
+		ConfigHash:  "",
+		SecretsHash: "",
+		PluginsEnv:  "",
+		Plugins:     newPluginsHelper(),
 	}
 }
 
-func (i *GrafanaReconciler) Reconcile(state *common.ClusterState, cr *v1alpha1.Grafana) common.DesiredClusterState {
+// Reconcile returns the actions to reach the desired state. A config that can't
+// be generated fails the reconciliation before any action runs, the deployment
+// would otherwise be updated without the hash of the config
+func (i *GrafanaReconciler) Reconcile(state *common.ClusterState, cr *v1alpha1.Grafana) (common.DesiredClusterState, error) {
 	desired := common.DesiredClusterState{}
 
 	desired = desired.AddAction(i.getGrafanaAdminUserSecretDesiredState(state, cr))
@@ -36,8 +44,18 @@ func (i *GrafanaReconciler) Reconcile(state *common.ClusterState, cr *v1alpha1.G
 	}
 
 	desired = desired.AddAction(i.getGrafanaServiceAccountDesiredState(state, cr))
-	desired = desired.AddActions(i.getGrafanaConfigDesiredState(state, cr))
+	configActions, err := i.getGrafanaConfigDesiredState(state, cr)
+	if err != nil {
+		return nil, err
+	}
+	desired = desired.AddActions(configActions)
+
+	// No action, the hash of the referenced secrets restarts grafana when they change
+	i.SecretsHash = i.getConfigSecretsHash(state, cr)
+
 	desired = desired.AddAction(i.getGrafanaExternalAccessDesiredState(state, cr))
+	desired = desired.AddAction(i.getGrafanaNetworkPolicyDesiredState(state, cr))
+	desired = desired.AddActions(i.getImageRendererDesiredState(state, cr))
 
 	// Consolidate plugins
 	// No action, will update init container env var
@@ -50,21 +68,21 @@ func (i *GrafanaReconciler) Reconcile(state *common.ClusterState, cr *v1alpha1.G
 	// Check Deployment and Route readiness
 	desired = desired.AddActions(i.getGrafanaReadiness(state, cr))
 
-	return desired
+	return desired, nil
 }
 
 func (i *GrafanaReconciler) getGrafanaReadiness(state *common.ClusterState, cr *v1alpha1.Grafana) []common.ClusterAction {
 	var actions []common.ClusterAction
 	cfg := config.GetControllerConfig()
 	openshift := cfg.GetConfigBool(config.ConfigOpenshift, false)
-	if openshift && cr.Spec.Ingress != nil && cr.Spec.Ingress.Enabled && (cr.Spec.Client == nil || !cr.Spec.Client.PreferService) {
+	if openshift && cr.IngressReadinessRequired() {
 		// On OpenShift, check the route, only if preferService is false
 		actions = append(actions, common.RouteReadyAction{
 			Ref: state.GrafanaRoute,
 			Msg: "check route readiness",
 		})
 	}
-	if !openshift && cr.Spec.Ingress != nil && cr.Spec.Ingress.Enabled && (cr.Spec.Client == nil || !cr.Spec.Client.PreferService) {
+	if !openshift && cr.IngressReadinessRequired() {
 		// On vanilla Kubernetes, check the ingress,only if preferService is false
 		actions = append(actions, common.IngressReadyAction{
 			Ref: state.GrafanaIngress,
@@ -119,14 +137,13 @@ func (i *GrafanaReconciler) getGrafanaServiceAccountDesiredState(state *common.C
 	}
 }
 
-func (i *GrafanaReconciler) getGrafanaConfigDesiredState(state *common.ClusterState, cr *v1alpha1.Grafana) []common.ClusterAction {
+func (i *GrafanaReconciler) getGrafanaConfigDesiredState(state *common.ClusterState, cr *v1alpha1.Grafana) ([]common.ClusterAction, error) {
 	actions := []common.ClusterAction{}
 
 	if state.GrafanaConfig == nil {
 		config, err := model.GrafanaConfig(cr)
 		if err != nil {
-			log.Error(err, "error creating grafana config")
-			return nil
+			return nil, fmt.Errorf("error creating grafana config: %v", err)
 		}
 
 		// Store the last config hash for the duration of this reconciliation for
@@ -140,8 +157,7 @@ func (i *GrafanaReconciler) getGrafanaConfigDesiredState(state *common.ClusterSt
 	} else {
 		config, err := model.GrafanaConfigReconciled(cr, state.GrafanaConfig)
 		if err != nil {
-			log.Error(err, "error updating grafana config")
-			return nil
+			return nil, fmt.Errorf("error updating grafana config: %v", err)
 		}
 
 		i.ConfigHash = config.Annotations[model.LastConfigAnnotation]
@@ -151,7 +167,28 @@ func (i *GrafanaReconciler) getGrafanaConfigDesiredState(state *common.ClusterSt
 			Msg: "update grafana config",
 		})
 	}
-	return actions
+	return actions, nil
+}
+
+// Returns a hash of the versions of the secrets referenced in the config. The
+// secret values are not hashed, the hash ends up in an annotation of the
+// deployment. Any change of a referenced secret restarts grafana
+func (i *GrafanaReconciler) getConfigSecretsHash(state *common.ClusterState, cr *v1alpha1.Grafana) string {
+	refs := model.GetConfigSecretRefs(cr)
+	if len(refs) == 0 {
+		return ""
+	}
+
+	hash := sha256.New()
+	for _, ref := range refs {
+		io.WriteString(hash, ref.EnvVarName())
+		io.WriteString(hash, ref.Ref.Key)
+		if secret, ok := state.ConfigSecrets[ref.Ref.Name]; ok {
+			io.WriteString(hash, string(secret.UID))
+			io.WriteString(hash, secret.ResourceVersion)
+		}
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
 }
 
 func (i *GrafanaReconciler) getGrafanaExternalAccessDesiredState(state *common.ClusterState, cr *v1alpha1.Grafana) common.ClusterAction {
@@ -182,6 +219,96 @@ func (i *GrafanaReconciler) getGrafanaExternalAccessDesiredState(state *common.C
 	}
 }
 
+func (i *GrafanaReconciler) getGrafanaNetworkPolicyDesiredState(state *common.ClusterState, cr *v1alpha1.Grafana) common.ClusterAction {
+	if cr.Spec.NetworkPolicy == nil || !cr.Spec.NetworkPolicy.Enabled {
+		// network policy not enabled: remove it if it exists or do nothing
+		if state.GrafanaNetworkPolicy != nil {
+			return common.GenericDeleteAction{
+				Ref: state.GrafanaNetworkPolicy,
+				Msg: "delete grafana network policy",
+			}
+		}
+		return nil
+	}
+
+	if state.GrafanaNetworkPolicy == nil {
+		return common.GenericCreateAction{
+			Ref: model.GrafanaNetworkPolicy(cr),
+			Msg: "create grafana network policy",
+		}
+	}
+	return common.GenericUpdateAction{
+		Ref: model.GrafanaNetworkPolicyReconciled(cr, state.GrafanaNetworkPolicy),
+		Msg: "update grafana network policy",
+	}
+}
+
+func (i *GrafanaReconciler) getImageRendererDesiredState(state *common.ClusterState, cr *v1alpha1.Grafana) []common.ClusterAction {
+	var actions []common.ClusterAction
+
+	if !model.ImageRendererEnabled(cr) {
+		// image renderer not enabled: remove the resources if they exist
+		if state.ImageRendererDeployment != nil {
+			actions = append(actions, common.GenericDeleteAction{
+				Ref: state.ImageRendererDeployment,
+				Msg: "delete image renderer deployment",
+			})
+		}
+		if state.ImageRendererService != nil {
+			actions = append(actions, common.GenericDeleteAction{
+				Ref: state.ImageRendererService,
+				Msg: "delete image renderer service",
+			})
+		}
+		if state.ImageRendererSecret != nil {
+			actions = append(actions, common.GenericDeleteAction{
+				Ref: state.ImageRendererSecret,
+				Msg: "delete image renderer token secret",
+			})
+		}
+		return actions
+	}
+
+	// The token secret is referenced by both, the renderer and the grafana deployment
+	if state.ImageRendererSecret == nil {
+		actions = append(actions, common.GenericCreateAction{
+			Ref: model.ImageRendererSecret(cr),
+			Msg: "create image renderer token secret",
+		})
+	} else {
+		actions = append(actions, common.GenericUpdateAction{
+			Ref: model.ImageRendererSecretReconciled(cr, state.ImageRendererSecret),
+			Msg: "update image renderer token secret",
+		})
+	}
+
+	if state.ImageRendererService == nil {
+		actions = append(actions, common.GenericCreateAction{
+			Ref: model.ImageRendererService(cr),
+			Msg: "create image renderer service",
+		})
+	} else {
+		actions = append(actions, common.GenericUpdateAction{
+			Ref: model.ImageRendererServiceReconciled(cr, state.ImageRendererService),
+			Msg: "update image renderer service",
+		})
+	}
+
+	if state.ImageRendererDeployment == nil {
+		actions = append(actions, common.GenericCreateAction{
+			Ref: model.ImageRendererDeployment(cr),
+			Msg: "create image renderer deployment",
+		})
+	} else {
+		actions = append(actions, common.GenericUpdateAction{
+			Ref: model.ImageRendererDeploymentReconciled(cr, state.ImageRendererDeployment),
+			Msg: "update image renderer deployment",
+		})
+	}
+
+	return actions
+}
+
 func (i *GrafanaReconciler) getGrafanaAdminUserSecretDesiredState(state *common.ClusterState, cr *v1alpha1.Grafana) common.ClusterAction {
 	if state.AdminSecret == nil {
 		return common.GenericCreateAction{
@@ -224,14 +351,14 @@ func (i *GrafanaReconciler) getGrafanaRouteDesiredState(state *common.ClusterSta
 func (i *GrafanaReconciler) getGrafanaDeploymentDesiredState(state *common.ClusterState, cr *v1alpha1.Grafana) common.ClusterAction {
 	if state.GrafanaDeployment == nil {
 		return common.GenericCreateAction{
-			Ref: model.GrafanaDeployment(cr, i.ConfigHash, i.DsHash),
+			Ref: model.GrafanaDeployment(cr, i.ConfigHash, i.DsHash, i.SecretsHash),
 			Msg: "create grafana deployment",
 		}
 	}
 
 	return common.GenericUpdateAction{
 		Ref: model.GrafanaDeploymentReconciled(cr, state.GrafanaDeployment,
-			i.ConfigHash, i.PluginsEnv, i.DsHash),
+			i.ConfigHash, i.PluginsEnv, i.DsHash, i.SecretsHash),
 		Msg: "update grafana deployment",
 	}
 }
@@ -272,6 +399,7 @@ func (i *GrafanaReconciler) reconcilePlugins(cr *v1alpha1.Grafana, plugins v1alp
 		if i.Plugins.PluginExists(plugin) == false {
 			log.Info(fmt.Sprintf("invalid plugin: %s@%s", plugin.Name, plugin.Version))
 			failedPlugins = append(failedPlugins, plugin)
+			metrics.PluginValidationFailures.WithLabelValues(cr.Namespace, cr.Name, plugin.Name).Inc()
 			continue
 		}
 
@@ -0,0 +1,24 @@
+package grafana
+
+import (
+	"testing"
+	"time"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLastSyncTime(t *testing.T) {
+	earlier := metav1.NewTime(time.Unix(1000, 0))
+	later := metav1.NewTime(time.Unix(2000, 0))
+
+	if last := lastSyncTime(nil, nil); last != nil {
+		t.Errorf("expected no sync time, got %v", last)
+	}
+
+	dashboards := []*grafanav1alpha1.GrafanaDashboardRef{{Name: "a", LastSync: &earlier}, {Name: "b"}}
+	datasources := []*grafanav1alpha1.GrafanaDatasourceRef{{Name: "c", LastSync: &later}}
+	if last := lastSyncTime(dashboards, datasources); last == nil || !last.Equal(&later) {
+		t.Errorf("expected %v, got %v", later, last)
+	}
+}
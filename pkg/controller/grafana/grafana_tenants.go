@@ -0,0 +1,293 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+	"github.com/ucloud/grafana-operator/pkg/controller/model"
+)
+
+// Organization roles in increasing order of permissions
+var tenantRoles = []string{"Viewer", "Editor", "Admin"}
+
+// Organizations the operator created for the namespaces of the instances. The
+// ids are kept in case the status of an instance can't be updated after an
+// organization was created, it would be refused as not created otherwise
+type createdTenants struct {
+	sync.Mutex
+	orgIds map[string]uint
+}
+
+func newCreatedTenants() *createdTenants {
+	return &createdTenants{
+		orgIds: map[string]uint{},
+	}
+}
+
+func (c *createdTenants) get(cr *grafanav1alpha1.Grafana, namespace string) uint {
+	c.Lock()
+	defer c.Unlock()
+	return c.orgIds[fmt.Sprintf("%v/%v/%v", cr.Namespace, cr.Name, namespace)]
+}
+
+func (c *createdTenants) add(cr *grafanav1alpha1.Grafana, namespace string, orgId uint) {
+	c.Lock()
+	defer c.Unlock()
+	c.orgIds[fmt.Sprintf("%v/%v/%v", cr.Namespace, cr.Name, namespace)] = orgId
+}
+
+func (c *createdTenants) forget(namespace, name string) {
+	c.Lock()
+	defer c.Unlock()
+	prefix := fmt.Sprintf("%v/%v/", namespace, name)
+	for key := range c.orgIds {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.orgIds, key)
+		}
+	}
+}
+
+// Creates the organizations of the namespaces with matching dashboards or
+// datasources and grants the membership listed in the annotations of the
+// namespaces. The tenants that can't be updated keep their previous status, the
+// tenants of namespaces without matching resources are kept unchanged to
+// remember the organizations the operator created
+func (r *ReconcileGrafana) updateTenants(cr *grafanav1alpha1.Grafana, state *common.ClusterState, namespaces map[string]bool) error {
+	client, err := common.NewGrafanaClient(cr, state)
+	if err != nil {
+		return err
+	}
+	admin := string(state.AdminSecret.Data[model.GrafanaAdminUserEnvVar])
+
+	previous := map[string]*grafanav1alpha1.GrafanaTenant{}
+	for _, tenant := range cr.Status.Tenants {
+		previous[tenant.Namespace] = tenant
+	}
+
+	var names []string
+	for namespace := range namespaces {
+		names = append(names, namespace)
+	}
+	for namespace := range previous {
+		if !namespaces[namespace] {
+			names = append(names, namespace)
+		}
+	}
+	sort.Strings(names)
+
+	var tenants []*grafanav1alpha1.GrafanaTenant
+	var failed []string
+	for _, namespace := range names {
+		if !namespaces[namespace] {
+			tenants = append(tenants, previous[namespace])
+			continue
+		}
+
+		tenant, err := r.updateTenant(cr, client, admin, namespace, previous[namespace])
+		if err != nil {
+			log.Error(err, "error updating tenant", "namespace", namespace)
+			r.recorder.Eventf(cr, "Warning", "TenantSyncFailed", "organization of namespace %v: %v", namespace, err)
+			failed = append(failed, namespace)
+		}
+		if tenant == nil {
+			tenant = previous[namespace]
+		}
+		if tenant != nil {
+			tenants = append(tenants, tenant)
+		}
+	}
+
+	cr.Status.Tenants = tenants
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot update the organizations of namespaces %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (r *ReconcileGrafana) updateTenant(cr *grafanav1alpha1.Grafana, client grafanaClient.GrafanaClient, admin, namespace string, previous *grafanav1alpha1.GrafanaTenant) (*grafanav1alpha1.GrafanaTenant, error) {
+	ns := &v1.Namespace{}
+	if err := r.client.Get(r.context, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	members, err := tenantMembers(r.context, client, ns.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	var granted []string
+	created := r.tenants.get(cr, namespace)
+	if previous != nil {
+		granted = previous.Members
+		created = previous.OrgID
+	}
+	orgId, err := common.TenantOrganization(r.context, client, namespace, created)
+	if err != nil {
+		return nil, err
+	}
+	if created == 0 {
+		r.tenants.add(cr, namespace, orgId)
+		r.recorder.Eventf(cr, "Normal", "Created", "organization of namespace %v created", namespace)
+	}
+
+	delete(members, admin)
+	synced, err := syncMembers(r.context, client, orgId, members, granted)
+	if synced == nil {
+		return nil, err
+	}
+
+	tenant := &grafanav1alpha1.GrafanaTenant{
+		Namespace: namespace,
+		OrgID:     orgId,
+	}
+	for login := range synced {
+		tenant.Members = append(tenant.Members, login)
+	}
+	sort.Strings(tenant.Members)
+	return tenant, err
+}
+
+// Returns the role of every user listed in the annotations, directly or as member
+// of a team of the default organization. Users listed more than once get the
+// highest of their roles
+func tenantMembers(ctx context.Context, client grafanaClient.GrafanaClient, annotations map[string]string) (map[string]string, error) {
+	users, err := parseMembers(annotations[grafanav1alpha1.TenantUsersAnnotation])
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := parseMembers(annotations[grafanav1alpha1.TenantTeamsAnnotation])
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[string]string{}
+	for login, role := range users {
+		members[login] = role
+	}
+
+	for name, role := range teams {
+		team, err := client.GetTeamByName(ctx, name)
+		if err != nil {
+			if err == grafanaClient.NotFoundError {
+				return nil, fmt.Errorf("team %v does not exist", name)
+			}
+			return nil, err
+		}
+
+		teamMembers, err := client.ListTeamMembers(ctx, team.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range teamMembers {
+			members[member.Login] = higherRole(members[member.Login], role)
+		}
+	}
+	return members, nil
+}
+
+// Parses a comma separated list of names with optional roles, e.g.
+// "alice=Editor, bob"
+func parseMembers(annotation string) (map[string]string, error) {
+	members := map[string]string{}
+	for _, item := range strings.Split(annotation, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, role := item, tenantRoles[0]
+		if i := strings.Index(item, "="); i >= 0 {
+			name, role = strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		}
+		if name == "" || roleRank(role) < 0 {
+			return nil, fmt.Errorf("invalid member %v, expected <name>[=%v]", item, strings.Join(tenantRoles, "|"))
+		}
+		members[name] = higherRole(members[name], role)
+	}
+	return members, nil
+}
+
+func roleRank(role string) int {
+	for i, r := range tenantRoles {
+		if r == role {
+			return i
+		}
+	}
+	return -1
+}
+
+func higherRole(a, b string) string {
+	if roleRank(a) > roleRank(b) {
+		return a
+	}
+	return b
+}
+
+// Adds the members to the organization or updates their roles, and removes the
+// previously granted users that are no longer members. Returns the members that
+// were granted their role
+func syncMembers(ctx context.Context, client grafanaClient.GrafanaClient, orgId uint, members map[string]string, granted []string) (map[string]string, error) {
+	users, err := client.ListOrganizationUsers(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]grafanaClient.GrafanaOrganizationUser{}
+	for _, user := range users {
+		current[user.Login] = user
+	}
+
+	result := map[string]string{}
+	var failed []string
+	for login, role := range members {
+		user, ok := current[login]
+		switch {
+		case !ok:
+			err = client.AddOrganizationUser(ctx, orgId, login, role)
+		case user.Role != role:
+			err = client.UpdateOrganizationUser(ctx, orgId, user.UserID, role)
+		default:
+			err = nil
+		}
+		if err != nil {
+			failed = append(failed, login)
+			if ok {
+				result[login] = user.Role
+			}
+			continue
+		}
+		result[login] = role
+	}
+
+	for _, login := range granted {
+		user, ok := current[login]
+		if _, member := members[login]; member || !ok {
+			continue
+		}
+		if err := client.RemoveOrganizationUser(ctx, orgId, user.UserID); err != nil && err != grafanaClient.NotFoundError {
+			failed = append(failed, login)
+			// Keep the user to retry the removal
+			result[login] = user.Role
+		}
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return result, fmt.Errorf("cannot update the membership of %v", strings.Join(failed, ", "))
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package grafana
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+func TestParseMembers(t *testing.T) {
+	members, err := parseMembers(" alice=Editor, bob,, alice=Viewer, carol = Admin ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{"alice": "Editor", "bob": "Viewer", "carol": "Admin"}
+	if !reflect.DeepEqual(members, expected) {
+		t.Errorf("expected %v, got %v", expected, members)
+	}
+
+	for _, annotation := range []string{"alice=Owner", "=Editor"} {
+		if _, err := parseMembers(annotation); err == nil {
+			t.Errorf("expected an error for %q", annotation)
+		}
+	}
+}
+
+func TestSyncMembers(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			w.Write([]byte(`[
+				{"userId": 1, "login": "admin", "role": "Admin"},
+				{"userId": 2, "login": "alice", "role": "Viewer"},
+				{"userId": 3, "login": "bob", "role": "Viewer"},
+				{"userId": 4, "login": "manual", "role": "Editor"}
+			]`))
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second})
+	members := map[string]string{"alice": "Editor", "carol": "Viewer"}
+	synced, err := syncMembers(context.Background(), client, 2, members, []string{"alice", "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(synced, members) {
+		t.Errorf("expected %v, got %v", members, synced)
+	}
+
+	// Users that were not granted by the operator are kept
+	sort.Strings(requests)
+	expected := []string{
+		`DELETE /api/orgs/2/users/3 `,
+		`PATCH /api/orgs/2/users/2 {"role":"Editor"}`,
+		`POST /api/orgs/2/users {"loginOrEmail":"carol","role":"Viewer"}`,
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %q, got %q", expected, requests)
+	}
+}
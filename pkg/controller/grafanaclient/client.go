@@ -2,14 +2,23 @@ package grafanaClient
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -17,8 +26,14 @@ const (
 	createOrUpdateDashboardUrl = "%s/api/dashboards/db"
 	deleteDatasourceByNameUrl  = "%s/api/datasources/name/%s"
 	createDatasourceUrl        = "%s/api/datasources"
+	datasourceHealthByUidUrl   = "%s/api/datasources/uid/%s/health"
+	datasourceHealthUrl        = "%s/api/datasources/%s/health"
+	datasourceProxyUrl         = "%s/api/datasources/proxy/%s/"
 	createOrUpdateFolderUrl    = "%s/api/folders"
 	healthInfoUrl              = "%s/api/health"
+	searchDashboardsUrl        = "%s/api/search?query=%s"
+	listDashboardsUrl          = "%s/api/search?type=dash-db"
+	listDatasourcesUrl         = "%s/api/datasources"
 )
 
 const (
@@ -45,8 +60,22 @@ type GrafanaResponse struct {
 	Status  *string `json:"resp"`
 	UID     *string `json:"uid"`
 	URL     *string `json:"url"`
+	// Only set for datasources
+	JsonData map[string]interface{} `json:"jsonData,omitempty"`
+}
+
+// Result of a datasource health check. Status is one of OK, ERROR or UNKNOWN
+type GrafanaHealthResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
 }
 
+const (
+	HealthStatusOk      = "OK"
+	HealthStatusError   = "ERROR"
+	HealthStatusUnknown = "UNKNOWN"
+)
+
 type GrafanaFolderRequest struct {
 	Title string `json:"title"`
 }
@@ -56,15 +85,72 @@ type GrafanaFolderResponse struct {
 	Title string `json:"title"`
 }
 
+// A dashboard as listed by the search api
+type GrafanaDashboardSearchResult struct {
+	ID          uint     `json:"id"`
+	UID         string   `json:"uid"`
+	Title       string   `json:"title"`
+	FolderTitle string   `json:"folderTitle"`
+	Tags        []string `json:"tags"`
+}
+
+// A datasource as listed by the datasources api
+type GrafanaDatasourceListItem struct {
+	ID       uint                   `json:"id"`
+	UID      string                 `json:"uid"`
+	Name     string                 `json:"name"`
+	JsonData map[string]interface{} `json:"jsonData"`
+}
+
 type GrafanaClient interface {
-	CheckGrafanaHealth() error
-	GetDashboardsByName(name string) ([]GrafanaResponse, error)
-	CreateOrUpdateDashboard(dashboard []byte, folderId int64) (GrafanaResponse, error)
-	DeleteDashboardByUID(UID string) (GrafanaResponse, error)
-	GetOrCreateNamespaceFolder(namespace string) (GrafanaFolderResponse, error)
-	GetDatasourceByName(name string) (GrafanaResponse, error)
-	CreateDatasource(datasource []byte) (GrafanaResponse, error)
-	DeleteDatasourceByName(name string) (GrafanaResponse, error)
+	CheckGrafanaHealth(ctx context.Context) error
+	GetDashboardsByName(ctx context.Context, name string) ([]GrafanaResponse, error)
+	CreateOrUpdateDashboard(ctx context.Context, dashboard []byte, folderId int64) (GrafanaResponse, error)
+	DeleteDashboardByUID(ctx context.Context, UID string) (GrafanaResponse, error)
+	GetOrCreateNamespaceFolder(ctx context.Context, namespace string) (GrafanaFolderResponse, error)
+	GetDatasourceByName(ctx context.Context, name string) (GrafanaResponse, error)
+	CreateDatasource(ctx context.Context, datasource []byte) (GrafanaResponse, error)
+	DeleteDatasourceByName(ctx context.Context, name string) (GrafanaResponse, error)
+	CheckDatasourceHealth(ctx context.Context, id uint, uid string) (GrafanaHealthResponse, error)
+	ListDashboards(ctx context.Context) ([]GrafanaDashboardSearchResult, error)
+	ListDatasources(ctx context.Context) ([]GrafanaDatasourceListItem, error)
+	WithOrganization(orgId uint) GrafanaClient
+	GetOrganizationByName(ctx context.Context, name string) (GrafanaOrganizationResponse, error)
+	ListOrganizations(ctx context.Context) ([]GrafanaOrganizationResponse, error)
+	CreateOrganization(ctx context.Context, name string) (uint, error)
+	DeleteOrganization(ctx context.Context, orgId uint) error
+	RenameOrganization(ctx context.Context, orgId uint, name string) error
+	AddOrganizationUser(ctx context.Context, orgId uint, login, role string) error
+	ListOrganizationUsers(ctx context.Context, orgId uint) ([]GrafanaOrganizationUser, error)
+	UpdateOrganizationUser(ctx context.Context, orgId, userId uint, role string) error
+	RemoveOrganizationUser(ctx context.Context, orgId, userId uint) error
+	UpdateOrganizationPreferences(ctx context.Context, preferences GrafanaPreferences) error
+	GetDashboardByUID(ctx context.Context, uid string) (GrafanaResponse, error)
+	GetTeamByName(ctx context.Context, name string) (GrafanaTeamResponse, error)
+	ListTeamMembers(ctx context.Context, teamId uint) ([]GrafanaTeamMember, error)
+	CreateTeam(ctx context.Context, name, email string) (uint, error)
+	UpdateTeam(ctx context.Context, teamId uint, name, email string) error
+	DeleteTeam(ctx context.Context, teamId uint) error
+	AddTeamMember(ctx context.Context, teamId, userId uint) error
+	RemoveTeamMember(ctx context.Context, teamId, userId uint) error
+	ListTeamGroups(ctx context.Context, teamId uint) ([]string, error)
+	AddTeamGroup(ctx context.Context, teamId uint, group string) error
+	RemoveTeamGroup(ctx context.Context, teamId uint, group string) error
+	GetUserByLogin(ctx context.Context, loginOrEmail string) (GrafanaUserResponse, error)
+	CreateUser(ctx context.Context, user GrafanaUserRequest) (uint, error)
+	UpdateUser(ctx context.Context, userId uint, user GrafanaUserRequest) error
+	UpdateUserPassword(ctx context.Context, userId uint, password string) error
+	UpdateUserPermissions(ctx context.Context, userId uint, grafanaAdmin bool) error
+	DeleteUser(ctx context.Context, userId uint) error
+	GetDashboardPermissions(ctx context.Context, uid string) ([]GrafanaDashboardPermission, error)
+	UpdateDashboardPermissions(ctx context.Context, uid string, permissions []GrafanaDashboardPermission) error
+	GetServiceAccountByName(ctx context.Context, name string) (GrafanaServiceAccountResponse, error)
+	CreateServiceAccount(ctx context.Context, account GrafanaServiceAccountRequest) (uint, error)
+	UpdateServiceAccount(ctx context.Context, accountId uint, account GrafanaServiceAccountRequest) error
+	DeleteServiceAccount(ctx context.Context, accountId uint) error
+	ListServiceAccountTokens(ctx context.Context, accountId uint) ([]GrafanaServiceAccountToken, error)
+	CreateServiceAccountToken(ctx context.Context, accountId uint, name string) (GrafanaServiceAccountToken, error)
+	DeleteServiceAccountToken(ctx context.Context, accountId, tokenId uint) error
 }
 
 type GrafanaClientImpl struct {
@@ -72,25 +158,155 @@ type GrafanaClientImpl struct {
 	user     string
 	password string
 	client   *http.Client
+	retries  int
+	limiter  *rate.Limiter
+	// Organization of all requests, the current organization of the user if zero
+	orgId uint
 }
 
+// Settings of the client of a grafana instance
+type ClientOptions struct {
+	// Timeout of a single attempt of a request
+	Timeout time.Duration
+	// Attempts after the first one for connection errors and 5xx responses.
+	// Requests that are not idempotent are only retried if they were not sent
+	Retries int
+	// Requests per second, unlimited if zero
+	RateLimit float64
+	Burst     int
+}
+
+const (
+	retryBaseDelay = 250 * time.Millisecond
+	retryMaxDelay  = 5 * time.Second
+)
+
 func setHeaders(req *http.Request) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "grafana-operator")
 }
 
-func NewGrafanaClient(url, user, password string, timeout time.Duration) GrafanaClient {
-	transport := http.Transport{
+// do sends the request, waiting for the rate limiter, and retries connection
+// errors and 5xx responses with a jittered backoff until the context is done.
+// A POST that timed out or failed on the server may have been applied, it is
+// only retried if the connection could not be established
+func (r *GrafanaClientImpl) do(req *http.Request, urlTemplate string) (*http.Response, error) {
+	ctx := req.Context()
+	if r.orgId > 0 {
+		req.Header.Set(orgIdHeader, strconv.FormatUint(uint64(r.orgId), 10))
+	}
+
+	for attempt := 0; ; attempt++ {
+		if r.limiter != nil {
+			if err := r.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := r.send(req, urlTemplate)
+		if attempt >= r.retries || !retryable(req, resp, err) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff(attempt)):
+		}
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// send sends the request once and records its latency and status code. The url
+// template is used as endpoint label to keep the cardinality low
+func (r *GrafanaClientImpl) send(req *http.Request, urlTemplate string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := r.client.Do(req)
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.ObserveApiRequest(req.Method, endpointOf(urlTemplate), code, time.Since(start))
+
+	return resp, err
+}
+
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return idempotent(req.Method) || notSent(err)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError && idempotent(req.Method)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Requests fail without being sent if the connection can't be established
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// Exponential backoff with jitter, so that requests failing at the same time are
+// not retried at the same time
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay << uint(attempt)
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+}
+
+// Turns an url template like `%s/api/dashboards/uid/%s` into `/api/dashboards/uid/:param`
+func endpointOf(urlTemplate string) string {
+	endpoint := strings.TrimPrefix(urlTemplate, "%s")
+	if i := strings.Index(endpoint, "?"); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+	return strings.ReplaceAll(endpoint, "%s", ":param")
+}
+
+// NewGrafanaClient creates a client with its own connection pool. Clients are
+// meant to be reused for all requests to the same instance
+func NewGrafanaClient(url, user, password string, options ClientOptions) GrafanaClient {
+	transport := &http.Transport{
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: true,
 		},
-		DisableKeepAlives: true,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
 	}
 
 	client := &http.Client{
-		Transport: &transport,
-		Timeout:   timeout,
+		Transport: transport,
+		Timeout:   options.Timeout,
+	}
+
+	var limiter *rate.Limiter
+	if options.RateLimit > 0 {
+		burst := options.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(options.RateLimit), burst)
 	}
 
 	return &GrafanaClientImpl{
@@ -98,10 +314,17 @@ func NewGrafanaClient(url, user, password string, timeout time.Duration) Grafana
 		user:     user,
 		password: password,
 		client:   client,
+		retries:  options.Retries,
+		limiter:  limiter,
 	}
 }
 
-func (r *GrafanaClientImpl) getAllFolders() ([]GrafanaFolderResponse, error) {
+// CloseIdleConnections closes the pooled connections once the client is replaced
+func (r *GrafanaClientImpl) CloseIdleConnections() {
+	r.client.Transport.(*http.Transport).CloseIdleConnections()
+}
+
+func (r *GrafanaClientImpl) getAllFolders(ctx context.Context) ([]GrafanaFolderResponse, error) {
 	rawUrl := fmt.Sprintf(createOrUpdateFolderUrl, r.url)
 	parsed, err := url.Parse(rawUrl)
 	if err != nil {
@@ -109,14 +332,14 @@ func (r *GrafanaClientImpl) getAllFolders() ([]GrafanaFolderResponse, error) {
 	}
 
 	parsed.User = url.UserPassword(r.user, r.password)
-	req, err := http.NewRequest("GET", parsed.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", parsed.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	setHeaders(req)
 
-	resp, err := r.client.Do(req)
+	resp, err := r.do(req, createOrUpdateFolderUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -138,8 +361,58 @@ func (r *GrafanaClientImpl) getAllFolders() ([]GrafanaFolderResponse, error) {
 	return folders, err
 }
 
+// ListDashboards lists all dashboards of the organization of the client
+func (r *GrafanaClientImpl) ListDashboards(ctx context.Context) ([]GrafanaDashboardSearchResult, error) {
+	var dashboards []GrafanaDashboardSearchResult
+	err := r.list(ctx, listDashboardsUrl, &dashboards)
+	return dashboards, err
+}
+
+// ListDatasources lists all datasources of the organization of the client
+func (r *GrafanaClientImpl) ListDatasources(ctx context.Context) ([]GrafanaDatasourceListItem, error) {
+	var datasources []GrafanaDatasourceListItem
+	err := r.list(ctx, listDatasourcesUrl, &datasources)
+	return datasources, err
+}
+
+func (r *GrafanaClientImpl) list(ctx context.Context, urlTemplate string, response interface{}) error {
+	rawUrl := fmt.Sprintf(urlTemplate, r.url)
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return err
+	}
+
+	parsed.User = url.UserPassword(r.user, r.password)
+	req, err := http.NewRequestWithContext(ctx, "GET", parsed.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	setHeaders(req)
+
+	resp, err := r.do(req, urlTemplate)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf(
+			"error listing %v, expected status 200 but got %v",
+			endpointOf(urlTemplate),
+			resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, response)
+}
+
 // CheckGrafanaHealth check health information about Grafana.
-func (r *GrafanaClientImpl) CheckGrafanaHealth() error {
+func (r *GrafanaClientImpl) CheckGrafanaHealth(ctx context.Context) error {
 	rawUrl := fmt.Sprintf(healthInfoUrl, r.url)
 
 	parsed, err := url.Parse(rawUrl)
@@ -148,14 +421,14 @@ func (r *GrafanaClientImpl) CheckGrafanaHealth() error {
 	}
 
 	parsed.User = url.UserPassword(r.user, r.password)
-	req, err := http.NewRequest("GET", parsed.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", parsed.String(), nil)
 	if err != nil {
 		return err
 	}
 
 	setHeaders(req)
 
-	resp, err := r.client.Do(req)
+	resp, err := r.do(req, healthInfoUrl)
 	if err != nil {
 		return err
 	}
@@ -170,10 +443,10 @@ func (r *GrafanaClientImpl) CheckGrafanaHealth() error {
 	return nil
 }
 
-func (r *GrafanaClientImpl) GetOrCreateNamespaceFolder(namespace string) (GrafanaFolderResponse, error) {
+func (r *GrafanaClientImpl) GetOrCreateNamespaceFolder(ctx context.Context, namespace string) (GrafanaFolderResponse, error) {
 	response := newFolderResponse()
 
-	folders, err := r.getAllFolders()
+	folders, err := r.getAllFolders(ctx)
 	if err != nil {
 		return response, err
 	}
@@ -203,14 +476,14 @@ func (r *GrafanaClientImpl) GetOrCreateNamespaceFolder(namespace string) (Grafan
 	}
 
 	parsed.User = url.UserPassword(r.user, r.password)
-	req, err := http.NewRequest("POST", parsed.String(), bytes.NewBuffer(raw))
+	req, err := http.NewRequestWithContext(ctx, "POST", parsed.String(), bytes.NewBuffer(raw))
 	if err != nil {
 		return response, err
 	}
 
 	setHeaders(req)
 
-	resp, err := r.client.Do(req)
+	resp, err := r.do(req, createOrUpdateFolderUrl)
 	if err != nil {
 		return response, err
 	}
@@ -232,8 +505,8 @@ func (r *GrafanaClientImpl) GetOrCreateNamespaceFolder(namespace string) (Grafan
 }
 
 // GetDashboardsByName get dashboards given by name.
-func (r *GrafanaClientImpl) GetDashboardsByName(name string) ([]GrafanaResponse, error) {
-	rawUrl := fmt.Sprintf("%s/api/search?query=%s", r.url, url.QueryEscape(name))
+func (r *GrafanaClientImpl) GetDashboardsByName(ctx context.Context, name string) ([]GrafanaResponse, error) {
+	rawUrl := fmt.Sprintf(searchDashboardsUrl, r.url, url.QueryEscape(name))
 	var response []GrafanaResponse
 
 	parsed, err := url.Parse(rawUrl)
@@ -242,14 +515,14 @@ func (r *GrafanaClientImpl) GetDashboardsByName(name string) ([]GrafanaResponse,
 	}
 
 	parsed.User = url.UserPassword(r.user, r.password)
-	req, err := http.NewRequest("GET", parsed.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", parsed.String(), nil)
 	if err != nil {
 		return response, err
 	}
 
 	setHeaders(req)
 
-	resp, err := r.client.Do(req)
+	resp, err := r.do(req, searchDashboardsUrl)
 	if err != nil {
 		return response, err
 	}
@@ -274,7 +547,7 @@ func (r *GrafanaClientImpl) GetDashboardsByName(name string) ([]GrafanaResponse,
 }
 
 // Submit dashboard json to grafana
-func (r *GrafanaClientImpl) CreateOrUpdateDashboard(dashboard []byte, folderId int64) (GrafanaResponse, error) {
+func (r *GrafanaClientImpl) CreateOrUpdateDashboard(ctx context.Context, dashboard []byte, folderId int64) (GrafanaResponse, error) {
 	rawUrl := fmt.Sprintf(createOrUpdateDashboardUrl, r.url)
 	response := newResponse()
 
@@ -298,14 +571,14 @@ func (r *GrafanaClientImpl) CreateOrUpdateDashboard(dashboard []byte, folderId i
 	}
 
 	parsed.User = url.UserPassword(r.user, r.password)
-	req, err := http.NewRequest("POST", parsed.String(), bytes.NewBuffer(raw))
+	req, err := http.NewRequestWithContext(ctx, "POST", parsed.String(), bytes.NewBuffer(raw))
 	if err != nil {
 		return response, err
 	}
 
 	setHeaders(req)
 
-	resp, err := r.client.Do(req)
+	resp, err := r.do(req, createOrUpdateDashboardUrl)
 	if err != nil {
 		return response, err
 	}
@@ -327,7 +600,7 @@ func (r *GrafanaClientImpl) CreateOrUpdateDashboard(dashboard []byte, folderId i
 }
 
 // Delete a dashboard given by a UID
-func (r *GrafanaClientImpl) DeleteDashboardByUID(UID string) (GrafanaResponse, error) {
+func (r *GrafanaClientImpl) DeleteDashboardByUID(ctx context.Context, UID string) (GrafanaResponse, error) {
 	rawUrl := fmt.Sprintf(deleteDashboardByUIDUrl, r.url, UID)
 	response := newResponse()
 
@@ -337,14 +610,14 @@ func (r *GrafanaClientImpl) DeleteDashboardByUID(UID string) (GrafanaResponse, e
 	}
 
 	parsed.User = url.UserPassword(r.user, r.password)
-	req, err := http.NewRequest("DELETE", parsed.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", parsed.String(), nil)
 	if err != nil {
 		return response, err
 	}
 
 	setHeaders(req)
 
-	resp, err := r.client.Do(req)
+	resp, err := r.do(req, deleteDashboardByUIDUrl)
 	if err != nil {
 		return response, err
 	}
@@ -395,7 +668,7 @@ func newResponse() GrafanaResponse {
 }
 
 // CreateDatasource Submit datasource json to grafana.
-func (r *GrafanaClientImpl) CreateDatasource(datasource []byte) (GrafanaResponse, error) {
+func (r *GrafanaClientImpl) CreateDatasource(ctx context.Context, datasource []byte) (GrafanaResponse, error) {
 	rawUrl := fmt.Sprintf(createDatasourceUrl, r.url)
 	response := newResponse()
 
@@ -405,14 +678,14 @@ func (r *GrafanaClientImpl) CreateDatasource(datasource []byte) (GrafanaResponse
 	}
 
 	parsed.User = url.UserPassword(r.user, r.password)
-	req, err := http.NewRequest("POST", parsed.String(), bytes.NewBuffer(datasource))
+	req, err := http.NewRequestWithContext(ctx, "POST", parsed.String(), bytes.NewBuffer(datasource))
 	if err != nil {
 		return response, err
 	}
 
 	setHeaders(req)
 
-	resp, err := r.client.Do(req)
+	resp, err := r.do(req, createDatasourceUrl)
 	if err != nil {
 		return response, err
 	}
@@ -437,7 +710,7 @@ func (r *GrafanaClientImpl) CreateDatasource(datasource []byte) (GrafanaResponse
 }
 
 // DeleteDatasourceByName Delete a datasource given by name.
-func (r *GrafanaClientImpl) DeleteDatasourceByName(name string) (GrafanaResponse, error) {
+func (r *GrafanaClientImpl) DeleteDatasourceByName(ctx context.Context, name string) (GrafanaResponse, error) {
 	rawUrl := fmt.Sprintf(deleteDatasourceByNameUrl, r.url, name)
 	response := newResponse()
 
@@ -447,14 +720,14 @@ func (r *GrafanaClientImpl) DeleteDatasourceByName(name string) (GrafanaResponse
 	}
 
 	parsed.User = url.UserPassword(r.user, r.password)
-	req, err := http.NewRequest("DELETE", parsed.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", parsed.String(), nil)
 	if err != nil {
 		return response, err
 	}
 
 	setHeaders(req)
 
-	resp, err := r.client.Do(req)
+	resp, err := r.do(req, deleteDatasourceByNameUrl)
 	if err != nil {
 		return response, err
 	}
@@ -479,7 +752,7 @@ func (r *GrafanaClientImpl) DeleteDatasourceByName(name string) (GrafanaResponse
 }
 
 // GetDatasourceByName get a datasource given by name.
-func (r *GrafanaClientImpl) GetDatasourceByName(name string) (GrafanaResponse, error) {
+func (r *GrafanaClientImpl) GetDatasourceByName(ctx context.Context, name string) (GrafanaResponse, error) {
 	rawUrl := fmt.Sprintf(deleteDatasourceByNameUrl, r.url, name)
 	response := newResponse()
 
@@ -489,14 +762,14 @@ func (r *GrafanaClientImpl) GetDatasourceByName(name string) (GrafanaResponse, e
 	}
 
 	parsed.User = url.UserPassword(r.user, r.password)
-	req, err := http.NewRequest("GET", parsed.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", parsed.String(), nil)
 	if err != nil {
 		return response, err
 	}
 
 	setHeaders(req)
 
-	resp, err := r.client.Do(req)
+	resp, err := r.do(req, deleteDatasourceByNameUrl)
 	if err != nil {
 		return response, err
 	}
@@ -519,3 +792,106 @@ func (r *GrafanaClientImpl) GetDatasourceByName(name string) (GrafanaResponse, e
 	err = json.Unmarshal(data, &response)
 	return response, err
 }
+
+// CheckDatasourceHealth asks grafana to test the connection to the datasource.
+// Grafana versions and datasources without a health endpoint are tested with a
+// request through the datasource proxy
+func (r *GrafanaClientImpl) CheckDatasourceHealth(ctx context.Context, id uint, uid string) (GrafanaHealthResponse, error) {
+	if uid != "" {
+		response, err := r.getDatasourceHealth(ctx, datasourceHealthByUidUrl, uid)
+		if err != NotFoundError {
+			return response, err
+		}
+	}
+
+	response, err := r.getDatasourceHealth(ctx, datasourceHealthUrl, strconv.FormatUint(uint64(id), 10))
+	if err != NotFoundError {
+		return response, err
+	}
+
+	return r.proxyDatasourceHealth(ctx, id)
+}
+
+func (r *GrafanaClientImpl) getDatasourceHealth(ctx context.Context, urlTemplate, param string) (GrafanaHealthResponse, error) {
+	rawUrl := fmt.Sprintf(urlTemplate, r.url, param)
+	response := GrafanaHealthResponse{}
+
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return response, err
+	}
+
+	parsed.User = url.UserPassword(r.user, r.password)
+	req, err := http.NewRequestWithContext(ctx, "GET", parsed.String(), nil)
+	if err != nil {
+		return response, err
+	}
+
+	setHeaders(req)
+
+	resp, err := r.do(req, urlTemplate)
+	if err != nil {
+		return response, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return response, NotFoundError
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return response, err
+	}
+
+	// Failed checks are reported with an error status code and the reason in the body
+	if err := json.Unmarshal(data, &response); err != nil || response.Status == "" {
+		if resp.StatusCode != http.StatusOK {
+			return response, fmt.Errorf(
+				"error checking datasource health, expected status 200 but got %v",
+				resp.StatusCode)
+		}
+		response.Status = HealthStatusOk
+	}
+	return response, nil
+}
+
+// Sends a request to the root of the datasource through the proxy of grafana.
+// Any response of the datasource except an authentication error is considered
+// healthy, gateway errors of the proxy are not
+func (r *GrafanaClientImpl) proxyDatasourceHealth(ctx context.Context, id uint) (GrafanaHealthResponse, error) {
+	rawUrl := fmt.Sprintf(datasourceProxyUrl, r.url, strconv.FormatUint(uint64(id), 10))
+	response := GrafanaHealthResponse{}
+
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return response, err
+	}
+
+	parsed.User = url.UserPassword(r.user, r.password)
+	req, err := http.NewRequestWithContext(ctx, "GET", parsed.String(), nil)
+	if err != nil {
+		return response, err
+	}
+
+	setHeaders(req)
+
+	resp, err := r.do(req, datasourceProxyUrl)
+	if err != nil {
+		return response, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		response.Status = HealthStatusError
+		response.Message = fmt.Sprintf("authentication failed with status %v", resp.StatusCode)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		response.Status = HealthStatusError
+		response.Message = fmt.Sprintf("datasource proxy returned status %v", resp.StatusCode)
+	default:
+		response.Status = HealthStatusOk
+		response.Message = "datasource is reachable"
+	}
+	return response, nil
+}
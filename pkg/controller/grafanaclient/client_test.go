@@ -0,0 +1,69 @@
+package grafanaClient
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetries(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewGrafanaClient(server.URL, "admin", "admin", ClientOptions{Timeout: time.Second, Retries: 2})
+	if err := client.RenameOrganization(context.Background(), 2, "team"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies) != 3 || bodies[2] != `{"name":"team"}` {
+		t.Errorf("unexpected requests %v", bodies)
+	}
+
+	// No attempts are left after the retries
+	bodies = nil
+	client = NewGrafanaClient(server.URL, "admin", "admin", ClientOptions{Timeout: time.Second, Retries: 1})
+	if err := client.RenameOrganization(context.Background(), 2, "team"); err == nil || len(bodies) != 2 {
+		t.Errorf("expected an error after 2 attempts, got %v after %v", err, len(bodies))
+	}
+}
+
+func TestNoRetriesOfSentPosts(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewGrafanaClient(server.URL, "admin", "admin", ClientOptions{Timeout: time.Second, Retries: 2})
+	if _, err := client.CreateDatasource(context.Background(), []byte(`{"name": "prometheus"}`)); err == nil || attempts != 1 {
+		t.Errorf("expected a single attempt, got %v attempts and error %v", attempts, err)
+	}
+}
+
+func TestRetriesStopWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := NewGrafanaClient(server.URL, "admin", "admin", ClientOptions{Timeout: time.Second, Retries: 10})
+	if err := client.CheckGrafanaHealth(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the deadline to stop the retries, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package grafanaClient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+const (
+	orgIdHeader              = "X-Grafana-Org-Id"
+	getOrganizationByNameUrl = "%s/api/orgs/name/%s"
+	createOrganizationUrl    = "%s/api/orgs"
+	listOrganizationsUrl     = "%s/api/orgs?perpage=1000"
+	organizationUrl          = "%s/api/orgs/%s"
+	organizationUsersUrl     = "%s/api/orgs/%s/users"
+	organizationUserUrl      = "%s/api/orgs/%s/users/%s"
+	organizationPrefsUrl     = "%s/api/org/preferences"
+	getDashboardByUIDUrl     = "%s/api/dashboards/uid/%s"
+)
+
+// Id of the organization grafana creates on startup. It can't be deleted
+const DefaultOrganizationId = 1
+
+type GrafanaOrganizationResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type GrafanaPreferences struct {
+	Theme           string `json:"theme"`
+	Timezone        string `json:"timezone"`
+	HomeDashboardId uint   `json:"homeDashboardId"`
+}
+
+type GrafanaOrganizationUser struct {
+	UserID uint   `json:"userId"`
+	Login  string `json:"login"`
+	Role   string `json:"role"`
+}
+
+type grafanaOrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+type grafanaOrganizationUserRequest struct {
+	LoginOrEmail string `json:"loginOrEmail"`
+	Role         string `json:"role"`
+}
+
+// WithOrganization returns a client that sends all requests to the given
+// organization. The client shares the connections and the rate limit of r
+func (r *GrafanaClientImpl) WithOrganization(orgId uint) GrafanaClient {
+	client := *r
+	client.orgId = orgId
+	return &client
+}
+
+// GetOrganizationByName looks up an organization by its name
+func (r *GrafanaClientImpl) GetOrganizationByName(ctx context.Context, name string) (GrafanaOrganizationResponse, error) {
+	response := GrafanaOrganizationResponse{}
+	status, err := r.call(ctx, "GET", getOrganizationByNameUrl, nil, &response, url.PathEscape(name))
+	if err == nil && status == http.StatusNotFound {
+		return response, NotFoundError
+	}
+	return response, expectOk(status, err, "error getting organization")
+}
+
+// ListOrganizations lists the organizations of the instance. The user of the
+// client has to be a grafana admin
+func (r *GrafanaClientImpl) ListOrganizations(ctx context.Context) ([]GrafanaOrganizationResponse, error) {
+	var organizations []GrafanaOrganizationResponse
+	err := r.list(ctx, listOrganizationsUrl, &organizations)
+	return organizations, err
+}
+
+// CreateOrganization creates an organization and returns its id. The user of the
+// client becomes an admin of the organization
+func (r *GrafanaClientImpl) CreateOrganization(ctx context.Context, name string) (uint, error) {
+	response := struct {
+		OrgId uint `json:"orgId"`
+	}{}
+	status, err := r.call(ctx, "POST", createOrganizationUrl, grafanaOrganizationRequest{Name: name}, &response)
+	if err == nil && status == http.StatusConflict {
+		return 0, ConflictError
+	}
+	return response.OrgId, expectOk(status, err, "error creating organization")
+}
+
+// DeleteOrganization deletes an organization with all its dashboards and datasources
+func (r *GrafanaClientImpl) DeleteOrganization(ctx context.Context, orgId uint) error {
+	status, err := r.call(ctx, "DELETE", organizationUrl, nil, nil, fmt.Sprint(orgId))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error deleting organization")
+}
+
+// RenameOrganization changes the name of an organization
+func (r *GrafanaClientImpl) RenameOrganization(ctx context.Context, orgId uint, name string) error {
+	status, err := r.call(ctx, "PUT", organizationUrl, grafanaOrganizationRequest{Name: name}, nil, fmt.Sprint(orgId))
+	return expectOk(status, err, "error renaming organization")
+}
+
+// AddOrganizationUser adds an existing user to an organization. Users that are
+// already members are left unchanged
+func (r *GrafanaClientImpl) AddOrganizationUser(ctx context.Context, orgId uint, login, role string) error {
+	status, err := r.call(ctx, "POST", organizationUsersUrl, grafanaOrganizationUserRequest{
+		LoginOrEmail: login,
+		Role:         role,
+	}, nil, fmt.Sprint(orgId))
+	if err == nil && status == http.StatusConflict {
+		return nil
+	}
+	return expectOk(status, err, "error adding organization user")
+}
+
+// ListOrganizationUsers returns the members of an organization
+func (r *GrafanaClientImpl) ListOrganizationUsers(ctx context.Context, orgId uint) ([]GrafanaOrganizationUser, error) {
+	var response []GrafanaOrganizationUser
+	status, err := r.call(ctx, "GET", organizationUsersUrl, nil, &response, fmt.Sprint(orgId))
+	return response, expectOk(status, err, "error listing organization users")
+}
+
+// UpdateOrganizationUser changes the role of a member of an organization
+func (r *GrafanaClientImpl) UpdateOrganizationUser(ctx context.Context, orgId, userId uint, role string) error {
+	status, err := r.call(ctx, "PATCH", organizationUserUrl, struct {
+		Role string `json:"role"`
+	}{Role: role}, nil, fmt.Sprint(orgId), fmt.Sprint(userId))
+	return expectOk(status, err, "error updating organization user")
+}
+
+// RemoveOrganizationUser removes a member from an organization
+func (r *GrafanaClientImpl) RemoveOrganizationUser(ctx context.Context, orgId, userId uint) error {
+	status, err := r.call(ctx, "DELETE", organizationUserUrl, nil, nil, fmt.Sprint(orgId), fmt.Sprint(userId))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error removing organization user")
+}
+
+// UpdateOrganizationPreferences replaces the preferences of the organization of
+// the client
+func (r *GrafanaClientImpl) UpdateOrganizationPreferences(ctx context.Context, preferences GrafanaPreferences) error {
+	status, err := r.call(ctx, "PUT", organizationPrefsUrl, preferences, nil)
+	return expectOk(status, err, "error updating organization preferences")
+}
+
+// GetDashboardByUID returns the id, uid and version of a dashboard
+func (r *GrafanaClientImpl) GetDashboardByUID(ctx context.Context, uid string) (GrafanaResponse, error) {
+	response := struct {
+		Dashboard GrafanaResponse `json:"dashboard"`
+	}{Dashboard: newResponse()}
+	status, err := r.call(ctx, "GET", getDashboardByUIDUrl, nil, &response, uid)
+	if err == nil && status == http.StatusNotFound {
+		return response.Dashboard, NotFoundError
+	}
+	return response.Dashboard, expectOk(status, err, "error getting dashboard")
+}
+
+// call sends a request with an optional json body and decodes the json response
+// of successful requests. The url template is filled with the url of grafana and
+// the params
+func (r *GrafanaClientImpl) call(ctx context.Context, method, urlTemplate string, body interface{}, response interface{}, params ...string) (int, error) {
+	args := []interface{}{r.url}
+	for _, param := range params {
+		args = append(args, param)
+	}
+	rawUrl := fmt.Sprintf(urlTemplate, args...)
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return 0, err
+	}
+
+	var content io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		content = bytes.NewReader(raw)
+	}
+
+	parsed.User = url.UserPassword(r.user, r.password)
+	req, err := http.NewRequestWithContext(ctx, method, parsed.String(), content)
+	if err != nil {
+		return 0, err
+	}
+
+	setHeaders(req)
+
+	resp, err := r.do(req, urlTemplate)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+
+	if (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated) && response != nil && len(data) > 0 {
+		err = json.Unmarshal(data, response)
+	}
+	return resp.StatusCode, err
+}
+
+func expectOk(status int, err error, message string) error {
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("%v, expected status 200 but got %v", message, status)
+	}
+	return nil
+}
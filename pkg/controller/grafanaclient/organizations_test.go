@@ -0,0 +1,41 @@
+package grafanaClient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOrganizationHeader(t *testing.T) {
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		headers = append(headers, req.Header.Get(orgIdHeader))
+		switch req.URL.Path {
+		case "/api/orgs/name/team a":
+			w.Write([]byte(`{"id": 2, "name": "team a"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGrafanaClient(server.URL, "admin", "admin", ClientOptions{Timeout: time.Second})
+	org, err := client.GetOrganizationByName(context.Background(), "team a")
+	if err != nil || org.ID != 2 {
+		t.Fatalf("unexpected organization %+v, error %v", org, err)
+	}
+
+	if _, err := client.WithOrganization(org.ID).GetDashboardByUID(context.Background(), "home"); err != NotFoundError {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if _, err := client.GetOrganizationByName(context.Background(), "team b"); err != NotFoundError {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	// Only the organization client selects an organization
+	if len(headers) != 3 || headers[0] != "" || headers[1] != "2" || headers[2] != "" {
+		t.Errorf("unexpected organization headers %q", headers)
+	}
+}
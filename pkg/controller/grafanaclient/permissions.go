@@ -0,0 +1,48 @@
+package grafanaClient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+const dashboardPermissionsUrl = "%s/api/dashboards/uid/%s/permissions"
+
+// A permission of a team, a user or an organization role on a dashboard
+type GrafanaDashboardPermission struct {
+	UserID     uint   `json:"userId,omitempty"`
+	TeamID     uint   `json:"teamId,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Permission int    `json:"permission"`
+	// Set for the permissions of the folder of the dashboard
+	Inherited bool `json:"inherited,omitempty"`
+}
+
+// GetDashboardPermissions returns the permissions of a dashboard, including the
+// permissions inherited from its folder
+func (r *GrafanaClientImpl) GetDashboardPermissions(ctx context.Context, uid string) ([]GrafanaDashboardPermission, error) {
+	var response []GrafanaDashboardPermission
+	status, err := r.call(ctx, "GET", dashboardPermissionsUrl, nil, &response, url.PathEscape(uid))
+	if err == nil && status == http.StatusNotFound {
+		return nil, NotFoundError
+	}
+	return response, expectOk(status, err, "error getting dashboard permissions")
+}
+
+// UpdateDashboardPermissions replaces all permissions of a dashboard that are not
+// inherited from its folder
+func (r *GrafanaClientImpl) UpdateDashboardPermissions(ctx context.Context, uid string, permissions []GrafanaDashboardPermission) error {
+	items := make([]GrafanaDashboardPermission, 0, len(permissions))
+	for _, permission := range permissions {
+		permission.Inherited = false
+		items = append(items, permission)
+	}
+
+	status, err := r.call(ctx, "POST", dashboardPermissionsUrl, struct {
+		Items []GrafanaDashboardPermission `json:"items"`
+	}{Items: items}, nil, url.PathEscape(uid))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error updating dashboard permissions")
+}
@@ -0,0 +1,116 @@
+package grafanaClient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	searchServiceAccountsUrl = "%s/api/serviceaccounts/search?query=%s"
+	createServiceAccountUrl  = "%s/api/serviceaccounts"
+	serviceAccountUrl        = "%s/api/serviceaccounts/%s"
+	serviceAccountTokensUrl  = "%s/api/serviceaccounts/%s/tokens"
+	serviceAccountTokenUrl   = "%s/api/serviceaccounts/%s/tokens/%s"
+)
+
+type GrafanaServiceAccountResponse struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	Role       string `json:"role"`
+	IsDisabled bool   `json:"isDisabled"`
+}
+
+type GrafanaServiceAccountRequest struct {
+	Name       string `json:"name"`
+	Role       string `json:"role"`
+	IsDisabled bool   `json:"isDisabled"`
+}
+
+type GrafanaServiceAccountToken struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	// Only returned when the token is created
+	Key string `json:"key,omitempty"`
+}
+
+// GetServiceAccountByName looks up a service account of the organization of the
+// client by its name
+func (r *GrafanaClientImpl) GetServiceAccountByName(ctx context.Context, name string) (GrafanaServiceAccountResponse, error) {
+	response := struct {
+		ServiceAccounts []GrafanaServiceAccountResponse `json:"serviceAccounts"`
+	}{}
+	status, err := r.call(ctx, "GET", searchServiceAccountsUrl, nil, &response, url.QueryEscape(name))
+	if err := expectOk(status, err, "error searching service accounts"); err != nil {
+		return GrafanaServiceAccountResponse{}, err
+	}
+
+	for _, account := range response.ServiceAccounts {
+		if account.Name == name {
+			return account, nil
+		}
+	}
+	return GrafanaServiceAccountResponse{}, NotFoundError
+}
+
+// CreateServiceAccount creates a service account in the organization of the
+// client and returns its id
+func (r *GrafanaClientImpl) CreateServiceAccount(ctx context.Context, account GrafanaServiceAccountRequest) (uint, error) {
+	response := GrafanaServiceAccountResponse{}
+	status, err := r.call(ctx, "POST", createServiceAccountUrl, account, &response)
+	if err == nil && status == http.StatusCreated {
+		return response.ID, nil
+	}
+	if err == nil && (status == http.StatusConflict || status == http.StatusBadRequest) {
+		// Returned by grafana if the name is taken
+		return 0, ConflictError
+	}
+	return response.ID, expectOk(status, err, "error creating service account")
+}
+
+// UpdateServiceAccount changes the name, role and disabled state of a service
+// account
+func (r *GrafanaClientImpl) UpdateServiceAccount(ctx context.Context, accountId uint, account GrafanaServiceAccountRequest) error {
+	status, err := r.call(ctx, "PATCH", serviceAccountUrl, account, nil, fmt.Sprint(accountId))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error updating service account")
+}
+
+// DeleteServiceAccount deletes a service account with all its tokens
+func (r *GrafanaClientImpl) DeleteServiceAccount(ctx context.Context, accountId uint) error {
+	status, err := r.call(ctx, "DELETE", serviceAccountUrl, nil, nil, fmt.Sprint(accountId))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error deleting service account")
+}
+
+// ListServiceAccountTokens returns the tokens of a service account without
+// their keys
+func (r *GrafanaClientImpl) ListServiceAccountTokens(ctx context.Context, accountId uint) ([]GrafanaServiceAccountToken, error) {
+	var response []GrafanaServiceAccountToken
+	status, err := r.call(ctx, "GET", serviceAccountTokensUrl, nil, &response, fmt.Sprint(accountId))
+	return response, expectOk(status, err, "error listing service account tokens")
+}
+
+// CreateServiceAccountToken creates a token that does not expire and returns it
+// with its key
+func (r *GrafanaClientImpl) CreateServiceAccountToken(ctx context.Context, accountId uint, name string) (GrafanaServiceAccountToken, error) {
+	response := GrafanaServiceAccountToken{}
+	status, err := r.call(ctx, "POST", serviceAccountTokensUrl, struct {
+		Name string `json:"name"`
+	}{Name: name}, &response, fmt.Sprint(accountId))
+	return response, expectOk(status, err, "error creating service account token")
+}
+
+// DeleteServiceAccountToken revokes a token of a service account
+func (r *GrafanaClientImpl) DeleteServiceAccountToken(ctx context.Context, accountId, tokenId uint) error {
+	status, err := r.call(ctx, "DELETE", serviceAccountTokenUrl, nil, nil, fmt.Sprint(accountId), fmt.Sprint(tokenId))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error deleting service account token")
+}
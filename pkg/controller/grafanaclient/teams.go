@@ -0,0 +1,141 @@
+package grafanaClient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	searchTeamsUrl = "%s/api/teams/search?name=%s"
+	createTeamUrl  = "%s/api/teams"
+	teamUrl        = "%s/api/teams/%s"
+	teamMembersUrl = "%s/api/teams/%s/members"
+	teamMemberUrl  = "%s/api/teams/%s/members/%s"
+	teamGroupsUrl  = "%s/api/teams/%s/groups"
+	teamGroupUrl   = "%s/api/teams/%s/groups/%s"
+)
+
+type GrafanaTeamResponse struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type grafanaTeamRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type GrafanaTeamMember struct {
+	UserID uint   `json:"userId"`
+	Login  string `json:"login"`
+}
+
+// GetTeamByName looks up a team of the organization of the client by its name
+func (r *GrafanaClientImpl) GetTeamByName(ctx context.Context, name string) (GrafanaTeamResponse, error) {
+	response := struct {
+		Teams []GrafanaTeamResponse `json:"teams"`
+	}{}
+	status, err := r.call(ctx, "GET", searchTeamsUrl, nil, &response, url.QueryEscape(name))
+	if err := expectOk(status, err, "error searching teams"); err != nil {
+		return GrafanaTeamResponse{}, err
+	}
+
+	for _, team := range response.Teams {
+		if team.Name == name {
+			return team, nil
+		}
+	}
+	return GrafanaTeamResponse{}, NotFoundError
+}
+
+// ListTeamMembers returns the members of a team
+func (r *GrafanaClientImpl) ListTeamMembers(ctx context.Context, teamId uint) ([]GrafanaTeamMember, error) {
+	var response []GrafanaTeamMember
+	status, err := r.call(ctx, "GET", teamMembersUrl, nil, &response, fmt.Sprint(teamId))
+	return response, expectOk(status, err, "error listing team members")
+}
+
+// CreateTeam creates a team in the organization of the client and returns its id
+func (r *GrafanaClientImpl) CreateTeam(ctx context.Context, name, email string) (uint, error) {
+	response := struct {
+		TeamId uint `json:"teamId"`
+	}{}
+	status, err := r.call(ctx, "POST", createTeamUrl, grafanaTeamRequest{Name: name, Email: email}, &response)
+	if err == nil && status == http.StatusConflict {
+		return 0, ConflictError
+	}
+	return response.TeamId, expectOk(status, err, "error creating team")
+}
+
+// UpdateTeam changes the name and email of a team
+func (r *GrafanaClientImpl) UpdateTeam(ctx context.Context, teamId uint, name, email string) error {
+	status, err := r.call(ctx, "PUT", teamUrl, grafanaTeamRequest{Name: name, Email: email}, nil, fmt.Sprint(teamId))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error updating team")
+}
+
+// DeleteTeam deletes a team and its permissions
+func (r *GrafanaClientImpl) DeleteTeam(ctx context.Context, teamId uint) error {
+	status, err := r.call(ctx, "DELETE", teamUrl, nil, nil, fmt.Sprint(teamId))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error deleting team")
+}
+
+// AddTeamMember adds a user to a team
+func (r *GrafanaClientImpl) AddTeamMember(ctx context.Context, teamId, userId uint) error {
+	status, err := r.call(ctx, "POST", teamMembersUrl, struct {
+		UserId uint `json:"userId"`
+	}{UserId: userId}, nil, fmt.Sprint(teamId))
+	return expectOk(status, err, "error adding team member")
+}
+
+// RemoveTeamMember removes a user from a team
+func (r *GrafanaClientImpl) RemoveTeamMember(ctx context.Context, teamId, userId uint) error {
+	status, err := r.call(ctx, "DELETE", teamMemberUrl, nil, nil, fmt.Sprint(teamId), fmt.Sprint(userId))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error removing team member")
+}
+
+// ListTeamGroups returns the external groups synced to a team. Only supported
+// by grafana enterprise
+func (r *GrafanaClientImpl) ListTeamGroups(ctx context.Context, teamId uint) ([]string, error) {
+	var response []struct {
+		GroupId string `json:"groupId"`
+	}
+	status, err := r.call(ctx, "GET", teamGroupsUrl, nil, &response, fmt.Sprint(teamId))
+	if err := expectOk(status, err, "error listing team groups"); err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(response))
+	for _, group := range response {
+		groups = append(groups, group.GroupId)
+	}
+	return groups, nil
+}
+
+// AddTeamGroup syncs the members of an external group to a team
+func (r *GrafanaClientImpl) AddTeamGroup(ctx context.Context, teamId uint, group string) error {
+	status, err := r.call(ctx, "POST", teamGroupsUrl, struct {
+		GroupId string `json:"groupId"`
+	}{GroupId: group}, nil, fmt.Sprint(teamId))
+	return expectOk(status, err, "error adding team group")
+}
+
+// RemoveTeamGroup stops syncing an external group to a team
+func (r *GrafanaClientImpl) RemoveTeamGroup(ctx context.Context, teamId uint, group string) error {
+	status, err := r.call(ctx, "DELETE", teamGroupUrl, nil, nil, fmt.Sprint(teamId), url.PathEscape(group))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error removing team group")
+}
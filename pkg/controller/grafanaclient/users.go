@@ -0,0 +1,90 @@
+package grafanaClient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	lookupUserUrl          = "%s/api/users/lookup?loginOrEmail=%s"
+	userUrl                = "%s/api/users/%s"
+	createUserUrl          = "%s/api/admin/users"
+	adminUserUrl           = "%s/api/admin/users/%s"
+	adminUserPasswordUrl   = "%s/api/admin/users/%s/password"
+	adminUserPermissionUrl = "%s/api/admin/users/%s/permissions"
+)
+
+type GrafanaUserResponse struct {
+	ID             uint   `json:"id"`
+	Login          string `json:"login"`
+	Email          string `json:"email"`
+	Name           string `json:"name"`
+	IsGrafanaAdmin bool   `json:"isGrafanaAdmin"`
+}
+
+type GrafanaUserRequest struct {
+	Login    string `json:"login"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password,omitempty"`
+}
+
+// GetUserByLogin looks up a user by login or email
+func (r *GrafanaClientImpl) GetUserByLogin(ctx context.Context, loginOrEmail string) (GrafanaUserResponse, error) {
+	response := GrafanaUserResponse{}
+	status, err := r.call(ctx, "GET", lookupUserUrl, nil, &response, url.QueryEscape(loginOrEmail))
+	if err == nil && status == http.StatusNotFound {
+		return response, NotFoundError
+	}
+	return response, expectOk(status, err, "error getting user")
+}
+
+// CreateUser creates a user with a password and returns its id
+func (r *GrafanaClientImpl) CreateUser(ctx context.Context, user GrafanaUserRequest) (uint, error) {
+	response := struct {
+		ID uint `json:"id"`
+	}{}
+	status, err := r.call(ctx, "POST", createUserUrl, user, &response)
+	if err == nil && status == http.StatusPreconditionFailed {
+		// Returned by grafana if the login or email is taken
+		return 0, ConflictError
+	}
+	return response.ID, expectOk(status, err, "error creating user")
+}
+
+// UpdateUser changes the login, email and name of a user
+func (r *GrafanaClientImpl) UpdateUser(ctx context.Context, userId uint, user GrafanaUserRequest) error {
+	user.Password = ""
+	status, err := r.call(ctx, "PUT", userUrl, user, nil, fmt.Sprint(userId))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error updating user")
+}
+
+// UpdateUserPassword sets the password of a user
+func (r *GrafanaClientImpl) UpdateUserPassword(ctx context.Context, userId uint, password string) error {
+	status, err := r.call(ctx, "PUT", adminUserPasswordUrl, struct {
+		Password string `json:"password"`
+	}{Password: password}, nil, fmt.Sprint(userId))
+	return expectOk(status, err, "error updating user password")
+}
+
+// UpdateUserPermissions grants or revokes the permissions of a grafana server admin
+func (r *GrafanaClientImpl) UpdateUserPermissions(ctx context.Context, userId uint, grafanaAdmin bool) error {
+	status, err := r.call(ctx, "PUT", adminUserPermissionUrl, struct {
+		IsGrafanaAdmin bool `json:"isGrafanaAdmin"`
+	}{IsGrafanaAdmin: grafanaAdmin}, nil, fmt.Sprint(userId))
+	return expectOk(status, err, "error updating user permissions")
+}
+
+// DeleteUser deletes a user from all organizations and teams
+func (r *GrafanaClientImpl) DeleteUser(ctx context.Context, userId uint) error {
+	status, err := r.call(ctx, "DELETE", adminUserUrl, nil, nil, fmt.Sprint(userId))
+	if err == nil && status == http.StatusNotFound {
+		return NotFoundError
+	}
+	return expectOk(status, err, "error deleting user")
+}
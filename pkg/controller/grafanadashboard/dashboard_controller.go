@@ -3,7 +3,7 @@ package grafanadashboard
 import (
 	"context"
 	"fmt"
-	"time"
+	"reflect"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -22,12 +22,12 @@ import (
 	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
 	"github.com/ucloud/grafana-operator/pkg/controller/common"
 	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
 )
 
 const (
 	ControllerName                 = "controller_grafanadashboard"
 	dashboardFinalizer             = "finalizer.grafanadashboards.monitor.kun"
-	defaultreconcileTime           = 10 * time.Second
 	defaultMaxConcurrentReconciles = 10
 )
 
@@ -67,6 +67,11 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	// Watch for changes to primary resource GrafanaDashboard
 	err = c.Watch(&source.Kind{Type: &grafanav1alpha1.GrafanaDashboard{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	err = watchDependencies(mgr, c)
 	if err == nil {
 		log.Info("Starting dashboard controller")
 	}
@@ -113,6 +118,7 @@ func (r *ReconcileGrafanaDashboard) Reconcile(request reconcile.Request) (reconc
 			// finalization logic fails, don't remove the finalizer so
 			// that we can retry during the next reconciliation.
 			if err := r.finalize(reqLogger, instance); err != nil {
+				r.manageError(instance, err)
 				return reconcile.Result{}, err
 			}
 
@@ -123,6 +129,7 @@ func (r *ReconcileGrafanaDashboard) Reconcile(request reconcile.Request) (reconc
 			if err != nil {
 				return reconcile.Result{}, err
 			}
+			metrics.ForgetResource(metrics.KindGrafanaDashboard, instance.Namespace, instance.Name)
 		}
 		return reconcile.Result{}, nil
 	}
@@ -141,7 +148,12 @@ func (r *ReconcileGrafanaDashboard) Reconcile(request reconcile.Request) (reconc
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{RequeueAfter: defaultreconcileTime}, nil
+	// Failed syncs are retried soon, otherwise the dashboard is only synced again
+	// when a watched resource changes or after the resync period
+	if instance.Status.Phase == grafanav1alpha1.PhaseFailing {
+		return reconcile.Result{RequeueAfter: config.RequeueDelay}, nil
+	}
+	return reconcile.Result{RequeueAfter: common.ResyncPeriod()}, nil
 }
 
 // check if the labels on a namespace match a given label selector
@@ -162,13 +174,18 @@ func (r *ReconcileGrafanaDashboard) checkNamespaceLabels(dashboard *grafanav1alp
 }
 
 // Handle success case: update dashboard metadata (id, uid) and update the list
-// of plugins
-func (r *ReconcileGrafanaDashboard) manageSuccess(dashboard *grafanav1alpha1.GrafanaDashboard) {
-	msg := fmt.Sprintf("dashboard %v/%v successfully submitted",
-		dashboard.Namespace,
-		dashboard.Name)
-	r.recorder.Event(dashboard, "Normal", "Success", msg)
-	log.Info(msg)
+// of plugins. The dashboard is in sync with every instance, submissions are
+// reported as events
+func (r *ReconcileGrafanaDashboard) manageSuccess(dashboard *grafanav1alpha1.GrafanaDashboard, submitted bool) {
+	if submitted {
+		msg := fmt.Sprintf("dashboard %v/%v successfully submitted",
+			dashboard.Namespace,
+			dashboard.Name)
+		r.recorder.Event(dashboard, "Normal", "Success", msg)
+		log.Info(msg)
+	}
+	r.updateStatus(dashboard, grafanav1alpha1.PhaseReconciling, "success", nil)
+	metrics.SetLastSuccessfulSync(metrics.KindGrafanaDashboard, dashboard.Namespace, dashboard.Name)
 	r.config.AddDashboard(dashboard)
 	r.config.SetPluginsFor(dashboard)
 }
@@ -177,9 +194,71 @@ func (r *ReconcileGrafanaDashboard) manageSuccess(dashboard *grafanav1alpha1.Gra
 func (r *ReconcileGrafanaDashboard) manageError(dashboard *grafanav1alpha1.GrafanaDashboard, issue error) {
 	r.recorder.Event(dashboard, "Warning", "ProcessingError", issue.Error())
 
-	// Ignore conclicts. Resource might just be outdated.
+	// Ignore conflicts. Resource might just be outdated.
 	if errors.IsConflict(issue) {
 		return
 	}
 	log.Error(issue, "error updating dashboard")
+	r.updateStatus(dashboard, grafanav1alpha1.PhaseFailing, issue.Error(), issue)
+}
+
+// Only write the status when it changed to not trigger another reconciliation
+func (r *ReconcileGrafanaDashboard) updateStatus(dashboard *grafanav1alpha1.GrafanaDashboard, phase grafanav1alpha1.StatusPhase, message string, issue error) {
+	previous := dashboard.Status.DeepCopy()
+	status := grafanav1alpha1.GrafanaDashboardStatus{
+		Phase:      phase,
+		Message:    message,
+		UID:        dashboard.UID(),
+		Conditions: previous.Conditions,
+		Instances:  previous.Instances,
+	}
+	grafanav1alpha1.SetSyncedCondition(&status.Conditions, dashboard.Generation, issue)
+	grafanav1alpha1.SetReadyCondition(&status.Conditions, dashboard.Generation, grafanav1alpha1.ConditionSynced)
+	if reflect.DeepEqual(dashboard.Status, status) {
+		return
+	}
+
+	dashboard.Status = status
+	err := r.client.Status().Update(r.context, dashboard)
+	if err != nil && !errors.IsConflict(err) {
+		log.Error(err, "error updating dashboard status")
+	}
+}
+
+// Records the sync state of the dashboard in every grafana instance, read by the
+// grafana controller, and whether its permissions are applied
+func (r *ReconcileGrafanaDashboard) updateInstances(dashboard *grafanav1alpha1.GrafanaDashboard, instances []grafanav1alpha1.GrafanaInstanceStatus, permissionsErr error) {
+	previous := dashboard.Status.DeepCopy()
+	dashboard.Status.Instances = instances
+	setPermissionsCondition(dashboard, permissionsErr)
+	if reflect.DeepEqual(*previous, dashboard.Status) {
+		return
+	}
+
+	err := r.client.Status().Update(r.context, dashboard)
+	if err != nil && !errors.IsConflict(err) {
+		log.Error(err, "error updating dashboard status")
+	}
+}
+
+// The condition records that permissions were applied. It is kept after the
+// permissions were removed from the spec until they were reset in every instance
+func setPermissionsCondition(dashboard *grafanav1alpha1.GrafanaDashboard, issue error) {
+	if len(dashboard.Spec.Permissions) == 0 && issue == nil {
+		grafanav1alpha1.RemoveCondition(&dashboard.Status.Conditions, grafanav1alpha1.ConditionPermissionsApplied)
+		return
+	}
+
+	condition := grafanav1alpha1.Condition{
+		Type:               grafanav1alpha1.ConditionPermissionsApplied,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: dashboard.Generation,
+		Reason:             grafanav1alpha1.ReasonReconcileSucceeded,
+	}
+	if issue != nil {
+		condition.Status = v1.ConditionFalse
+		condition.Reason = grafanav1alpha1.ReasonReconcileFailed
+		condition.Message = issue.Error()
+	}
+	grafanav1alpha1.SetCondition(&dashboard.Status.Conditions, condition)
 }
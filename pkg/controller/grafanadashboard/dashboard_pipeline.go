@@ -9,12 +9,15 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-jsonnet"
 	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
 	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
@@ -138,11 +141,57 @@ func (r *DashboardPipelineImpl) loadJsonnet(source string) (string, error) {
 
 	vm := jsonnet.MakeVM()
 
-	vm.Importer(&jsonnet.FileImporter{
-		JPaths: []string{jsonnetLocation},
-	})
+	vm.Importer(newLibraryImporter(jsonnetLocation))
 
-	return vm.EvaluateSnippet(r.Dashboard.Name, source)
+	start := time.Now()
+	result, err := vm.EvaluateSnippet(r.Dashboard.Name, source)
+	metrics.ObserveJsonnetEvaluation(r.Dashboard.Namespace, time.Since(start), err)
+
+	return result, err
+}
+
+// Imports files from the jsonnet library location only. Absolute paths and paths
+// leaving the location are rejected, they would make any file readable by the
+// operator part of the dashboard
+type libraryImporter struct {
+	location string
+	files    *jsonnet.FileImporter
+}
+
+func newLibraryImporter(location string) *libraryImporter {
+	return &libraryImporter{
+		location: path.Clean(location),
+		files:    &jsonnet.FileImporter{},
+	}
+}
+
+func (i *libraryImporter) inLocation(p string) bool {
+	return strings.HasPrefix(p, i.location+"/")
+}
+
+// Imports are resolved relative to the importing library and then relative to
+// the library location, like the jsonnet library paths
+func (i *libraryImporter) Import(importedFrom, importedPath string) (jsonnet.Contents, string, error) {
+	if path.IsAbs(importedPath) {
+		return jsonnet.Contents{}, "", fmt.Errorf("import %v: absolute paths are not allowed", importedPath)
+	}
+
+	var candidates []string
+	if i.inLocation(importedFrom) {
+		candidates = append(candidates, path.Join(path.Dir(importedFrom), importedPath))
+	}
+	candidates = append(candidates, path.Join(i.location, importedPath))
+
+	for _, candidate := range candidates {
+		if !i.inLocation(candidate) {
+			continue
+		}
+
+		if contents, foundAt, err := i.files.Import("", candidate); err == nil {
+			return contents, foundAt, nil
+		}
+	}
+	return jsonnet.Contents{}, "", fmt.Errorf("import %v: not found in the jsonnet libraries", importedPath)
 }
 
 // Try to obtain the dashboard json from a provided url
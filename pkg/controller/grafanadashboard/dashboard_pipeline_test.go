@@ -0,0 +1,61 @@
+package grafanadashboard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-jsonnet"
+)
+
+func TestLibraryImporter(t *testing.T) {
+	root, err := ioutil.TempDir("", "jsonnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	// A file readable by the operator next to the library location
+	secret := filepath.Join(root, "token")
+	if err := ioutil.WriteFile(secret, []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	location := filepath.Join(root, "jsonnet")
+	if err := os.MkdirAll(filepath.Join(location, "grafonnet"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"grafonnet/grafana.libsonnet":   `{ dashboard: import "dashboard.libsonnet" }`,
+		"grafonnet/dashboard.libsonnet": `{ title: "dashboard" }`,
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(location, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(newLibraryImporter(location))
+
+	result, err := vm.EvaluateSnippet("dashboard", `(import "grafonnet/grafana.libsonnet").dashboard`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, `"dashboard"`) {
+		t.Errorf("unexpected result %v", result)
+	}
+
+	outside := []string{
+		`importstr "` + secret + `"`,
+		`importstr "../token"`,
+		`importstr "grafonnet/../../token"`,
+	}
+	for _, snippet := range outside {
+		if _, err := vm.EvaluateSnippet("dashboard", snippet); err == nil {
+			t.Errorf("%v: expected the import to be rejected", snippet)
+		}
+	}
+}
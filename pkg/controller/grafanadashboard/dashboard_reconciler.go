@@ -1,11 +1,21 @@
 package grafanadashboard
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
 	"github.com/go-logr/logr"
 	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
 
 	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
 	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
 )
 
 func (r *ReconcileGrafanaDashboard) reconcile(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaDashboard) error {
@@ -15,68 +25,258 @@ func (r *ReconcileGrafanaDashboard) reconcile(reqLogger logr.Logger, cr *grafana
 		return err
 	}
 
+	if len(matchedGrafs) == 0 {
+		return nil
+	}
+
+	// The dashboard json only depends on the custom resource and its sources, the
+	// same json is submitted to every grafana
+	pipeline := NewDashboardPipeline(r.client, cr)
+	processed, err := pipeline.ProcessDashboard()
+	if err != nil {
+		reqLogger.Error(err, "cannot process dashboard")
+		for _, graf := range matchedGrafs {
+			metrics.DashboardSyncs.WithLabelValues(graf.Namespace, graf.Name, metrics.ResultFailure).Inc()
+		}
+		r.manageError(cr, err)
+		return nil
+	}
+
+	if processed == nil {
+		return nil
+	}
+	hash := contentHash(processed)
+
+	// Every instance is synced before the phase is set, the dashboard is only
+	// ready once it is in sync with all of them
+	instances := cr.Status.DeepCopy().Instances
+	var failed, permissionsFailed []string
+	var issue error
+	submitted := false
 	for _, graf := range matchedGrafs {
 		reqLogger.V(3).Info("reconcile dashboard for grafana", "grafanaName", graf.Name)
-		// Read current state
-		state := common.NewClusterState()
-		err = state.Read(r.context, graf, r.client)
+		updated, err := r.syncDashboard(reqLogger, cr, graf, processed, hash, &instances)
 		if err != nil {
-			reqLogger.Error(err, "error reading state")
+			if _, ok := err.(*permissionsError); ok {
+				permissionsFailed = append(permissionsFailed, graf.Name)
+			}
+			metrics.DashboardSyncs.WithLabelValues(graf.Namespace, graf.Name, metrics.ResultFailure).Inc()
+			failed = append(failed, graf.Name)
+			issue = err
 			continue
 		}
-
-		client, err := common.NewGrafanaClient(graf, state)
-		if err != nil {
-			reqLogger.Error(err, "newGrafanaClient failed")
-			continue
+		if updated {
+			metrics.DashboardSyncs.WithLabelValues(graf.Namespace, graf.Name, metrics.ResultSuccess).Inc()
+			submitted = true
 		}
+	}
 
-		dashboards, err := client.GetDashboardsByName(cr.DashboardName())
-		if err != nil {
-			reqLogger.Error(err, "cannot get dashboard")
-			r.manageError(cr, err)
-			continue
-		}
+	var permissionsErr error
+	if len(permissionsFailed) > 0 {
+		permissionsErr = fmt.Errorf("cannot apply permissions in grafana %v", strings.Join(permissionsFailed, ", "))
+	}
+	if permissionsErr == nil && len(failed) > 0 && resetPermissions(cr) {
+		// Keep the condition to reset the permissions in the failed instances
+		permissionsErr = fmt.Errorf("cannot reset permissions in grafana %v", strings.Join(failed, ", "))
+	}
+	r.updateInstances(cr, instances, permissionsErr)
 
-		if len(dashboards) > 0 {
-			continue
-		}
+	if len(failed) > 0 {
+		r.manageError(cr, fmt.Errorf("cannot sync dashboard to grafana %v: %v", strings.Join(failed, ", "), issue))
+		return nil
+	}
+	r.manageSuccess(cr, submitted)
+	return nil
+}
+
+// Failure to apply the permissions of a dashboard that was submitted
+type permissionsError struct {
+	error
+}
+
+// Submits the dashboard to a single grafana instance and applies its permissions.
+// Unready instances fail the sync, it is retried soon. Returns true if the
+// dashboard or its permissions were updated
+func (r *ReconcileGrafanaDashboard) syncDashboard(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaDashboard, graf *grafanav1alpha1.Grafana, processed []byte, hash string, instances *[]grafanav1alpha1.GrafanaInstanceStatus) (bool, error) {
+	client, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
+	if err != nil {
+		reqLogger.Error(err, "grafana not ready", "grafana", graf.Name)
+		return false, err
+	}
+
+	client, err = common.ResourceClient(r.context, client, graf, cr.Namespace, cr.Spec.Organization)
+	if err != nil {
+		reqLogger.Error(err, "cannot get organization", "grafana", graf.Name)
+		return false, err
+	}
 
-		pipeline := NewDashboardPipeline(r.client, cr)
-		processed, err := pipeline.ProcessDashboard()
+	dashboards, err := client.GetDashboardsByName(r.context, cr.DashboardName())
+	if err != nil {
+		reqLogger.Error(err, "cannot get dashboard")
+		return false, err
+	}
 
+	// Dashboards are only submitted again if their json changed since the
+	// last submission, e.g. because the referenced configmap was updated
+	previous := grafanav1alpha1.FindInstanceStatus(*instances, graf.Namespace, graf.Name)
+	if len(dashboards) > 0 && previous != nil && previous.Hash == hash {
+		grafanav1alpha1.SetInstanceStatus(instances, common.InstanceStatus(graf, dashboards[0], *instances, false))
+		// Permissions changed in grafana are reverted
+		updated, err := applyPermissions(r.context, client, cr.Spec.Permissions, *dashboards[0].UID, resetPermissions(cr))
 		if err != nil {
-			reqLogger.Error(err, "cannot process dashboard")
-			r.manageError(cr, err)
-			continue
+			reqLogger.Error(err, "cannot apply dashboard permissions", "grafana", graf.Name)
+			return false, &permissionsError{err}
 		}
+		return updated, nil
+	}
 
-		if processed == nil {
-			continue
+	folder, err := client.GetOrCreateNamespaceFolder(r.context, cr.Namespace)
+	if err != nil {
+		reqLogger.Error(err, "failed to get or create namespace folder")
+		return false, err
+	}
+
+	var folderID int64 = 0
+	if folder.ID != nil {
+		folderID = *folder.ID
+	}
+
+	dashboard, err := tagDashboard(processed, graf)
+	if err != nil {
+		reqLogger.Error(err, "cannot tag dashboard")
+		return false, err
+	}
+
+	response, err := client.CreateOrUpdateDashboard(r.context, dashboard, folderID)
+	if err != nil {
+		log.Error(err, "cannot submit dashboard")
+		return false, err
+	}
+	status := common.InstanceStatus(graf, response, *instances, true)
+	status.Hash = hash
+	grafanav1alpha1.SetInstanceStatus(instances, status)
+
+	if _, err := applyPermissions(r.context, client, cr.Spec.Permissions, status.UID, resetPermissions(cr)); err != nil {
+		reqLogger.Error(err, "cannot apply dashboard permissions", "grafana", graf.Name)
+		return false, &permissionsError{err}
+	}
+	return true, nil
+}
+
+func contentHash(processed []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(processed))
+}
+
+// Tags the dashboard as managed by the operator in the grafana instance, keeping
+// the tags of the json
+func tagDashboard(processed []byte, graf *grafanav1alpha1.Grafana) ([]byte, error) {
+	board := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(processed))
+	decoder.UseNumber()
+	if err := decoder.Decode(&board); err != nil {
+		return nil, err
+	}
+
+	var tags []interface{}
+	if existing, ok := board["tags"].([]interface{}); ok {
+		tags = existing
+	}
+
+	tag := common.ManagedDashboardTag(graf)
+	for _, existing := range tags {
+		if existing == tag {
+			return processed, nil
 		}
+	}
+	board["tags"] = append(tags, tag)
+	return json.Marshal(board)
+}
 
-		folder, err := client.GetOrCreateNamespaceFolder(cr.Namespace)
-		if err != nil {
-			reqLogger.Error(err, "failed to get or create namespace folder")
-			r.manageError(cr, err)
-			continue
+// Replaces the permissions of the dashboard if they differ from the permissions
+// in the spec. Returns true if the permissions were replaced. Dashboards without
+// permissions in the spec keep their permissions, unless reset is true because
+// permissions were applied before. Their own permissions are removed then and
+// they inherit the permissions of their folder again
+func applyPermissions(ctx context.Context, client grafanaClient.GrafanaClient, permissions []grafanav1alpha1.GrafanaDashboardPermission, uid string, reset bool) (bool, error) {
+	if len(permissions) == 0 && !reset {
+		return false, nil
+	}
+
+	desired, err := resolvePermissions(ctx, client, permissions)
+	if err != nil {
+		return false, err
+	}
+
+	current, err := client.GetDashboardPermissions(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	var own []grafanaClient.GrafanaDashboardPermission
+	for _, permission := range current {
+		if !permission.Inherited {
+			own = append(own, permission)
 		}
+	}
+	sortPermissions(own)
+	if len(own) == len(desired) && (len(own) == 0 || reflect.DeepEqual(own, desired)) {
+		return false, nil
+	}
 
-		var folderID int64 = 0
-		if folder.ID != nil {
-			folderID = *folder.ID
+	return true, client.UpdateDashboardPermissions(ctx, uid, desired)
+}
+
+// Permissions removed from the spec are reset until the PermissionsApplied
+// condition is removed, which happens once they were reset in every instance
+func resetPermissions(cr *grafanav1alpha1.GrafanaDashboard) bool {
+	return len(cr.Spec.Permissions) == 0 && grafanav1alpha1.FindCondition(cr.Status.Conditions, grafanav1alpha1.ConditionPermissionsApplied) != nil
+}
+
+// Looks up the ids of the teams and users. Grantees listed more than once get
+// the highest of their permissions
+func resolvePermissions(ctx context.Context, client grafanaClient.GrafanaClient, permissions []grafanav1alpha1.GrafanaDashboardPermission) ([]grafanaClient.GrafanaDashboardPermission, error) {
+	grantees := map[grafanaClient.GrafanaDashboardPermission]int{}
+	for _, permission := range permissions {
+		grantee := grafanaClient.GrafanaDashboardPermission{Role: permission.Role}
+		switch {
+		case permission.Team != "":
+			team, err := client.GetTeamByName(ctx, permission.Team)
+			if err != nil {
+				if err == grafanaClient.NotFoundError {
+					return nil, fmt.Errorf("team %v does not exist", permission.Team)
+				}
+				return nil, err
+			}
+			grantee.TeamID = team.ID
+		case permission.User != "":
+			user, err := client.GetUserByLogin(ctx, permission.User)
+			if err != nil {
+				if err == grafanaClient.NotFoundError {
+					return nil, fmt.Errorf("user %v does not exist", permission.User)
+				}
+				return nil, err
+			}
+			grantee.UserID = user.ID
 		}
 
-		_, err = client.CreateOrUpdateDashboard(processed, folderID)
-		if err != nil {
-			log.Error(err, "cannot submit dashboard")
-			r.manageError(cr, err)
-			continue
+		level := grafanav1alpha1.DashboardPermissionLevels[permission.Permission]
+		if level > grantees[grantee] {
+			grantees[grantee] = level
 		}
-		r.manageSuccess(cr)
 	}
 
-	return nil
+	resolved := make([]grafanaClient.GrafanaDashboardPermission, 0, len(grantees))
+	for grantee, level := range grantees {
+		grantee.Permission = level
+		resolved = append(resolved, grantee)
+	}
+	sortPermissions(resolved)
+	return resolved, nil
+}
+
+func sortPermissions(permissions []grafanaClient.GrafanaDashboardPermission) {
+	sort.Slice(permissions, func(i, j int) bool {
+		return fmt.Sprint(permissions[i]) < fmt.Sprint(permissions[j])
+	})
 }
 
 func (r *ReconcileGrafanaDashboard) reconcileDelete(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaDashboard) error {
@@ -86,26 +286,33 @@ func (r *ReconcileGrafanaDashboard) reconcileDelete(reqLogger logr.Logger, cr *g
 		return err
 	}
 
+	// The finalizer is only removed once the dashboard is deleted from every
+	// matching grafana
+	var failed []string
 	for _, graf := range matchedGrafs {
 		reqLogger.V(3).Info("delete dashboard from grafana", "grafanaName", graf.Name)
-		// Read current state
-		state := common.NewClusterState()
-		err = state.Read(r.context, graf, r.client)
+		client, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
 		if err != nil {
-			reqLogger.Error(err, "error reading state")
+			reqLogger.Error(err, "grafana not ready", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
 			continue
 		}
 
-		client, err := common.NewGrafanaClient(graf, state)
+		// Dashboards are deleted together with their organization
+		client, err = common.ResourceClient(r.context, client, graf, cr.Namespace, cr.Spec.Organization)
+		if _, ok := err.(*common.OrganizationNotFoundError); ok {
+			continue
+		}
 		if err != nil {
-			reqLogger.Error(err, "newGrafanaClient failed")
+			reqLogger.Error(err, "cannot get organization", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
 			continue
 		}
 
-		dashboards, err := client.GetDashboardsByName(cr.DashboardName())
+		dashboards, err := client.GetDashboardsByName(r.context, cr.DashboardName())
 		if err != nil {
 			reqLogger.Error(err, "cannot get dashboard")
-			r.manageError(cr, err)
+			failed = append(failed, graf.Name)
 			continue
 		}
 
@@ -117,15 +324,20 @@ func (r *ReconcileGrafanaDashboard) reconcileDelete(reqLogger logr.Logger, cr *g
 			reqLogger.Info("GetDashboardsByName", "dashboardNum", len(dashboards))
 		}
 
-		if _, err = client.DeleteDashboardByUID(*dashboards[0].UID); err != nil {
+		if _, err = client.DeleteDashboardByUID(r.context, *dashboards[0].UID); err != nil {
 			if err == grafanaClient.NotFoundError {
 				reqLogger.Info("dashboard already be deleted", "grafana", graf.Name)
 			} else {
 				reqLogger.Error(err, "cannot delete dashboard", "grafana", graf.Name)
+				failed = append(failed, graf.Name)
 			}
 			continue
 		}
 	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot delete dashboard from grafana %v", strings.Join(failed, ", "))
+	}
 	return nil
 }
 
@@ -0,0 +1,107 @@
+package grafanadashboard
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+func TestApplyPermissions(t *testing.T) {
+	current := `[
+		{"role": "Viewer", "permission": 1, "inherited": true},
+		{"teamId": 5, "permission": 2},
+		{"role": "Editor", "permission": 2}
+	]`
+
+	var updates []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/teams/search":
+			w.Write([]byte(`{"teams": [{"id": 5, "name": "sre"}]}`))
+		case "/api/users/lookup":
+			w.Write([]byte(`{"id": 7, "login": "alice"}`))
+		case "/api/dashboards/uid/abc/permissions":
+			if req.Method == http.MethodGet {
+				w.Write([]byte(current))
+				return
+			}
+			body, _ := ioutil.ReadAll(req.Body)
+			updates = append(updates, string(body))
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second})
+	permissions := []grafanav1alpha1.GrafanaDashboardPermission{
+		{Team: "sre", Permission: "Edit"},
+		{User: "alice", Permission: "View"},
+		{User: "alice", Permission: "Admin"},
+	}
+
+	updated, err := applyPermissions(context.Background(), client, permissions, "abc", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Inherited permissions are not submitted, duplicates get the highest permission
+	expected := `{"items":[{"teamId":5,"permission":2},{"userId":7,"permission":4}]}`
+	if !updated || len(updates) != 1 || updates[0] != expected {
+		t.Errorf("expected update %v, got %v", expected, updates)
+	}
+
+	// Permissions matching the spec are left unchanged
+	current = `[
+		{"role": "Viewer", "permission": 1, "inherited": true},
+		{"userId": 7, "permission": 4},
+		{"teamId": 5, "permission": 2}
+	]`
+	updates = nil
+	updated, err = applyPermissions(context.Background(), client, permissions, "abc", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated || len(updates) > 0 {
+		t.Errorf("expected no update, got %v", updates)
+	}
+
+	// Dashboards without permissions keep theirs unless they were applied before
+	updates = nil
+	if updated, err = applyPermissions(context.Background(), client, nil, "abc", false); err != nil || updated {
+		t.Errorf("expected no update, got %v, %v", updates, err)
+	}
+	updated, err = applyPermissions(context.Background(), client, nil, "abc", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"items":[]}`; !updated || len(updates) != 1 || updates[0] != expected {
+		t.Errorf("expected update %v, got %v", expected, updates)
+	}
+}
+
+func TestTagDashboard(t *testing.T) {
+	graf := &grafanav1alpha1.Grafana{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana"}}
+	tagged, err := tagDashboard([]byte(`{"id":null,"tags":["kafka"],"version":12345678901234567890}`), graf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"id":null,"tags":["kafka","managed-by:grafana-operator/monitoring/grafana"],"version":12345678901234567890}`
+	if string(tagged) != expected {
+		t.Errorf("expected %v, got %s", expected, tagged)
+	}
+
+	if again, err := tagDashboard(tagged, graf); err != nil || string(again) != expected {
+		t.Errorf("expected the tag to be added once, got %s", again)
+	}
+}
@@ -0,0 +1,200 @@
+package grafanadashboard
+
+import (
+	"context"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+)
+
+// Field indexes of the dashboards, used to find the dashboards affected by a change
+// of a secondary resource
+const (
+	configMapIndex    = "spec.configMapRef.name"
+	datasourceIndex   = "spec.datasources.datasourceName"
+	organizationIndex = "spec.organization"
+)
+
+func indexConfigMapRef(o runtime.Object) []string {
+	dashboard := o.(*grafanav1alpha1.GrafanaDashboard)
+	if dashboard.Spec.ConfigMapRef == nil {
+		return nil
+	}
+	return []string{dashboard.Spec.ConfigMapRef.Name}
+}
+
+func indexDatasources(o runtime.Object) []string {
+	dashboard := o.(*grafanav1alpha1.GrafanaDashboard)
+	var names []string
+	for _, input := range dashboard.Spec.Datasources {
+		names = append(names, input.DatasourceName)
+	}
+	return names
+}
+
+func indexOrganization(o runtime.Object) []string {
+	dashboard := o.(*grafanav1alpha1.GrafanaDashboard)
+	if dashboard.Spec.Organization == "" {
+		return nil
+	}
+	return []string{dashboard.Spec.Organization}
+}
+
+// datasourceChanged passes the events of datasources that were changed or synced
+// again. Status updates that don't change any datasource uid are ignored
+var datasourceChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		previous := e.ObjectOld.(*grafanav1alpha1.GrafanaDataSource)
+		current := e.ObjectNew.(*grafanav1alpha1.GrafanaDataSource)
+		return previous.Generation != current.Generation || !reflect.DeepEqual(datasourceUids(previous), datasourceUids(current))
+	},
+}
+
+func datasourceUids(cr *grafanav1alpha1.GrafanaDataSource) map[string]string {
+	uids := map[string]string{}
+	for _, item := range cr.Status.Datasources {
+		uids[item.Name] = item.UID
+	}
+	return uids
+}
+
+// Registers the field indexes and watches the resources dashboards depend on: the
+// grafana instances, the configmaps holding dashboard json, the datasources and
+// the organizations
+func watchDependencies(mgr manager.Manager, c controller.Controller) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(&grafanav1alpha1.GrafanaDashboard{}, configMapIndex, indexConfigMapRef); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(&grafanav1alpha1.GrafanaDashboard{}, datasourceIndex, indexDatasources); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(&grafanav1alpha1.GrafanaDashboard{}, organizationIndex, indexOrganization); err != nil {
+		return err
+	}
+
+	kubeclient := mgr.GetClient()
+
+	// Selector changes of a grafana
+	err := c.Watch(&source.Kind{Type: &grafanav1alpha1.Grafana{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return mapGrafanaToDashboards(kubeclient, o.Object.(*grafanav1alpha1.Grafana))
+		}),
+	}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Grafana deployments turning ready
+	err = c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			grafana := common.OwningGrafana(context.Background(), kubeclient, o.Meta)
+			if grafana == nil {
+				return nil
+			}
+			return mapGrafanaToDashboards(kubeclient, grafana)
+		}),
+	}, common.GrafanaBecameReady)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &v1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return listDashboards(kubeclient, o.Meta.GetNamespace(), client.MatchingField(configMapIndex, o.Meta.GetName()))
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &grafanav1alpha1.GrafanaDataSource{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return mapDatasourceToDashboards(kubeclient, o.Object.(*grafanav1alpha1.GrafanaDataSource))
+		}),
+	}, datasourceChanged)
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &grafanav1alpha1.GrafanaOrganization{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			organization := o.Object.(*grafanav1alpha1.GrafanaOrganization)
+			return listDashboards(kubeclient, organization.Namespace, client.MatchingField(organizationIndex, organization.OrganizationName()))
+		}),
+	}, common.OrganizationChanged)
+}
+
+// Returns a request for every dashboard matching the dashboard selectors of the grafana
+func mapGrafanaToDashboards(c client.Client, grafana *grafanav1alpha1.Grafana) []reconcile.Request {
+	dashboards := &grafanav1alpha1.GrafanaDashboardList{}
+	if err := c.List(context.Background(), dashboards, client.InNamespace(grafana.ResourceNamespace())); err != nil {
+		log.Error(err, "error listing dashboards")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, dashboard := range dashboards.Items {
+		match, err := common.MatchesSelectors(dashboard.Labels, grafana.Spec.DashboardLabelSelector)
+		if err != nil || !match {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: dashboard.Namespace,
+			Name:      dashboard.Name,
+		}})
+	}
+	return requests
+}
+
+// Returns a request for every dashboard that uses one of the datasources of the
+// custom resource as input
+func mapDatasourceToDashboards(c client.Client, datasource *grafanav1alpha1.GrafanaDataSource) []reconcile.Request {
+	names := datasource.Spec.Datasources.Names()
+	for _, item := range datasource.Status.Datasources {
+		names = append(names, item.Name)
+	}
+
+	var requests []reconcile.Request
+	seen := map[types.NamespacedName]bool{}
+	for _, name := range names {
+		for _, request := range listDashboards(c, datasource.Namespace, client.MatchingField(datasourceIndex, name)) {
+			if !seen[request.NamespacedName] {
+				seen[request.NamespacedName] = true
+				requests = append(requests, request)
+			}
+		}
+	}
+	return requests
+}
+
+func listDashboards(c client.Client, namespace string, fields client.MatchingFields) []reconcile.Request {
+	dashboards := &grafanav1alpha1.GrafanaDashboardList{}
+	if err := c.List(context.Background(), dashboards, client.InNamespace(namespace), fields); err != nil {
+		log.Error(err, "error listing dashboards")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(dashboards.Items))
+	for _, dashboard := range dashboards.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: dashboard.Namespace,
+			Name:      dashboard.Name,
+		}})
+	}
+	return requests
+}
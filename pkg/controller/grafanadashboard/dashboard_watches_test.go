@@ -0,0 +1,64 @@
+package grafanadashboard
+
+import (
+	"reflect"
+	"testing"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestDashboardIndexes(t *testing.T) {
+	dashboard := &grafanav1alpha1.GrafanaDashboard{
+		Spec: grafanav1alpha1.GrafanaDashboardSpec{
+			ConfigMapRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "dashboards"},
+				Key:                  "overview.json",
+			},
+			Datasources: []grafanav1alpha1.GrafanaDashboardDatasource{
+				{InputName: "DS_PROMETHEUS", DatasourceName: "prometheus"},
+				{InputName: "DS_LOKI", DatasourceName: "loki"},
+			},
+		},
+	}
+
+	if refs := indexConfigMapRef(dashboard); !reflect.DeepEqual(refs, []string{"dashboards"}) {
+		t.Errorf("unexpected configmap index %v", refs)
+	}
+	if names := indexDatasources(dashboard); !reflect.DeepEqual(names, []string{"prometheus", "loki"}) {
+		t.Errorf("unexpected datasource index %v", names)
+	}
+
+	dashboard.Spec.ConfigMapRef = nil
+	if refs := indexConfigMapRef(dashboard); len(refs) != 0 {
+		t.Errorf("expected no configmap index, got %v", refs)
+	}
+}
+
+func TestDatasourceChanged(t *testing.T) {
+	previous := &grafanav1alpha1.GrafanaDataSource{
+		Status: grafanav1alpha1.GrafanaDataSourceStatus{
+			Datasources: []grafanav1alpha1.GrafanaDataSourceItemStatus{{Name: "prometheus", UID: "abc"}},
+		},
+	}
+	previous.Generation = 1
+
+	unchanged := previous.DeepCopy()
+	unchanged.Status.Message = "success"
+	if datasourceChanged.Update(event.UpdateEvent{ObjectOld: previous, ObjectNew: unchanged}) {
+		t.Errorf("status update without new uids should be ignored")
+	}
+
+	resynced := previous.DeepCopy()
+	resynced.Status.Datasources[0].UID = "def"
+	if !datasourceChanged.Update(event.UpdateEvent{ObjectOld: previous, ObjectNew: resynced}) {
+		t.Errorf("changed uid should enqueue the dashboards")
+	}
+
+	changed := previous.DeepCopy()
+	changed.Generation = 2
+	if !datasourceChanged.Update(event.UpdateEvent{ObjectOld: previous, ObjectNew: changed}) {
+		t.Errorf("spec change should enqueue the dashboards")
+	}
+}
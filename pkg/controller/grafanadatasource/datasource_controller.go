@@ -19,12 +19,13 @@ import (
 
 	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
 	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
 )
 
 const (
 	ControllerName                 = "controller_grafanadatasource"
 	datasourceFinalizer            = "finalizer.grafanadatasources.monitor.kun"
-	defaultreconcileTime           = 10 * time.Second
 	defaultMaxConcurrentReconciles = 10
 )
 
@@ -48,6 +49,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		cancel:   cancel,
 		recorder: mgr.GetEventRecorderFor(ControllerName),
 		state:    common.ControllerState{},
+		health:   newHealthChecks(),
 	}
 }
 
@@ -68,7 +70,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	return nil
+	return watchGrafanas(mgr, c)
 }
 
 var _ reconcile.Reconciler = &ReconcileGrafanaDataSource{}
@@ -83,6 +85,7 @@ type ReconcileGrafanaDataSource struct {
 	cancel   context.CancelFunc
 	recorder record.EventRecorder
 	state    common.ControllerState
+	health   *healthChecks
 }
 
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
@@ -110,6 +113,7 @@ func (r *ReconcileGrafanaDataSource) Reconcile(request reconcile.Request) (recon
 			// finalization logic fails, don't remove the finalizer so
 			// that we can retry during the next reconciliation.
 			if err := r.finalize(reqLogger, instance); err != nil {
+				r.manageError(instance, err)
 				return reconcile.Result{}, err
 			}
 
@@ -120,6 +124,8 @@ func (r *ReconcileGrafanaDataSource) Reconcile(request reconcile.Request) (recon
 			if err != nil {
 				return reconcile.Result{}, err
 			}
+			metrics.ForgetResource(metrics.KindGrafanaDataSource, instance.Namespace, instance.Name)
+			r.health.forget(instance)
 		}
 		return reconcile.Result{}, nil
 	}
@@ -138,7 +144,23 @@ func (r *ReconcileGrafanaDataSource) Reconcile(request reconcile.Request) (recon
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{RequeueAfter: defaultreconcileTime}, nil
+	return reconcile.Result{RequeueAfter: requeueAfter(instance)}, nil
+}
+
+// Failed syncs are retried soon. Otherwise the datasources are synced again when a
+// watched resource changes or after the resync period, but early enough for the
+// next health check
+func requeueAfter(datasource *grafanav1alpha1.GrafanaDataSource) time.Duration {
+	if datasource.Status.Phase == grafanav1alpha1.PhaseFailing {
+		return config.RequeueDelay
+	}
+
+	after := common.ResyncPeriod()
+	interval := config.GetControllerConfig().GetConfigItem(config.ConfigDatasourceHealthCheckInterval, config.DatasourceHealthCheckInterval).(time.Duration)
+	if interval > 0 && interval < after {
+		after = interval
+	}
+	return after
 }
 
 // Handle error case: update datasource with error message and status
@@ -152,10 +174,12 @@ func (r *ReconcileGrafanaDataSource) manageError(datasource *grafanav1alpha1.Gra
 
 	datasource.Status.Phase = grafanav1alpha1.PhaseFailing
 	datasource.Status.Message = issue.Error()
+	grafanav1alpha1.SetSyncedCondition(&datasource.Status.Conditions, datasource.Generation, issue)
+	grafanav1alpha1.SetReadyCondition(&datasource.Status.Conditions, datasource.Generation, grafanav1alpha1.ConditionSynced)
 
 	err := r.client.Status().Update(r.context, datasource)
 	if err != nil {
-		// Ignore conclicts. Resource might just be outdated.
+		// Ignore conflicts. Resource might just be outdated.
 		if errors.IsConflict(err) {
 			return
 		}
@@ -172,6 +196,9 @@ func (r *ReconcileGrafanaDataSource) manageSuccess(datasource *grafanav1alpha1.G
 
 	datasource.Status.Phase = grafanav1alpha1.PhaseReconciling
 	datasource.Status.Message = "success"
+	grafanav1alpha1.SetSyncedCondition(&datasource.Status.Conditions, datasource.Generation, nil)
+	grafanav1alpha1.SetReadyCondition(&datasource.Status.Conditions, datasource.Generation, grafanav1alpha1.ConditionSynced)
+	metrics.SetLastSuccessfulSync(metrics.KindGrafanaDataSource, datasource.Namespace, datasource.Name)
 
 	err := r.client.Status().Update(r.context, datasource)
 	if err != nil {
@@ -0,0 +1,142 @@
+package grafanadatasource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	ReasonHealthCheckPassed      = "HealthCheckPassed"
+	ReasonHealthCheckFailed      = "HealthCheckFailed"
+	ReasonHealthCheckUnavailable = "HealthCheckUnavailable"
+)
+
+// Remembers when the datasources were last checked, health checks run less often
+// than the datasources are reconciled
+type healthChecks struct {
+	sync.Mutex
+	lastChecks map[string]time.Time
+}
+
+func newHealthChecks() *healthChecks {
+	return &healthChecks{
+		lastChecks: map[string]time.Time{},
+	}
+}
+
+func healthCheckKey(cr *grafanav1alpha1.GrafanaDataSource, graf *grafanav1alpha1.Grafana, name string) string {
+	return fmt.Sprintf("%v/%v/%v/%v", cr.Namespace, cr.Name, graf.Name, name)
+}
+
+// Returns true and records the check if the last check is older than the interval
+func (h *healthChecks) due(key string) bool {
+	interval := config.GetControllerConfig().GetConfigItem(config.ConfigDatasourceHealthCheckInterval, config.DatasourceHealthCheckInterval).(time.Duration)
+
+	h.Lock()
+	defer h.Unlock()
+	if last, ok := h.lastChecks[key]; ok && time.Since(last) < interval {
+		return false
+	}
+	h.lastChecks[key] = time.Now()
+	return true
+}
+
+func (h *healthChecks) forget(cr *grafanav1alpha1.GrafanaDataSource) {
+	prefix := fmt.Sprintf("%v/%v/", cr.Namespace, cr.Name)
+
+	h.Lock()
+	defer h.Unlock()
+	for key := range h.lastChecks {
+		if strings.HasPrefix(key, prefix) {
+			delete(h.lastChecks, key)
+		}
+	}
+}
+
+// Runs the health check of grafana for the datasource and returns the result as
+// condition
+func checkHealth(ctx context.Context, client grafanaClient.GrafanaClient, fields *grafanav1alpha1.GrafanaDataSourceFields, datasource grafanaClient.GrafanaResponse) grafanav1alpha1.Condition {
+	condition := grafanav1alpha1.Condition{
+		Type:   grafanav1alpha1.ConditionHealthy,
+		Status: v1.ConditionUnknown,
+		Reason: ReasonHealthCheckUnavailable,
+	}
+
+	// Datasources with direct access are queried by the browser
+	if fields.Access == grafanav1alpha1.DatasourceAccessDirect {
+		condition.Message = "datasources with direct access can't be checked by grafana"
+		return condition
+	}
+
+	if datasource.ID == nil {
+		condition.Message = "datasource has no id"
+		return condition
+	}
+
+	uid := ""
+	if datasource.UID != nil {
+		uid = *datasource.UID
+	}
+
+	response, err := client.CheckDatasourceHealth(ctx, *datasource.ID, uid)
+	if err != nil {
+		condition.Message = err.Error()
+		return condition
+	}
+
+	condition.Message = response.Message
+	switch response.Status {
+	case grafanaClient.HealthStatusOk:
+		condition.Status = v1.ConditionTrue
+		condition.Reason = ReasonHealthCheckPassed
+	case grafanaClient.HealthStatusError:
+		condition.Status = v1.ConditionFalse
+		condition.Reason = ReasonHealthCheckFailed
+	}
+	return condition
+}
+
+// Summarizes the health of all datasources of the resource
+func healthCondition(items []grafanav1alpha1.GrafanaDataSourceItemStatus) grafanav1alpha1.Condition {
+	var failed, unknown []string
+	for _, item := range items {
+		health := grafanav1alpha1.FindCondition(item.Conditions, grafanav1alpha1.ConditionHealthy)
+		switch {
+		case health == nil || health.Status == v1.ConditionUnknown:
+			unknown = append(unknown, item.Name)
+		case health.Status == v1.ConditionFalse:
+			failed = append(failed, fmt.Sprintf("%v: %v", item.Name, health.Message))
+		}
+	}
+
+	switch {
+	case len(failed) > 0:
+		return grafanav1alpha1.Condition{
+			Type:    grafanav1alpha1.ConditionHealthy,
+			Status:  v1.ConditionFalse,
+			Reason:  ReasonHealthCheckFailed,
+			Message: strings.Join(failed, "; "),
+		}
+	case len(unknown) > 0:
+		return grafanav1alpha1.Condition{
+			Type:    grafanav1alpha1.ConditionHealthy,
+			Status:  v1.ConditionUnknown,
+			Reason:  ReasonHealthCheckUnavailable,
+			Message: fmt.Sprintf("health of %v is unknown", strings.Join(unknown, ", ")),
+		}
+	}
+
+	return grafanav1alpha1.Condition{
+		Type:   grafanav1alpha1.ConditionHealthy,
+		Status: v1.ConditionTrue,
+		Reason: ReasonHealthCheckPassed,
+	}
+}
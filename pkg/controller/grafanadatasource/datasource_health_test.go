@@ -0,0 +1,67 @@
+package grafanadatasource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestCheckHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/datasources/uid/prometheus/health":
+			w.Write([]byte(`{"status": "OK", "message": "Data source is working"}`))
+		case "/api/datasources/uid/loki/health":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status": "ERROR", "message": "connection refused"}`))
+		case "/api/datasources/proxy/3/":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second})
+	fields := &grafanav1alpha1.GrafanaDataSourceFields{Access: grafanav1alpha1.DatasourceAccessProxy}
+
+	expected := map[string]v1.ConditionStatus{
+		"prometheus": v1.ConditionTrue,
+		"loki":       v1.ConditionFalse,
+		// Falls back to the datasource proxy
+		"legacy": v1.ConditionFalse,
+	}
+
+	ids := map[string]uint{"prometheus": 1, "loki": 2, "legacy": 3}
+	for name, status := range expected {
+		id, uid := ids[name], name
+		condition := checkHealth(context.Background(), client, fields, grafanaClient.GrafanaResponse{ID: &id, UID: &uid})
+		if condition.Status != status {
+			t.Errorf("%v: expected health %v, got %v (%v)", name, status, condition.Status, condition.Message)
+		}
+	}
+}
+
+func TestHealthCondition(t *testing.T) {
+	statuses := newItemStatuses(&grafanav1alpha1.GrafanaDataSource{
+		Spec: grafanav1alpha1.GrafanaDataSourceSpec{
+			Datasources: grafanav1alpha1.GrafanaDataSourceFieldsList{{Name: "prometheus"}, {Name: "loki"}},
+		},
+	})
+
+	// A failing check in one grafana is not overridden by a passing check in another
+	statuses.setHealth("loki", grafanav1alpha1.Condition{Type: grafanav1alpha1.ConditionHealthy, Status: v1.ConditionFalse, Message: "timeout"})
+	statuses.setHealth("loki", grafanav1alpha1.Condition{Type: grafanav1alpha1.ConditionHealthy, Status: v1.ConditionTrue})
+	statuses.setHealth("prometheus", grafanav1alpha1.Condition{Type: grafanav1alpha1.ConditionHealthy, Status: v1.ConditionTrue})
+
+	condition := healthCondition(statuses.list())
+	if condition.Status != v1.ConditionFalse || condition.Message != "loki: timeout" {
+		t.Errorf("unexpected health condition: %+v", condition)
+	}
+}
@@ -1,24 +1,214 @@
 package grafanadatasource
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+
 	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type DatasourcePipeline interface {
 	ProcessDatasource() ([]byte, error)
 }
 
+// Returns the uid of the grafana datasource with the given name
+type UidResolver func(name string) (string, error)
+
 type DatasourcePipelineImpl struct {
+	client     client.Client
+	grafana    *v1alpha1.Grafana
 	datasource *v1alpha1.GrafanaDataSource
+	fields     *v1alpha1.GrafanaDataSourceFields
+	resolveUid UidResolver
 }
 
-func NewDatasourcePipeline(ds *v1alpha1.GrafanaDataSource) DatasourcePipeline {
+func NewDatasourcePipeline(client client.Client, graf *v1alpha1.Grafana, ds *v1alpha1.GrafanaDataSource, fields *v1alpha1.GrafanaDataSourceFields, resolveUid UidResolver) DatasourcePipeline {
 	return &DatasourcePipelineImpl{
+		client:     client,
+		grafana:    graf,
 		datasource: ds,
+		fields:     fields,
+		resolveUid: resolveUid,
 	}
 }
 
 func (i *DatasourcePipelineImpl) ProcessDatasource() ([]byte, error) {
-	return json.Marshal(i.datasource.Spec.Datasources)
+	fields := i.fields.DeepCopy()
+
+	if err := i.resolveSecretRefs(fields); err != nil {
+		return nil, err
+	}
+
+	// Options in jsonData and secureJsonData without a typed field are merged
+	// with the typed fields when serialized
+	headers := fields.HttpHeaders
+	fields.HttpHeaders = nil
+	bytes, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	datasource := map[string]interface{}{}
+	if err := json.Unmarshal(bytes, &datasource); err != nil {
+		return nil, err
+	}
+
+	if err := i.expandHttpHeaders(datasource, headers); err != nil {
+		return nil, err
+	}
+
+	if err := i.resolveUidReferences(datasource); err != nil {
+		return nil, err
+	}
+
+	// Mark the datasource as managed by the operator in the grafana instance
+	getObject(datasource, "jsonData")[common.ManagedDatasourceKey] = common.ManagedMarker(i.grafana)
+
+	return json.Marshal(datasource)
+}
+
+// Grafana expects custom headers as numbered httpHeaderName keys in the json data
+// and httpHeaderValue keys in the secure json data. Headers from the list are
+// numbered after the ones already set in the fixed fields
+func (i *DatasourcePipelineImpl) expandHttpHeaders(datasource map[string]interface{}, headers []v1alpha1.GrafanaDataSourceHttpHeader) error {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	jsonData := getObject(datasource, "jsonData")
+	secureJsonData := getObject(datasource, "secureJsonData")
+
+	index := 1
+	for _, header := range headers {
+		value, err := i.getHttpHeaderValue(header)
+		if err != nil {
+			return err
+		}
+
+		for jsonData[fmt.Sprintf("httpHeaderName%d", index)] != nil {
+			index++
+		}
+
+		jsonData[fmt.Sprintf("httpHeaderName%d", index)] = header.Name
+		secureJsonData[fmt.Sprintf("httpHeaderValue%d", index)] = value
+		index++
+	}
+
+	return nil
+}
+
+// Replace ${uid:<name>} references in the json options with the uid of the
+// named datasource in grafana
+func (i *DatasourcePipelineImpl) resolveUidReferences(datasource map[string]interface{}) error {
+	for _, key := range []string{"jsonData", "secureJsonData"} {
+		options, ok := datasource[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		resolved, err := i.resolveJsonValue(options)
+		if err != nil {
+			return err
+		}
+		datasource[key] = resolved
+	}
+	return nil
+}
+
+func (i *DatasourcePipelineImpl) resolveJsonValue(value interface{}) (interface{}, error) {
+	var err error
+	switch v := value.(type) {
+	case string:
+		err = i.replaceUidReferences(&v)
+		return v, err
+	case map[string]interface{}:
+		for key, item := range v {
+			if v[key], err = i.resolveJsonValue(item); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for index, item := range v {
+			if v[index], err = i.resolveJsonValue(item); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return value, nil
+}
+
+func (i *DatasourcePipelineImpl) replaceUidReferences(value *string) error {
+	var err error
+	*value = v1alpha1.DatasourceUidReference.ReplaceAllStringFunc(*value, func(reference string) string {
+		name := v1alpha1.DatasourceUidReference.FindStringSubmatch(reference)[1]
+		uid, resolveErr := i.resolveUid(name)
+		if resolveErr != nil && err == nil {
+			err = fmt.Errorf("cannot resolve uid of datasource %v: %v", name, resolveErr)
+		}
+		return uid
+	})
+	return err
+}
+
+func (i *DatasourcePipelineImpl) getHttpHeaderValue(header v1alpha1.GrafanaDataSourceHttpHeader) (string, error) {
+	if header.ValueFrom == nil || header.ValueFrom.SecretKeyRef == nil {
+		return header.Value, nil
+	}
+	return i.readSecretKey(header.ValueFrom.SecretKeyRef)
+}
+
+// Returns the nested object with the given key, creating it if necessary
+func getObject(parent map[string]interface{}, key string) map[string]interface{} {
+	object, ok := parent[key].(map[string]interface{})
+	if !ok {
+		object = map[string]interface{}{}
+		parent[key] = object
+	}
+	return object
+}
+
+// Replace the passwords referenced by secrets with their values. The references
+// themselves are not sent to grafana
+func (i *DatasourcePipelineImpl) resolveSecretRefs(fields *v1alpha1.GrafanaDataSourceFields) error {
+	if fields.PasswordRef != nil {
+		password, err := i.readSecretKey(fields.PasswordRef)
+		if err != nil {
+			return err
+		}
+		fields.Password = password
+		fields.PasswordRef = nil
+	}
+
+	if fields.BasicAuthPasswordRef != nil {
+		password, err := i.readSecretKey(fields.BasicAuthPasswordRef)
+		if err != nil {
+			return err
+		}
+		fields.BasicAuthPassword = password
+		fields.BasicAuthPasswordRef = nil
+	}
+
+	return nil
+}
+
+func (i *DatasourcePipelineImpl) readSecretKey(ref *corev1.SecretKeySelector) (string, error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{
+		Namespace: i.datasource.Namespace,
+		Name:      ref.Name,
+	}
+
+	if err := i.client.Get(context.Background(), key, secret); err != nil {
+		return "", fmt.Errorf("error reading secret %v: %v", ref.Name, err)
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %v not found in secret %v", ref.Key, ref.Name)
+	}
+	return string(value), nil
 }
@@ -0,0 +1,145 @@
+package grafanadatasource
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var graf = &v1alpha1.Grafana{
+	ObjectMeta: metav1.ObjectMeta{Name: "grafana", Namespace: "monitoring"},
+}
+
+func TestProcessDatasourceHttpHeaders(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "headers", Namespace: "grafana"},
+		Data:       map[string][]byte{"token": []byte("secret-token")},
+	}
+
+	ds := &v1alpha1.GrafanaDataSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "loki", Namespace: "grafana"},
+		Spec: v1alpha1.GrafanaDataSourceSpec{
+			Datasources: v1alpha1.GrafanaDataSourceFieldsList{
+				{
+					Name: "loki",
+					Type: "loki",
+					JsonData: v1alpha1.GrafanaDataSourceJsonData{
+						HTTPHeaderName1: "X-Fixed",
+					},
+					SecureJsonData: v1alpha1.GrafanaDataSourceSecureJsonData{
+						HTTPHeaderValue1: "fixed",
+					},
+					HttpHeaders: []v1alpha1.GrafanaDataSourceHttpHeader{
+						{Name: "X-Scope-OrgID", Value: "team-a"},
+						{
+							Name: "Authorization",
+							ValueFrom: &v1alpha1.GrafanaDataSourceHttpHeaderSource{
+								SecretKeyRef: &v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{Name: "headers"},
+									Key:                  "token",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	processed, err := NewDatasourcePipeline(fake.NewFakeClient(secret), graf, ds, &ds.Spec.Datasources[0], nil).ProcessDatasource()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		JsonData       map[string]interface{} `json:"jsonData"`
+		SecureJsonData map[string]interface{} `json:"secureJsonData"`
+		HttpHeaders    interface{}            `json:"httpHeaders"`
+	}
+	if err := json.Unmarshal(processed, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"httpHeaderName1":  "X-Fixed",
+		"httpHeaderName2":  "X-Scope-OrgID",
+		"httpHeaderName3":  "Authorization",
+		"httpHeaderValue1": "fixed",
+		"httpHeaderValue2": "team-a",
+		"httpHeaderValue3": "secret-token",
+	}
+	for key, value := range expected {
+		actual := result.JsonData[key]
+		if actual == nil {
+			actual = result.SecureJsonData[key]
+		}
+		if actual != value {
+			t.Errorf("expected %v to be %v, got %v", key, value, actual)
+		}
+	}
+
+	if result.HttpHeaders != nil {
+		t.Errorf("header list must not be sent to grafana")
+	}
+
+	if marker := result.JsonData["managedBy"]; marker != "grafana-operator/monitoring/grafana" {
+		t.Errorf("expected the datasource to be marked for its grafana, got %v", marker)
+	}
+}
+
+func TestProcessDatasourceUidReferences(t *testing.T) {
+	ds := &v1alpha1.GrafanaDataSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "tracing", Namespace: "grafana"},
+	}
+	fields := &v1alpha1.GrafanaDataSourceFields{
+		Name: "tempo",
+		Type: "tempo",
+		JsonData: v1alpha1.GrafanaDataSourceJsonData{
+			Extra: map[string]interface{}{
+				"tracesToLogs": map[string]interface{}{
+					"datasourceUid": "${uid:loki}",
+					"tags":          []interface{}{"job"},
+				},
+			},
+		},
+	}
+
+	uids := map[string]string{"loki": "P8E80F9AEF21F6940"}
+	resolveUid := func(name string) (string, error) {
+		uid, ok := uids[name]
+		if !ok {
+			return "", fmt.Errorf("not found")
+		}
+		return uid, nil
+	}
+
+	processed, err := NewDatasourcePipeline(fake.NewFakeClient(), graf, ds, fields, resolveUid).ProcessDatasource()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		JsonData struct {
+			TracesToLogs struct {
+				DatasourceUid string `json:"datasourceUid"`
+			} `json:"tracesToLogs"`
+		} `json:"jsonData"`
+	}
+	if err := json.Unmarshal(processed, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if result.JsonData.TracesToLogs.DatasourceUid != uids["loki"] {
+		t.Errorf("uid reference not resolved: %s", processed)
+	}
+
+	fields.JsonData.Extra["tracesToLogs"] = map[string]interface{}{"datasourceUid": "${uid:unknown}"}
+	if _, err := NewDatasourcePipeline(fake.NewFakeClient(), graf, ds, fields, resolveUid).ProcessDatasource(); err == nil {
+		t.Errorf("expected an error for an unknown datasource")
+	}
+}
@@ -1,11 +1,16 @@
 package grafanadatasource
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"github.com/go-logr/logr"
 	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
 
 	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
 	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
 )
 
 func (r *ReconcileGrafanaDataSource) reconcile(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaDataSource) error {
@@ -16,93 +21,251 @@ func (r *ReconcileGrafanaDataSource) reconcile(reqLogger logr.Logger, cr *grafan
 	}
 	reqLogger.Info("matched grafana", "szie", len(matchedGrafs))
 
+	// Referenced datasources have to exist before the datasources referencing them
+	datasources, err := cr.Spec.Datasources.Sorted()
+	if err != nil {
+		return err
+	}
+
+	removed := removedDatasources(cr)
+	orgIds := datasourceOrgIds(cr)
+	items := newItemStatuses(cr)
+
+	// Datasources removed from the list are kept in the status until they are
+	// deleted, they are retried with every reconciliation
+	for _, name := range removed {
+		items.get(name)
+	}
+
 	for _, graf := range matchedGrafs {
 		reqLogger.Info("reconcile datasource for grafana", "grafanaName", graf.Name)
-		// Read current state
-		state := common.NewClusterState()
-		if err = state.Read(r.context, graf, r.client); err != nil {
-			reqLogger.Error(err, "error reading state")
-			continue
-		}
-
-		client, err := common.NewGrafanaClient(graf, state)
+		// Unready instances fail the sync, it is retried soon
+		client, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
 		if err != nil {
-			reqLogger.Error(err, "newGrafanaClient failed")
-			r.manageError(cr, err)
+			reqLogger.Error(err, "grafana not ready", "grafana", graf.Name)
+			items.setAll(append(cr.Spec.Datasources.Names(), removed...), err)
 			continue
 		}
 
-		if _, err := client.GetDatasourceByName(cr.Spec.Datasources.Name); err != nil && err == grafanaClient.NotFoundError {
-			reqLogger.Info("create new datasource for grafana", "grafanaName", graf.Name, "dataSource", cr.Spec.Datasources.Name)
-			pipeline := NewDatasourcePipeline(cr)
-			processed, err := pipeline.ProcessDatasource()
-
+		for i := range datasources {
+			fields := &datasources[i]
+			client, err := organizationClient(r.context, client, graf, cr, fields.OrgId)
 			if err != nil {
-				reqLogger.Error(err, "cannot process datasource")
-				r.manageError(cr, err)
+				reqLogger.Error(err, "cannot get organization", "grafana", graf.Name)
+				items.set(fields.Name, "", err)
 				continue
 			}
 
-			if processed == nil {
-				continue
+			response, created, err := r.reconcileDatasource(reqLogger, cr, graf, client, fields)
+			items.set(fields.Name, responseUid(response), err)
+			if err == nil {
+				items.setInstance(fields.Name, graf, response, created)
 			}
 
-			_, err = client.CreateDatasource(processed)
-			if err != nil {
-				reqLogger.Error(err, "cannot submit datasource", "grafana", graf.Name)
-				r.manageError(cr, err)
-				continue
+			// New datasources are checked right away, existing ones periodically
+			if err == nil && (r.health.due(healthCheckKey(cr, graf, fields.Name)) || created) {
+				health := checkHealth(r.context, client, fields, response)
+				health.ObservedGeneration = cr.Generation
+				items.setHealth(fields.Name, health)
 			}
-		} else if err != nil {
-			reqLogger.Error(err, "cannot get datasource", "grafana", graf.Name)
-			r.manageError(cr, err)
-			continue
-		} else if err == nil {
-			continue
 		}
 
-		r.manageSuccess(cr)
+		for _, name := range removed {
+			if err := deleteDatasource(r.context, reqLogger, graf, client, cr, name, orgIds[name]); err != nil {
+				items.set(name, "", err)
+			}
+		}
+	}
+
+	// Removed datasources are dropped once they are deleted from all grafanas,
+	// which takes at least one matching grafana
+	if len(matchedGrafs) > 0 {
+		items.dropDeleted(removed)
+	}
+
+	cr.Status.Datasources = items.list()
+	health := healthCondition(cr.Status.Datasources)
+	health.ObservedGeneration = cr.Generation
+	grafanav1alpha1.SetCondition(&cr.Status.Conditions, health)
+	if failed := items.failed(); failed > 0 {
+		r.manageError(cr, fmt.Errorf("%v of %v datasources failed to sync", failed, len(cr.Status.Datasources)))
+		return nil
+	}
+
+	r.manageSuccess(cr)
+	return nil
+}
+
+// Creates the datasource in grafana if it does not exist yet. Returns the datasource
+// and whether it was created
+func (r *ReconcileGrafanaDataSource) reconcileDatasource(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaDataSource, graf *grafanav1alpha1.Grafana, client grafanaClient.GrafanaClient, fields *grafanav1alpha1.GrafanaDataSourceFields) (grafanaClient.GrafanaResponse, bool, error) {
+	response, err := client.GetDatasourceByName(r.context, fields.Name)
+	if err == nil {
+		return response, false, nil
+	}
+
+	if err != grafanaClient.NotFoundError {
+		reqLogger.Error(err, "cannot get datasource", "grafana", graf.Name)
+		metrics.DatasourceSyncs.WithLabelValues(graf.Namespace, graf.Name, metrics.ResultFailure).Inc()
+		return response, false, err
+	}
+
+	reqLogger.Info("create new datasource for grafana", "grafanaName", graf.Name, "dataSource", fields.Name)
+	pipeline := NewDatasourcePipeline(r.client, graf, cr, fields, func(name string) (string, error) {
+		return datasourceUid(r.context, client, name)
+	})
+	processed, err := pipeline.ProcessDatasource()
+	if err != nil {
+		reqLogger.Error(err, "cannot process datasource")
+		metrics.DatasourceSyncs.WithLabelValues(graf.Namespace, graf.Name, metrics.ResultFailure).Inc()
+		return response, false, err
+	}
+
+	_, err = client.CreateDatasource(r.context, processed)
+	metrics.DatasourceSyncs.WithLabelValues(graf.Namespace, graf.Name, metrics.Result(err)).Inc()
+	if err != nil {
+		reqLogger.Error(err, "cannot submit datasource", "grafana", graf.Name)
+		return response, false, err
+	}
+
+	response, err = client.GetDatasourceByName(r.context, fields.Name)
+	return response, true, err
+}
+
+func responseUid(response grafanaClient.GrafanaResponse) string {
+	if response.UID == nil {
+		return ""
+	}
+	return *response.UID
+}
+
+func datasourceUid(ctx context.Context, client grafanaClient.GrafanaClient, name string) (string, error) {
+	response, err := client.GetDatasourceByName(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	uid := responseUid(response)
+	if uid == "" {
+		return "", fmt.Errorf("datasource %v has no uid", name)
 	}
+	return uid, nil
+}
 
+// Returns a client for the organization of a datasource. The orgId of the
+// datasource takes precedence over the organization of the resource, unless
+// grafana separates the namespaces into organizations
+func organizationClient(ctx context.Context, client grafanaClient.GrafanaClient, graf *grafanav1alpha1.Grafana, cr *grafanav1alpha1.GrafanaDataSource, orgId int) (grafanaClient.GrafanaClient, error) {
+	if orgId > 0 && graf.Spec.Tenancy != grafanav1alpha1.TenancyNamespaceOrgs {
+		return client.WithOrganization(uint(orgId)), nil
+	}
+	return common.ResourceClient(ctx, client, graf, cr.Namespace, cr.Spec.Organization)
+}
+
+// Deletes the datasource from its organization if the operator created it for
+// the grafana instance, datasources created by other means are kept. Datasources
+// of deleted organizations are gone already
+func deleteDatasource(ctx context.Context, reqLogger logr.Logger, graf *grafanav1alpha1.Grafana, client grafanaClient.GrafanaClient, cr *grafanav1alpha1.GrafanaDataSource, name string, orgId int) error {
+	var err error
+	if orgId > 0 && graf.Spec.Tenancy != grafanav1alpha1.TenancyNamespaceOrgs {
+		client = client.WithOrganization(uint(orgId))
+	} else {
+		client, err = common.ResourceClient(ctx, client, graf, cr.Namespace, cr.Spec.Organization)
+	}
+	if _, ok := err.(*common.OrganizationNotFoundError); ok {
+		return nil
+	}
+	if err != nil {
+		reqLogger.Error(err, "cannot get organization", "grafana", graf.Name)
+		return err
+	}
+
+	datasource, err := client.GetDatasourceByName(ctx, name)
+	if err != nil {
+		if err == grafanaClient.NotFoundError {
+			reqLogger.Info("datasource already be deleted or not installed", "grafana", graf.Name, "dataSource", name)
+			return nil
+		}
+		reqLogger.Error(err, "cannot get datasource", "grafana", graf.Name)
+		return err
+	}
+	if !common.IsManagedDatasource(graf, datasource.JsonData) {
+		reqLogger.Info("datasource not created by the operator, not deleting it", "grafana", graf.Name, "dataSource", name)
+		return nil
+	}
+
+	if _, err := client.DeleteDatasourceByName(ctx, name); err != nil {
+		if err == grafanaClient.NotFoundError {
+			reqLogger.Info("datasource already be deleted", "grafana", graf.Name, "dataSource", name)
+			return nil
+		}
+		reqLogger.Error(err, "cannot delete datasource", "grafana", graf.Name)
+		return err
+	}
 	return nil
 }
 
+// Returns the datasources in the status that are no longer part of the list
+func removedDatasources(cr *grafanav1alpha1.GrafanaDataSource) []string {
+	names := cr.Spec.Datasources.Names()
+
+	var removed []string
+	for _, item := range cr.Status.Datasources {
+		if !contains(names, item.Name) {
+			removed = append(removed, item.Name)
+		}
+	}
+	return removed
+}
+
+// Returns the orgId of every datasource in the list or in the status. Removed
+// datasources are deleted from the organization they were created in
+func datasourceOrgIds(cr *grafanav1alpha1.GrafanaDataSource) map[string]int {
+	orgIds := map[string]int{}
+	for _, item := range cr.Status.Datasources {
+		orgIds[item.Name] = item.OrgID
+	}
+	for _, fields := range cr.Spec.Datasources {
+		orgIds[fields.Name] = fields.OrgId
+	}
+	return orgIds
+}
+
 func (r *ReconcileGrafanaDataSource) reconcileDelete(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaDataSource) error {
 	matchedGrafs, err := common.MatchGrafana(r.context, r.client, reqLogger, cr.Namespace, cr.Labels, common.MatchByDataSource)
 	if err != nil {
 		reqLogger.Error(err, "matchGrafana failed.")
 		return err
 	}
+
+	// The finalizer is only removed once the datasources are deleted from every
+	// matching grafana
+	var failed []string
+	orgIds := datasourceOrgIds(cr)
+	names := append(cr.Spec.Datasources.Names(), removedDatasources(cr)...)
 	for _, graf := range matchedGrafs {
 		reqLogger.V(3).Info("delete datasource from grafana", "grafanaName", graf.Name)
-		state := common.NewClusterState()
-		if err = state.Read(r.context, graf, r.client); err != nil {
-			reqLogger.Error(err, "error reading state")
-			continue
-		}
-
-		client, err := common.NewGrafanaClient(graf, state)
+		client, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
 		if err != nil {
-			reqLogger.Error(err, "newGrafanaClient failed")
+			reqLogger.Error(err, "grafana not ready", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
 			continue
 		}
 
-		if _, err := client.GetDatasourceByName(cr.Spec.Datasources.Name); err != nil {
-			if err == grafanaClient.NotFoundError {
-				reqLogger.Info("datasource already be deleted or not installed", "grafana", graf.Name)
+		deleted := true
+		for _, name := range names {
+			if err := deleteDatasource(r.context, reqLogger, graf, client, cr, name, orgIds[name]); err != nil {
+				deleted = false
 			}
-			reqLogger.Error(err, "cannot get datasource", "grafana", graf.Name)
-			continue
 		}
-
-		if _, err = client.DeleteDatasourceByName(cr.Spec.Datasources.Name); err != nil {
-			if err == grafanaClient.NotFoundError {
-				reqLogger.Info("datasource already be deleted", "grafana", graf.Name)
-			}
-			reqLogger.Error(err, "cannot delete datasource", "grafana", graf.Name)
-			continue
+		if !deleted {
+			failed = append(failed, graf.Name)
 		}
 	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot delete datasources from grafana %v", strings.Join(failed, ", "))
+	}
 	return nil
 }
 
@@ -0,0 +1,44 @@
+package grafanadatasource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+// Only datasources created by the operator for the grafana instance are deleted
+func TestDeleteDatasource(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodDelete {
+			deleted = append(deleted, req.URL.Path)
+		}
+		switch req.URL.Path {
+		case "/api/datasources/name/managed":
+			w.Write([]byte(`{"id": 1, "jsonData": {"` + common.ManagedDatasourceKey + `": "` + common.ManagedMarker(graf) + `"}}`))
+		case "/api/datasources/name/other-instance":
+			w.Write([]byte(`{"id": 2, "jsonData": {"` + common.ManagedDatasourceKey + `": "grafana-operator/monitoring/other"}}`))
+		default:
+			w.Write([]byte(`{"id": 3, "jsonData": {}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second})
+	cr := &v1alpha1.GrafanaDataSource{}
+	for _, name := range []string{"managed", "other-instance", "manual"} {
+		if err := deleteDatasource(context.Background(), log, graf, client, cr, name, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(deleted) != 1 || deleted[0] != "/api/datasources/name/managed" {
+		t.Errorf("expected only the managed datasource to be deleted, got %v", deleted)
+	}
+}
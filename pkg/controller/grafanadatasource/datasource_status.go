@@ -0,0 +1,135 @@
+package grafanadatasource
+
+import (
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Collects the status of every datasource of a resource across all matched
+// grafanas. A datasource is failing if it failed to sync in any grafana and
+// unhealthy if it is unhealthy in any grafana
+type itemStatuses struct {
+	names    []string
+	items    map[string]*grafanav1alpha1.GrafanaDataSourceItemStatus
+	previous map[string]grafanav1alpha1.GrafanaDataSourceItemStatus
+	health   map[string]grafanav1alpha1.Condition
+}
+
+func newItemStatuses(cr *grafanav1alpha1.GrafanaDataSource) *itemStatuses {
+	statuses := &itemStatuses{
+		items:    map[string]*grafanav1alpha1.GrafanaDataSourceItemStatus{},
+		previous: map[string]grafanav1alpha1.GrafanaDataSourceItemStatus{},
+		health:   map[string]grafanav1alpha1.Condition{},
+	}
+	for _, item := range cr.Status.Datasources {
+		statuses.previous[item.Name] = *item.DeepCopy()
+	}
+	for _, fields := range cr.Spec.Datasources {
+		statuses.get(fields.Name).OrgID = fields.OrgId
+	}
+	return statuses
+}
+
+func (s *itemStatuses) get(name string) *grafanav1alpha1.GrafanaDataSourceItemStatus {
+	item, ok := s.items[name]
+	if !ok {
+		// Conditions and instances are kept until they are checked again
+		item = &grafanav1alpha1.GrafanaDataSourceItemStatus{
+			Name:       name,
+			UID:        s.previous[name].UID,
+			OrgID:      s.previous[name].OrgID,
+			Phase:      grafanav1alpha1.PhaseReconciling,
+			Conditions: s.previous[name].Conditions,
+			Instances:  s.previous[name].Instances,
+		}
+		s.items[name] = item
+		s.names = append(s.names, name)
+	}
+	return item
+}
+
+func (s *itemStatuses) set(name, uid string, err error) {
+	item := s.get(name)
+	if uid != "" {
+		item.UID = uid
+	}
+
+	if err != nil {
+		item.Phase = grafanav1alpha1.PhaseFailing
+		item.Message = err.Error()
+	}
+}
+
+// Records the state of the datasource in a single grafana, synced is true if it
+// has just been submitted
+func (s *itemStatuses) setInstance(name string, graf *grafanav1alpha1.Grafana, response grafanaClient.GrafanaResponse, synced bool) {
+	item := s.get(name)
+	grafanav1alpha1.SetInstanceStatus(&item.Instances, common.InstanceStatus(graf, response, item.Instances, synced))
+}
+
+// Health checks failing in any grafana take precedence over passing checks, both
+// over checks with an unknown result
+func (s *itemStatuses) setHealth(name string, condition grafanav1alpha1.Condition) {
+	existing, ok := s.health[name]
+	if !ok || healthPriority(condition.Status) > healthPriority(existing.Status) {
+		s.health[name] = condition
+	}
+}
+
+func healthPriority(status v1.ConditionStatus) int {
+	switch status {
+	case v1.ConditionFalse:
+		return 2
+	case v1.ConditionTrue:
+		return 1
+	}
+	return 0
+}
+
+func (s *itemStatuses) setAll(names []string, err error) {
+	for _, name := range names {
+		s.set(name, "", err)
+	}
+}
+
+// Drops the removed datasources that did not fail to be deleted
+func (s *itemStatuses) dropDeleted(removed []string) {
+	for _, name := range removed {
+		item, ok := s.items[name]
+		if !ok || item.Phase == grafanav1alpha1.PhaseFailing {
+			continue
+		}
+
+		delete(s.items, name)
+		for i, existing := range s.names {
+			if existing == name {
+				s.names = append(s.names[:i], s.names[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (s *itemStatuses) failed() int {
+	failed := 0
+	for _, item := range s.items {
+		if item.Phase == grafanav1alpha1.PhaseFailing {
+			failed++
+		}
+	}
+	return failed
+}
+
+func (s *itemStatuses) list() []grafanav1alpha1.GrafanaDataSourceItemStatus {
+	list := make([]grafanav1alpha1.GrafanaDataSourceItemStatus, 0, len(s.names))
+	for _, name := range s.names {
+		item := s.items[name]
+		if health, ok := s.health[name]; ok {
+			grafanav1alpha1.SetCondition(&item.Conditions, health)
+		}
+		list = append(list, *item)
+	}
+	return list
+}
@@ -0,0 +1,39 @@
+package grafanadatasource
+
+import (
+	"fmt"
+	"testing"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+)
+
+func TestDropDeleted(t *testing.T) {
+	cr := &grafanav1alpha1.GrafanaDataSource{
+		Spec: grafanav1alpha1.GrafanaDataSourceSpec{
+			Datasources: grafanav1alpha1.GrafanaDataSourceFieldsList{{Name: "prometheus"}},
+		},
+		Status: grafanav1alpha1.GrafanaDataSourceStatus{
+			Datasources: []grafanav1alpha1.GrafanaDataSourceItemStatus{
+				{Name: "prometheus"},
+				{Name: "deleted", OrgID: 2},
+				{Name: "failed", OrgID: 3},
+			},
+		},
+	}
+
+	removed := removedDatasources(cr)
+	items := newItemStatuses(cr)
+	for _, name := range removed {
+		items.get(name)
+	}
+	items.set("failed", "", fmt.Errorf("connection refused"))
+	items.dropDeleted(removed)
+
+	list := items.list()
+	if len(list) != 2 || list[0].Name != "prometheus" || list[1].Name != "failed" {
+		t.Fatalf("unexpected datasources %+v", list)
+	}
+	if list[1].OrgID != 3 {
+		t.Errorf("expected the organization of the removed datasource to be kept, got %v", list[1].OrgID)
+	}
+}
@@ -0,0 +1,110 @@
+package grafanadatasource
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+)
+
+// Field index of the datasources, used to find the datasources of an organization
+const organizationIndex = "spec.organization"
+
+func indexOrganization(o runtime.Object) []string {
+	datasource := o.(*grafanav1alpha1.GrafanaDataSource)
+	if datasource.Spec.Organization == "" {
+		return nil
+	}
+	return []string{datasource.Spec.Organization}
+}
+
+// Watches the grafana instances and organizations, datasources are synced again
+// when the selectors of a grafana change, when its deployment turns ready or when
+// their organization was created
+func watchGrafanas(mgr manager.Manager, c controller.Controller) error {
+	if err := mgr.GetFieldIndexer().IndexField(&grafanav1alpha1.GrafanaDataSource{}, organizationIndex, indexOrganization); err != nil {
+		return err
+	}
+
+	kubeclient := mgr.GetClient()
+
+	err := c.Watch(&source.Kind{Type: &grafanav1alpha1.Grafana{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return mapGrafanaToDatasources(kubeclient, o.Object.(*grafanav1alpha1.Grafana))
+		}),
+	}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			grafana := common.OwningGrafana(context.Background(), kubeclient, o.Meta)
+			if grafana == nil {
+				return nil
+			}
+			return mapGrafanaToDatasources(kubeclient, grafana)
+		}),
+	}, common.GrafanaBecameReady)
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &grafanav1alpha1.GrafanaOrganization{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return mapOrganizationToDatasources(kubeclient, o.Object.(*grafanav1alpha1.GrafanaOrganization))
+		}),
+	}, common.OrganizationChanged)
+}
+
+// Returns a request for every datasource of the organization
+func mapOrganizationToDatasources(c client.Client, organization *grafanav1alpha1.GrafanaOrganization) []reconcile.Request {
+	datasources := &grafanav1alpha1.GrafanaDataSourceList{}
+	err := c.List(context.Background(), datasources, client.InNamespace(organization.Namespace), client.MatchingField(organizationIndex, organization.OrganizationName()))
+	if err != nil {
+		log.Error(err, "error listing datasources")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(datasources.Items))
+	for _, datasource := range datasources.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: datasource.Namespace,
+			Name:      datasource.Name,
+		}})
+	}
+	return requests
+}
+
+// Returns a request for every datasource matching the datasource selectors of the grafana
+func mapGrafanaToDatasources(c client.Client, grafana *grafanav1alpha1.Grafana) []reconcile.Request {
+	datasources := &grafanav1alpha1.GrafanaDataSourceList{}
+	if err := c.List(context.Background(), datasources, client.InNamespace(grafana.ResourceNamespace())); err != nil {
+		log.Error(err, "error listing datasources")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, datasource := range datasources.Items {
+		match, err := common.MatchesSelectors(datasource.Labels, grafana.Spec.DatasourceLabelSelector)
+		if err != nil || !match {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: datasource.Namespace,
+			Name:      datasource.Name,
+		}})
+	}
+	return requests
+}
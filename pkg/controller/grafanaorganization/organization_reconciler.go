@@ -0,0 +1,261 @@
+package grafanaorganization
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+// Role of the operator admin user in all organizations, required to manage the
+// dashboards and datasources of the organization
+const adminRole = "Admin"
+
+func (r *ReconcileGrafanaOrganization) reconcile(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaOrganization) error {
+	matchedGrafs, err := common.MatchGrafana(r.context, r.client, reqLogger, cr.Namespace, cr.Labels, common.MatchByOrganization)
+	if err != nil {
+		reqLogger.Error(err, "matchGrafana failed.")
+		return err
+	}
+
+	instances := cr.Status.DeepCopy().Instances
+	var failed []string
+	for _, graf := range matchedGrafs {
+		reqLogger.V(3).Info("reconcile organization for grafana", "grafanaName", graf.Name)
+		previous := grafanav1alpha1.FindInstanceStatus(instances, graf.Namespace, graf.Name)
+		orgId, created, err := r.reconcileOrganization(cr, graf, previous)
+		if err != nil {
+			reqLogger.Error(err, "cannot reconcile organization", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
+			continue
+		}
+
+		// The last sync is the time the organization was created or adopted
+		status := grafanav1alpha1.GrafanaInstanceStatus{
+			Namespace: graf.Namespace,
+			Name:      graf.Name,
+			ID:        orgId,
+			Created:   created,
+		}
+		if previous != nil && previous.ID == orgId {
+			status.LastSync = previous.LastSync
+		} else {
+			now := metav1.Now()
+			status.LastSync = &now
+		}
+		grafanav1alpha1.SetInstanceStatus(&instances, status)
+	}
+
+	cr.Status.Instances = instances
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot reconcile organization in grafana %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Creates the organization if it does not exist yet, renames it if the name in
+// the spec changed and applies the preferences. Existing organizations are only
+// adopted if no other resource or tenant claims them. Returns the id of the
+// organization and whether the operator created it
+func (r *ReconcileGrafanaOrganization) reconcileOrganization(cr *grafanav1alpha1.GrafanaOrganization, graf *grafanav1alpha1.Grafana, previous *grafanav1alpha1.GrafanaInstanceStatus) (uint, bool, error) {
+	client, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
+	if err != nil {
+		return 0, false, err
+	}
+
+	name := cr.OrganizationName()
+	org, err := client.GetOrganizationByName(r.context, name)
+	var orgId uint
+	created := previous != nil && previous.Created
+	switch {
+	case err == nil:
+		orgId = org.ID
+		if previous == nil || previous.ID != orgId {
+			if err := r.claimOrganization(cr, graf, orgId); err != nil {
+				return 0, false, err
+			}
+			created = false
+			r.recorder.Eventf(cr, "Normal", "Adopted", "existing organization %v adopted in grafana %v", name, graf.Name)
+		}
+
+		// Organizations created outside of the operator might not include the
+		// admin user yet
+		login, err := common.AdminLogin(r.context, r.client, graf)
+		if err != nil {
+			return 0, false, err
+		}
+		if err := client.AddOrganizationUser(r.context, orgId, login, adminRole); err != nil {
+			return 0, false, err
+		}
+	case err == grafanaClient.NotFoundError && previous != nil && previous.ID > grafanaClient.DefaultOrganizationId:
+		orgId = previous.ID
+		if err := client.RenameOrganization(r.context, orgId, name); err != nil {
+			return 0, false, err
+		}
+		r.recorder.Eventf(cr, "Normal", "Renamed", "organization renamed to %v in grafana %v", name, graf.Name)
+	case err == grafanaClient.NotFoundError:
+		if err := r.claimName(cr, graf); err != nil {
+			return 0, false, err
+		}
+		if orgId, err = client.CreateOrganization(r.context, name); err != nil {
+			return 0, false, err
+		}
+		created = true
+		r.recorder.Eventf(cr, "Normal", "Created", "organization %v created in grafana %v", name, graf.Name)
+	default:
+		return 0, false, err
+	}
+
+	if cr.Spec.Preferences != nil {
+		if err := r.updatePreferences(cr.Spec.Preferences, client.WithOrganization(orgId)); err != nil {
+			return 0, false, err
+		}
+	}
+	return orgId, created, nil
+}
+
+// Refuses to adopt the organization of a namespace with namespaceOrgs tenancy or
+// an organization that another resource manages
+func (r *ReconcileGrafanaOrganization) claimOrganization(cr *grafanav1alpha1.GrafanaOrganization, graf *grafanav1alpha1.Grafana, orgId uint) error {
+	for _, tenant := range graf.Status.Tenants {
+		if tenant.OrgID == orgId {
+			return fmt.Errorf("organization %v belongs to namespace %v", cr.OrganizationName(), tenant.Namespace)
+		}
+	}
+
+	others, err := r.otherOrganizations(cr, graf)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		instance := grafanav1alpha1.FindInstanceStatus(other.Status.Instances, graf.Namespace, graf.Name)
+		if instance != nil && instance.ID == orgId {
+			return fmt.Errorf("organization %v is managed by %v", cr.OrganizationName(), other.Name)
+		}
+	}
+	return r.claimName(cr, graf)
+}
+
+// The oldest of the resources with the same organization name manages the
+// organization
+func (r *ReconcileGrafanaOrganization) claimName(cr *grafanav1alpha1.GrafanaOrganization, graf *grafanav1alpha1.Grafana) error {
+	others, err := r.otherOrganizations(cr, graf)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.OrganizationName() != cr.OrganizationName() {
+			continue
+		}
+		if other.CreationTimestamp.Before(&cr.CreationTimestamp) || (other.CreationTimestamp.Equal(&cr.CreationTimestamp) && other.Name < cr.Name) {
+			return fmt.Errorf("organization %v is managed by %v", cr.OrganizationName(), other.Name)
+		}
+	}
+	return nil
+}
+
+// Returns the other organization resources of the grafana instance
+func (r *ReconcileGrafanaOrganization) otherOrganizations(cr *grafanav1alpha1.GrafanaOrganization, graf *grafanav1alpha1.Grafana) ([]grafanav1alpha1.GrafanaOrganization, error) {
+	list := &grafanav1alpha1.GrafanaOrganizationList{}
+	if err := r.client.List(r.context, list, client.InNamespace(graf.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var others []grafanav1alpha1.GrafanaOrganization
+	for _, other := range list.Items {
+		if other.UID == cr.UID || other.DeletionTimestamp != nil {
+			continue
+		}
+		match, err := common.MatchesSelectors(other.Labels, graf.Spec.OrganizationLabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			others = append(others, other)
+		}
+	}
+	return others, nil
+}
+
+func (r *ReconcileGrafanaOrganization) updatePreferences(preferences *grafanav1alpha1.GrafanaOrganizationPreferences, client grafanaClient.GrafanaClient) error {
+	request := grafanaClient.GrafanaPreferences{
+		Theme:    preferences.Theme,
+		Timezone: preferences.Timezone,
+	}
+
+	if preferences.HomeDashboardUID != "" {
+		dashboard, err := client.GetDashboardByUID(r.context, preferences.HomeDashboardUID)
+		if err != nil {
+			if err == grafanaClient.NotFoundError {
+				return fmt.Errorf("home dashboard %v does not exist", preferences.HomeDashboardUID)
+			}
+			return err
+		}
+		request.HomeDashboardId = *dashboard.ID
+	}
+
+	return client.UpdateOrganizationPreferences(r.context, request)
+}
+
+// Deletes the organization with all its dashboards and datasources from every
+// matching grafana, according to the deletion policy. The organization is
+// deleted by the id recorded in the status, the default organization is never
+// deleted
+func (r *ReconcileGrafanaOrganization) reconcileDelete(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaOrganization) error {
+	if cr.Spec.DeletionPolicy == grafanav1alpha1.OrganizationDeletionPolicyRetain {
+		return nil
+	}
+
+	matchedGrafs, err := common.MatchGrafana(r.context, r.client, reqLogger, cr.Namespace, cr.Labels, common.MatchByOrganization)
+	if err != nil {
+		reqLogger.Error(err, "matchGrafana failed.")
+		return err
+	}
+
+	var failed []string
+	for _, graf := range matchedGrafs {
+		instance := grafanav1alpha1.FindInstanceStatus(cr.Status.Instances, graf.Namespace, graf.Name)
+		if !deleteOrganization(cr, instance) {
+			continue
+		}
+
+		reqLogger.V(3).Info("delete organization from grafana", "grafanaName", graf.Name)
+		client, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
+		if err != nil {
+			reqLogger.Error(err, "grafana not ready", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
+			continue
+		}
+
+		err = client.DeleteOrganization(r.context, instance.ID)
+		if err != nil && err != grafanaClient.NotFoundError {
+			reqLogger.Error(err, "cannot delete organization", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot delete organization from grafana %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Adopted organizations are only deleted with the Delete policy
+func deleteOrganization(cr *grafanav1alpha1.GrafanaOrganization, instance *grafanav1alpha1.GrafanaInstanceStatus) bool {
+	if instance == nil || instance.ID <= grafanaClient.DefaultOrganizationId {
+		return false
+	}
+	switch cr.Spec.DeletionPolicy {
+	case grafanav1alpha1.OrganizationDeletionPolicyDelete:
+		return true
+	case grafanav1alpha1.OrganizationDeletionPolicyRetain:
+		return false
+	}
+	return instance.Created
+}
@@ -0,0 +1,150 @@
+package grafanaorganization
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
+	"github.com/ucloud/grafana-operator/pkg/controller/model"
+)
+
+// Returns a grafana instance selecting the organizations labeled app=grafana and
+// its service and admin secret, grafana is reached at the server url
+func newTestGrafana(t *testing.T, serverUrl string) (*grafanav1alpha1.Grafana, []runtime.Object) {
+	parsed, err := url.Parse(serverUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graf := &grafanav1alpha1.Grafana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana"},
+		Spec: grafanav1alpha1.GrafanaSpec{
+			Config: grafanav1alpha1.GrafanaConfig{
+				Server: &grafanav1alpha1.GrafanaConfigServer{HttpPort: parsed.Port()},
+			},
+			OrganizationLabelSelector: []*metav1.LabelSelector{{MatchLabels: map[string]string{"app": "grafana"}}},
+		},
+	}
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: graf.Namespace, Name: model.GrafanaServiceSelector(graf).Name},
+		Spec:       v1.ServiceSpec{ClusterIP: parsed.Hostname()},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: graf.Namespace, Name: model.AdminSecretSelector(graf).Name},
+		Data: map[string][]byte{
+			model.GrafanaAdminUserEnvVar:     []byte("admin"),
+			model.GrafanaAdminPasswordEnvVar: []byte("admin"),
+		},
+	}
+	return graf, []runtime.Object{graf, service, secret}
+}
+
+func newTestReconciler(t *testing.T, objects ...runtime.Object) *ReconcileGrafanaOrganization {
+	s := runtime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := grafanav1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	kubeclient := fake.NewFakeClientWithScheme(s, objects...)
+	recorder := record.NewFakeRecorder(10)
+	return &ReconcileGrafanaOrganization{
+		client:   kubeclient,
+		context:  context.Background(),
+		recorder: recorder,
+		resources: &common.SyncedResourceReconciler{
+			Client:    kubeclient,
+			Context:   context.Background(),
+			Recorder:  recorder,
+			Log:       log,
+			Kind:      metrics.KindGrafanaOrganization,
+			Finalizer: organizationFinalizer,
+		},
+	}
+}
+
+func TestReconcileOrganization(t *testing.T) {
+	var orgId string
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			requests = append(requests, req.Method+" "+req.URL.Path)
+		}
+		switch {
+		case req.URL.Path == "/api/health":
+			w.Write([]byte(`{}`))
+		case req.URL.Path == "/api/orgs/name/team a" && orgId == "":
+			w.WriteHeader(http.StatusNotFound)
+		case req.URL.Path == "/api/orgs/name/team a":
+			w.Write([]byte(`{"id": 5, "name": "team a"}`))
+		case req.Method == http.MethodPost && req.URL.Path == "/api/orgs":
+			orgId = "5"
+			w.Write([]byte(`{"orgId": 5}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	graf, objects := newTestGrafana(t, server.URL)
+	organization := &grafanav1alpha1.GrafanaOrganization{
+		ObjectMeta: metav1.ObjectMeta{Namespace: graf.Namespace, Name: "team-a", Labels: map[string]string{"app": "grafana"}},
+		Spec:       grafanav1alpha1.GrafanaOrganizationSpec{Name: "team a"},
+	}
+	r := newTestReconciler(t, append(objects, organization)...)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: organization.Namespace, Name: organization.Name}}
+
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.client.Get(r.context, request.NamespacedName, organization); err != nil {
+		t.Fatal(err)
+	}
+	instance := grafanav1alpha1.FindInstanceStatus(organization.Status.Instances, graf.Namespace, graf.Name)
+	if organization.Status.Phase != grafanav1alpha1.PhaseReconciling || instance == nil || instance.ID != 5 || !instance.Created {
+		t.Fatalf("expected the organization to be created, got status %+v", organization.Status)
+	}
+	if !reflect.DeepEqual(organization.Finalizers, []string{organizationFinalizer}) {
+		t.Errorf("expected the finalizer to be added, got %v", organization.Finalizers)
+	}
+
+	// The organization is deleted by the id in the status before the finalizer
+	// is removed
+	now := metav1.Now()
+	organization.DeletionTimestamp = &now
+	if err := r.client.Update(r.context, organization); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatal(err)
+	}
+	deleted := &grafanav1alpha1.GrafanaOrganization{}
+	if err := r.client.Get(r.context, request.NamespacedName, deleted); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted.Finalizers) > 0 {
+		t.Errorf("expected the finalizer to be removed, got %v", deleted.Finalizers)
+	}
+
+	expected := []string{"POST /api/orgs", "DELETE /api/orgs/5"}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+}
@@ -0,0 +1,286 @@
+package grafanaserviceaccount
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+func (r *ReconcileGrafanaServiceAccount) reconcile(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaServiceAccount) error {
+	matchedGrafs, err := common.MatchGrafana(r.context, r.client, reqLogger, cr.Namespace, cr.Labels, common.MatchByServiceAccount)
+	if err != nil {
+		reqLogger.Error(err, "matchGrafana failed.")
+		return err
+	}
+
+	secret, err := r.tokenSecret(cr)
+	if err != nil {
+		return err
+	}
+
+	rotation := cr.Annotations[grafanav1alpha1.RotateTokenAnnotation]
+	rotate := rotation != cr.Status.Rotation
+
+	instances := cr.Status.DeepCopy().Instances
+	var failed []string
+	for _, graf := range matchedGrafs {
+		reqLogger.V(3).Info("reconcile service account for grafana", "grafanaName", graf.Name)
+		previous := grafanav1alpha1.FindInstanceStatus(instances, graf.Namespace, graf.Name)
+		accountId, created, err := r.reconcileServiceAccount(cr, graf, previous, secret, rotate)
+		if err != nil {
+			reqLogger.Error(err, "cannot reconcile service account", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
+			continue
+		}
+
+		// The last sync is the time the token was created
+		status := grafanav1alpha1.GrafanaInstanceStatus{
+			Namespace: graf.Namespace,
+			Name:      graf.Name,
+			ID:        accountId,
+		}
+		if previous != nil && !created {
+			status.LastSync = previous.LastSync
+		} else {
+			now := metav1.Now()
+			status.LastSync = &now
+		}
+		grafanav1alpha1.SetInstanceStatus(&instances, status)
+	}
+
+	cr.Status.Instances = instances
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot reconcile service account in grafana %v", strings.Join(failed, ", "))
+	}
+	cr.Status.Rotation = rotation
+	return nil
+}
+
+// Creates or updates the service account and creates a new token if the token is
+// due. The new token is written to the secret before the previous tokens of the
+// operator are revoked. Returns the id of the service account and whether a token was created
+func (r *ReconcileGrafanaServiceAccount) reconcileServiceAccount(cr *grafanav1alpha1.GrafanaServiceAccount, graf *grafanav1alpha1.Grafana, previous *grafanav1alpha1.GrafanaInstanceStatus, secret *v1.Secret, rotate bool) (uint, bool, error) {
+	instanceClient, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
+	if err != nil {
+		return 0, false, err
+	}
+	client, err := common.OrganizationClient(r.context, instanceClient, cr.Spec.Organization)
+	if err != nil {
+		return 0, false, err
+	}
+
+	accountId, err := r.ensureServiceAccount(cr, graf, client, previous)
+	if err != nil {
+		return 0, false, err
+	}
+
+	tokens, err := r.operatorTokens(cr, client, accountId)
+	if err != nil {
+		return 0, false, err
+	}
+	if !rotate && !tokenDue(cr, previous, accountId, secret.Data[graf.Name], tokens, time.Now()) {
+		return accountId, false, nil
+	}
+
+	token, err := client.CreateServiceAccountToken(r.context, accountId, tokenName(cr, time.Now()))
+	if err != nil {
+		return 0, false, err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[graf.Name] = []byte(token.Key)
+	if err := r.writeSecret(secret); err != nil {
+		// The token is revoked by the next rotation
+		return 0, false, err
+	}
+	r.recorder.Eventf(cr, "Normal", "TokenCreated", "token of grafana %v written to secret %v", graf.Name, secret.Name)
+
+	for _, previousToken := range tokens {
+		if err := client.DeleteServiceAccountToken(r.context, accountId, previousToken.ID); err != nil && err != grafanaClient.NotFoundError {
+			return 0, false, err
+		}
+	}
+	return accountId, true, nil
+}
+
+// Only the service account recorded in the status is updated, an existing
+// service account with the same name that the operator did not create is a
+// conflict
+func (r *ReconcileGrafanaServiceAccount) ensureServiceAccount(cr *grafanav1alpha1.GrafanaServiceAccount, graf *grafanav1alpha1.Grafana, client grafanaClient.GrafanaClient, previous *grafanav1alpha1.GrafanaInstanceStatus) (uint, error) {
+	request := grafanaClient.GrafanaServiceAccountRequest{
+		Name:       cr.ServiceAccountName(),
+		Role:       cr.ServiceAccountRole(),
+		IsDisabled: cr.Spec.Disabled,
+	}
+
+	account, err := client.GetServiceAccountByName(r.context, request.Name)
+	if err == nil {
+		if previous == nil || previous.ID != account.ID {
+			return 0, fmt.Errorf("service account %v already exists in grafana %v and was not created by the operator", request.Name, graf.Name)
+		}
+		if account.Role != request.Role || account.IsDisabled != request.IsDisabled {
+			return account.ID, client.UpdateServiceAccount(r.context, account.ID, request)
+		}
+		return account.ID, nil
+	}
+	if err != grafanaClient.NotFoundError {
+		return 0, err
+	}
+
+	if previous != nil && previous.ID > 0 {
+		err := client.UpdateServiceAccount(r.context, previous.ID, request)
+		if err == nil {
+			r.recorder.Eventf(cr, "Normal", "Renamed", "service account renamed to %v in grafana %v", request.Name, graf.Name)
+			return previous.ID, nil
+		}
+		if err != grafanaClient.NotFoundError {
+			return 0, err
+		}
+	}
+
+	accountId, err := client.CreateServiceAccount(r.context, request)
+	if err == grafanaClient.ConflictError {
+		return 0, fmt.Errorf("service account %v already exists", request.Name)
+	}
+	if err != nil {
+		return 0, err
+	}
+	r.recorder.Eventf(cr, "Normal", "Created", "service account %v created in grafana %v", request.Name, graf.Name)
+	return accountId, nil
+}
+
+// Returns the tokens of the service account created by the operator, tokens
+// created by others are left alone
+func (r *ReconcileGrafanaServiceAccount) operatorTokens(cr *grafanav1alpha1.GrafanaServiceAccount, client grafanaClient.GrafanaClient, accountId uint) ([]grafanaClient.GrafanaServiceAccountToken, error) {
+	tokens, err := client.ListServiceAccountTokens(r.context, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	var own []grafanaClient.GrafanaServiceAccountToken
+	for _, token := range tokens {
+		if operatorToken(cr, token.Name) {
+			own = append(own, token)
+		}
+	}
+	return own, nil
+}
+
+// Tokens are named after the resource and the time they were created
+func tokenName(cr *grafanav1alpha1.GrafanaServiceAccount, now time.Time) string {
+	return fmt.Sprintf("%v-%v", cr.Name, now.Unix())
+}
+
+func operatorToken(cr *grafanav1alpha1.GrafanaServiceAccount, name string) bool {
+	prefix := cr.Name + "-"
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	_, err := strconv.ParseInt(strings.TrimPrefix(name, prefix), 10, 64)
+	return err == nil
+}
+
+// A new token is due if the service account has no token in grafana or in the
+// secret, if the service account was replaced or if the token is older than the
+// rotation period
+func tokenDue(cr *grafanav1alpha1.GrafanaServiceAccount, previous *grafanav1alpha1.GrafanaInstanceStatus, accountId uint, key []byte, tokens []grafanaClient.GrafanaServiceAccountToken, now time.Time) bool {
+	if previous == nil || previous.ID != accountId || previous.LastSync == nil {
+		return true
+	}
+	if len(key) == 0 || len(tokens) == 0 {
+		return true
+	}
+	if period := cr.Spec.RotationPeriod; period != nil && period.Duration > 0 {
+		return now.Sub(previous.LastSync.Time) >= period.Duration
+	}
+	return false
+}
+
+// Returns the secret containing the tokens, or a new secret owned by the
+// service account if it does not exist yet
+func (r *ReconcileGrafanaServiceAccount) tokenSecret(cr *grafanav1alpha1.GrafanaServiceAccount) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	key := types.NamespacedName{Namespace: cr.Namespace, Name: cr.TokenSecretName()}
+	err := r.client.Get(r.context, key, secret)
+	if err == nil {
+		return secret, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	secret = &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+		Type: v1.SecretTypeOpaque,
+	}
+	if err := controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+func (r *ReconcileGrafanaServiceAccount) writeSecret(secret *v1.Secret) error {
+	if secret.ResourceVersion == "" {
+		return r.client.Create(r.context, secret)
+	}
+	return r.client.Update(r.context, secret)
+}
+
+// Deletes the service account with its tokens from every matching grafana, by the
+// id recorded in the status. The secret is deleted by the garbage collector
+func (r *ReconcileGrafanaServiceAccount) reconcileDelete(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaServiceAccount) error {
+	matchedGrafs, err := common.MatchGrafana(r.context, r.client, reqLogger, cr.Namespace, cr.Labels, common.MatchByServiceAccount)
+	if err != nil {
+		reqLogger.Error(err, "matchGrafana failed.")
+		return err
+	}
+
+	var failed []string
+	for _, graf := range matchedGrafs {
+		instance := grafanav1alpha1.FindInstanceStatus(cr.Status.Instances, graf.Namespace, graf.Name)
+		if instance == nil || instance.ID == 0 {
+			continue
+		}
+
+		reqLogger.V(3).Info("delete service account from grafana", "grafanaName", graf.Name)
+		instanceClient, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
+		if err != nil {
+			reqLogger.Error(err, "grafana not ready", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
+			continue
+		}
+
+		client, err := common.OrganizationClient(r.context, instanceClient, cr.Spec.Organization)
+		if _, ok := err.(*common.OrganizationNotFoundError); ok {
+			continue
+		}
+		if err == nil {
+			err = client.DeleteServiceAccount(r.context, instance.ID)
+		}
+		if err != nil && err != grafanaClient.NotFoundError {
+			reqLogger.Error(err, "cannot delete service account", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot delete service account from grafana %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
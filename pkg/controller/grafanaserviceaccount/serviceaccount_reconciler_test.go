@@ -0,0 +1,104 @@
+package grafanaserviceaccount
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+func TestTokenDue(t *testing.T) {
+	now := time.Now()
+	created := metav1.NewTime(now.Add(-48 * time.Hour))
+	previous := &grafanav1alpha1.GrafanaInstanceStatus{ID: 3, LastSync: &created}
+	tokens := []grafanaClient.GrafanaServiceAccountToken{{ID: 1, Name: "ci-1"}}
+	key := []byte("glsa_token")
+
+	cr := &grafanav1alpha1.GrafanaServiceAccount{}
+	rotating := &grafanav1alpha1.GrafanaServiceAccount{
+		Spec: grafanav1alpha1.GrafanaServiceAccountSpec{
+			RotationPeriod: &metav1.Duration{Duration: 24 * time.Hour},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		cr       *grafanav1alpha1.GrafanaServiceAccount
+		previous *grafanav1alpha1.GrafanaInstanceStatus
+		id       uint
+		key      []byte
+		tokens   []grafanaClient.GrafanaServiceAccountToken
+		due      bool
+	}{
+		{"valid token", cr, previous, 3, key, tokens, false},
+		{"new instance", cr, nil, 3, key, tokens, true},
+		{"new service account", cr, previous, 4, key, tokens, true},
+		{"missing key", cr, previous, 3, nil, tokens, true},
+		{"revoked token", cr, previous, 3, key, nil, true},
+		{"expired token", rotating, previous, 3, key, tokens, true},
+	}
+
+	for _, c := range cases {
+		if due := tokenDue(c.cr, c.previous, c.id, c.key, c.tokens, now); due != c.due {
+			t.Errorf("%v: expected due %v, got %v", c.name, c.due, due)
+		}
+	}
+}
+
+func TestOperatorToken(t *testing.T) {
+	cr := &grafanav1alpha1.GrafanaServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "ci"}}
+
+	if name := tokenName(cr, time.Unix(1700000000, 0)); !operatorToken(cr, name) {
+		t.Errorf("expected %v to be a token of the operator", name)
+	}
+	for _, name := range []string{"ci", "ci-deploy-1700000000", "manual", "ci-"} {
+		if operatorToken(cr, name) {
+			t.Errorf("expected %v not to be a token of the operator", name)
+		}
+	}
+}
+
+// Only the service account recorded in the status is managed
+func TestEnsureServiceAccount(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			requests = append(requests, req.Method+" "+req.URL.Path)
+		}
+		w.Write([]byte(`{"serviceAccounts": [{"id": 5, "name": "ci", "role": "Admin"}]}`))
+	}))
+	defer server.Close()
+
+	client := grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second})
+	r := &ReconcileGrafanaServiceAccount{context: context.Background(), recorder: record.NewFakeRecorder(10)}
+	graf := &grafanav1alpha1.Grafana{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana"}}
+	cr := &grafanav1alpha1.GrafanaServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "ci"},
+		Spec:       grafanav1alpha1.GrafanaServiceAccountSpec{Role: "Viewer"},
+	}
+
+	if _, err := r.ensureServiceAccount(cr, graf, client, nil); err == nil {
+		t.Error("expected a conflict for a service account not created by the operator")
+	}
+	if _, err := r.ensureServiceAccount(cr, graf, client, &grafanav1alpha1.GrafanaInstanceStatus{ID: 6}); err == nil {
+		t.Error("expected a conflict for a service account with another id")
+	}
+	if len(requests) > 0 {
+		t.Fatalf("expected no requests, got %v", requests)
+	}
+
+	accountId, err := r.ensureServiceAccount(cr, graf, client, &grafanav1alpha1.GrafanaInstanceStatus{ID: 5})
+	if err != nil || accountId != 5 {
+		t.Fatalf("expected the service account to be updated, got %v, %v", accountId, err)
+	}
+	if len(requests) != 1 || requests[0] != "PATCH /api/serviceaccounts/5" {
+		t.Errorf("expected the role to be updated, got %v", requests)
+	}
+}
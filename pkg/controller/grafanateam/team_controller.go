@@ -0,0 +1,141 @@
+package grafanateam
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
+)
+
+const (
+	ControllerName = "controller_grafanateam"
+	teamFinalizer  = "finalizer.grafanateams.monitor.kun"
+)
+
+var log = logf.Log.WithName(ControllerName)
+
+// Add creates a new GrafanaTeam Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, _ chan schema.GroupVersionKind) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+
+	kubeclient := mgr.GetClient()
+	recorder := mgr.GetEventRecorderFor(ControllerName)
+	return &ReconcileGrafanaTeam{
+		client:   kubeclient,
+		context:  ctx,
+		cancel:   cancel,
+		recorder: recorder,
+		resources: &common.SyncedResourceReconciler{
+			Client:    kubeclient,
+			Context:   ctx,
+			Recorder:  recorder,
+			Log:       log,
+			Kind:      metrics.KindGrafanaTeam,
+			Finalizer: teamFinalizer,
+		},
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("grafanateam-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource GrafanaTeam
+	err = c.Watch(&source.Kind{Type: &grafanav1alpha1.GrafanaTeam{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Teams are created once a grafana selects them and is ready
+	kubeclient := mgr.GetClient()
+	err = c.Watch(&source.Kind{Type: &grafanav1alpha1.Grafana{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return mapGrafanaToTeams(kubeclient, o.Object.(*grafanav1alpha1.Grafana))
+		}),
+	}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			grafana := common.OwningGrafana(context.Background(), kubeclient, o.Meta)
+			if grafana == nil {
+				return nil
+			}
+			return mapGrafanaToTeams(kubeclient, grafana)
+		}),
+	}, common.GrafanaBecameReady)
+}
+
+// Returns a request for every team matching the team selectors of the grafana
+func mapGrafanaToTeams(c client.Client, grafana *grafanav1alpha1.Grafana) []reconcile.Request {
+	teams := &grafanav1alpha1.GrafanaTeamList{}
+	if err := c.List(context.Background(), teams, client.InNamespace(grafana.Namespace)); err != nil {
+		log.Error(err, "error listing teams")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, team := range teams.Items {
+		match, err := common.MatchesSelectors(team.Labels, grafana.Spec.TeamLabelSelector)
+		if err != nil || !match {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: team.Namespace,
+			Name:      team.Name,
+		}})
+	}
+	return requests
+}
+
+var _ reconcile.Reconciler = &ReconcileGrafanaTeam{}
+
+// ReconcileGrafanaTeam reconciles a GrafanaTeam object
+type ReconcileGrafanaTeam struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client    client.Client
+	context   context.Context
+	cancel    context.CancelFunc
+	recorder  record.EventRecorder
+	resources *common.SyncedResourceReconciler
+}
+
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileGrafanaTeam) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	instance := &grafanav1alpha1.GrafanaTeam{}
+	return r.resources.Reconcile(request, instance, func(reqLogger logr.Logger) error {
+		return r.reconcile(reqLogger, instance)
+	}, func(reqLogger logr.Logger) error {
+		return r.reconcileDelete(reqLogger, instance)
+	})
+}
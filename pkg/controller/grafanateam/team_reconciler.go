@@ -0,0 +1,251 @@
+package grafanateam
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+func (r *ReconcileGrafanaTeam) reconcile(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaTeam) error {
+	matchedGrafs, err := common.MatchGrafana(r.context, r.client, reqLogger, cr.Namespace, cr.Labels, common.MatchByTeam)
+	if err != nil {
+		reqLogger.Error(err, "matchGrafana failed.")
+		return err
+	}
+
+	instances := cr.Status.DeepCopy().Instances
+	var failed []string
+	for _, graf := range matchedGrafs {
+		reqLogger.V(3).Info("reconcile team for grafana", "grafanaName", graf.Name)
+		previous := grafanav1alpha1.FindInstanceStatus(instances, graf.Namespace, graf.Name)
+		teamId, created, err := r.reconcileTeam(cr, graf, previous)
+		if err != nil {
+			reqLogger.Error(err, "cannot reconcile team", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
+			continue
+		}
+
+		// The last sync is the time the team was created or adopted
+		status := grafanav1alpha1.GrafanaInstanceStatus{
+			Namespace: graf.Namespace,
+			Name:      graf.Name,
+			ID:        teamId,
+			Created:   created,
+		}
+		if previous != nil && previous.ID == teamId {
+			status.LastSync = previous.LastSync
+		} else {
+			now := metav1.Now()
+			status.LastSync = &now
+		}
+		grafanav1alpha1.SetInstanceStatus(&instances, status)
+	}
+
+	cr.Status.Instances = instances
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot reconcile team in grafana %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Creates the team if it does not exist yet, renames it if the name in the spec
+// changed and syncs its members and external groups. Returns the id of the team
+// and whether the operator created it
+func (r *ReconcileGrafanaTeam) reconcileTeam(cr *grafanav1alpha1.GrafanaTeam, graf *grafanav1alpha1.Grafana, previous *grafanav1alpha1.GrafanaInstanceStatus) (uint, bool, error) {
+	instanceClient, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
+	if err != nil {
+		return 0, false, err
+	}
+	client, err := common.OrganizationClient(r.context, instanceClient, cr.Spec.Organization)
+	if err != nil {
+		return 0, false, err
+	}
+
+	teamId, created, err := r.ensureTeam(cr, graf, client, previous)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if err := syncTeamMembers(r.context, client, teamId, cr.Spec.Members); err != nil {
+		return 0, false, err
+	}
+	if len(cr.Spec.ExternalGroups) > 0 {
+		if err := syncTeamGroups(r.context, client, teamId, cr.Spec.ExternalGroups); err != nil {
+			return 0, false, err
+		}
+	}
+	return teamId, created, nil
+}
+
+// Teams that exist already are only adopted with the adopt annotation
+func (r *ReconcileGrafanaTeam) ensureTeam(cr *grafanav1alpha1.GrafanaTeam, graf *grafanav1alpha1.Grafana, client grafanaClient.GrafanaClient, previous *grafanav1alpha1.GrafanaInstanceStatus) (uint, bool, error) {
+	name := cr.TeamName()
+	created := previous != nil && previous.Created
+	team, err := client.GetTeamByName(r.context, name)
+	if err == nil {
+		if previous == nil || previous.ID != team.ID {
+			if !grafanav1alpha1.Adopts(cr) {
+				return 0, false, fmt.Errorf("team %v already exists in grafana %v and was not created by the operator, set the %v annotation to adopt it", name, graf.Name, grafanav1alpha1.AdoptAnnotation)
+			}
+			created = false
+			r.recorder.Eventf(cr, "Normal", "Adopted", "existing team %v adopted in grafana %v", name, graf.Name)
+		}
+		if team.Email != cr.Spec.Email {
+			return team.ID, created, client.UpdateTeam(r.context, team.ID, name, cr.Spec.Email)
+		}
+		return team.ID, created, nil
+	}
+	if err != grafanaClient.NotFoundError {
+		return 0, false, err
+	}
+
+	if previous != nil && previous.ID > 0 {
+		err := client.UpdateTeam(r.context, previous.ID, name, cr.Spec.Email)
+		if err == nil {
+			r.recorder.Eventf(cr, "Normal", "Renamed", "team renamed to %v in grafana %v", name, graf.Name)
+			return previous.ID, created, nil
+		}
+		if err != grafanaClient.NotFoundError {
+			return 0, false, err
+		}
+	}
+
+	teamId, err := client.CreateTeam(r.context, name, cr.Spec.Email)
+	if err != nil {
+		return 0, false, err
+	}
+	r.recorder.Eventf(cr, "Normal", "Created", "team %v created in grafana %v", name, graf.Name)
+	return teamId, true, nil
+}
+
+// Adds the listed users to the team and removes all other members
+func syncTeamMembers(ctx context.Context, client grafanaClient.GrafanaClient, teamId uint, members []string) error {
+	desired := map[uint]string{}
+	for _, member := range members {
+		user, err := client.GetUserByLogin(ctx, member)
+		if err != nil {
+			if err == grafanaClient.NotFoundError {
+				return fmt.Errorf("user %v does not exist", member)
+			}
+			return err
+		}
+		desired[user.ID] = member
+	}
+
+	current, err := client.ListTeamMembers(ctx, teamId)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, member := range current {
+		if _, ok := desired[member.UserID]; ok {
+			delete(desired, member.UserID)
+			continue
+		}
+		if err := client.RemoveTeamMember(ctx, teamId, member.UserID); err != nil && err != grafanaClient.NotFoundError {
+			failed = append(failed, member.Login)
+		}
+	}
+
+	var added []uint
+	for userId := range desired {
+		added = append(added, userId)
+	}
+	sort.Slice(added, func(i, j int) bool { return added[i] < added[j] })
+	for _, userId := range added {
+		if err := client.AddTeamMember(ctx, teamId, userId); err != nil {
+			failed = append(failed, desired[userId])
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot update the team membership of %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Adds the listed external groups to the team and removes all other groups
+func syncTeamGroups(ctx context.Context, client grafanaClient.GrafanaClient, teamId uint, groups []string) error {
+	current, err := client.ListTeamGroups(ctx, teamId)
+	if err != nil {
+		return err
+	}
+
+	desired := map[string]bool{}
+	for _, group := range groups {
+		desired[group] = true
+	}
+
+	for _, group := range current {
+		if desired[group] {
+			delete(desired, group)
+			continue
+		}
+		if err := client.RemoveTeamGroup(ctx, teamId, group); err != nil && err != grafanaClient.NotFoundError {
+			return err
+		}
+	}
+
+	for _, group := range groups {
+		if !desired[group] {
+			continue
+		}
+		if err := client.AddTeamGroup(ctx, teamId, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deletes the teams the operator created from every matching grafana, by the id
+// recorded in the status. Adopted teams are kept and teams of organizations that
+// no longer exist are gone already
+func (r *ReconcileGrafanaTeam) reconcileDelete(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaTeam) error {
+	matchedGrafs, err := common.MatchGrafana(r.context, r.client, reqLogger, cr.Namespace, cr.Labels, common.MatchByTeam)
+	if err != nil {
+		reqLogger.Error(err, "matchGrafana failed.")
+		return err
+	}
+
+	var failed []string
+	for _, graf := range matchedGrafs {
+		instance := grafanav1alpha1.FindInstanceStatus(cr.Status.Instances, graf.Namespace, graf.Name)
+		if instance == nil || instance.ID == 0 || !instance.Created {
+			continue
+		}
+
+		reqLogger.V(3).Info("delete team from grafana", "grafanaName", graf.Name)
+		instanceClient, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
+		if err != nil {
+			reqLogger.Error(err, "grafana not ready", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
+			continue
+		}
+
+		client, err := common.OrganizationClient(r.context, instanceClient, cr.Spec.Organization)
+		if _, ok := err.(*common.OrganizationNotFoundError); ok {
+			continue
+		}
+		if err == nil {
+			err = client.DeleteTeam(r.context, instance.ID)
+		}
+		if err != nil && err != grafanaClient.NotFoundError {
+			reqLogger.Error(err, "cannot delete team", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot delete team from grafana %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package grafanateam
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+func TestSyncTeamMembers(t *testing.T) {
+	users := map[string]string{
+		"alice": `{"id": 2, "login": "alice"}`,
+		"bob":   `{"id": 3, "login": "bob"}`,
+	}
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == "/api/users/lookup":
+			user, ok := users[req.URL.Query().Get("loginOrEmail")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(user))
+		case req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/members"):
+			w.Write([]byte(`[
+				{"userId": 2, "login": "alice"},
+				{"userId": 4, "login": "carol"}
+			]`))
+		default:
+			body, _ := ioutil.ReadAll(req.Body)
+			requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	client := grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second})
+	if err := syncTeamMembers(context.Background(), client, 7, []string{"alice", "bob"}); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(requests)
+	expected := []string{
+		`DELETE /api/teams/7/members/4 `,
+		`POST /api/teams/7/members {"userId":3}`,
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %q, got %q", expected, requests)
+	}
+
+	// Unknown users fail before the membership is changed
+	requests = nil
+	if err := syncTeamMembers(context.Background(), client, 7, []string{"dave"}); err == nil {
+		t.Error("expected an error for an unknown user")
+	}
+	if len(requests) > 0 {
+		t.Errorf("expected no requests, got %q", requests)
+	}
+}
+
+// Teams that exist already are only adopted with the adopt annotation
+func TestEnsureTeamAdoption(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			requests = append(requests, req.Method+" "+req.URL.Path)
+		}
+		w.Write([]byte(`{"teams": [{"id": 4, "name": "sre"}]}`))
+	}))
+	defer server.Close()
+
+	client := grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second})
+	r := &ReconcileGrafanaTeam{context: context.Background(), recorder: record.NewFakeRecorder(10)}
+	graf := &grafanav1alpha1.Grafana{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana"}}
+	cr := &grafanav1alpha1.GrafanaTeam{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "sre"},
+		Spec:       grafanav1alpha1.GrafanaTeamSpec{Email: "sre@example.com"},
+	}
+
+	if _, _, err := r.ensureTeam(cr, graf, client, nil); err == nil {
+		t.Error("expected an error for an existing team")
+	}
+	if len(requests) > 0 {
+		t.Errorf("expected no requests, got %v", requests)
+	}
+
+	// The team the status records was created by the operator
+	teamId, created, err := r.ensureTeam(cr, graf, client, &grafanav1alpha1.GrafanaInstanceStatus{ID: 4, Created: true})
+	if err != nil || teamId != 4 || !created {
+		t.Errorf("expected the created team, got %v, %v, %v", teamId, created, err)
+	}
+
+	cr.Annotations = map[string]string{grafanav1alpha1.AdoptAnnotation: "true"}
+	teamId, created, err = r.ensureTeam(cr, graf, client, nil)
+	if err != nil || teamId != 4 || created {
+		t.Errorf("expected the team to be adopted, got %v, %v, %v", teamId, created, err)
+	}
+}
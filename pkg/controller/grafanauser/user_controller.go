@@ -0,0 +1,182 @@
+package grafanauser
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
+)
+
+const (
+	ControllerName   = "controller_grafanauser"
+	userFinalizer    = "finalizer.grafanausers.monitor.kun"
+	passwordRefIndex = "spec.passwordRef.name"
+)
+
+var log = logf.Log.WithName(ControllerName)
+
+// Add creates a new GrafanaUser Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, _ chan schema.GroupVersionKind) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+
+	kubeclient := mgr.GetClient()
+	recorder := mgr.GetEventRecorderFor(ControllerName)
+	return &ReconcileGrafanaUser{
+		client:   kubeclient,
+		context:  ctx,
+		cancel:   cancel,
+		recorder: recorder,
+		resources: &common.SyncedResourceReconciler{
+			Client:    kubeclient,
+			Context:   ctx,
+			Recorder:  recorder,
+			Log:       log,
+			Kind:      metrics.KindGrafanaUser,
+			Finalizer: userFinalizer,
+		},
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("grafanauser-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource GrafanaUser
+	err = c.Watch(&source.Kind{Type: &grafanav1alpha1.GrafanaUser{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Users are created once a grafana selects them and is ready
+	kubeclient := mgr.GetClient()
+	err = c.Watch(&source.Kind{Type: &grafanav1alpha1.Grafana{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return mapGrafanaToUsers(kubeclient, o.Object.(*grafanav1alpha1.Grafana))
+		}),
+	}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			grafana := common.OwningGrafana(context.Background(), kubeclient, o.Meta)
+			if grafana == nil {
+				return nil
+			}
+			return mapGrafanaToUsers(kubeclient, grafana)
+		}),
+	}, common.GrafanaBecameReady)
+	if err != nil {
+		return err
+	}
+
+	// Passwords are set again when their secret changes
+	if err := mgr.GetFieldIndexer().IndexField(&grafanav1alpha1.GrafanaUser{}, passwordRefIndex, indexPasswordRef); err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &v1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return listUsers(kubeclient, o.Meta.GetNamespace(), client.MatchingField(passwordRefIndex, o.Meta.GetName()))
+		}),
+	})
+}
+
+func indexPasswordRef(o runtime.Object) []string {
+	user := o.(*grafanav1alpha1.GrafanaUser)
+	if user.Spec.PasswordRef == nil {
+		return nil
+	}
+	return []string{user.Spec.PasswordRef.Name}
+}
+
+func listUsers(c client.Client, namespace string, fields client.MatchingFields) []reconcile.Request {
+	users := &grafanav1alpha1.GrafanaUserList{}
+	if err := c.List(context.Background(), users, client.InNamespace(namespace), fields); err != nil {
+		log.Error(err, "error listing users")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(users.Items))
+	for _, user := range users.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: user.Namespace,
+			Name:      user.Name,
+		}})
+	}
+	return requests
+}
+
+// Returns a request for every user matching the user selectors of the grafana
+func mapGrafanaToUsers(c client.Client, grafana *grafanav1alpha1.Grafana) []reconcile.Request {
+	users := &grafanav1alpha1.GrafanaUserList{}
+	if err := c.List(context.Background(), users, client.InNamespace(grafana.Namespace)); err != nil {
+		log.Error(err, "error listing users")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, user := range users.Items {
+		match, err := common.MatchesSelectors(user.Labels, grafana.Spec.UserLabelSelector)
+		if err != nil || !match {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: user.Namespace,
+			Name:      user.Name,
+		}})
+	}
+	return requests
+}
+
+var _ reconcile.Reconciler = &ReconcileGrafanaUser{}
+
+// ReconcileGrafanaUser reconciles a GrafanaUser object
+type ReconcileGrafanaUser struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client    client.Client
+	context   context.Context
+	cancel    context.CancelFunc
+	recorder  record.EventRecorder
+	resources *common.SyncedResourceReconciler
+}
+
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileGrafanaUser) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	instance := &grafanav1alpha1.GrafanaUser{}
+	return r.resources.Reconcile(request, instance, func(reqLogger logr.Logger) error {
+		return r.reconcile(reqLogger, instance)
+	}, func(reqLogger logr.Logger) error {
+		return r.reconcileDelete(reqLogger, instance)
+	})
+}
@@ -0,0 +1,305 @@
+package grafanauser
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+)
+
+// Role of users in the default organization once their role there is no longer
+// listed
+const defaultRole = "Viewer"
+
+func (r *ReconcileGrafanaUser) reconcile(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaUser) error {
+	matchedGrafs, err := common.MatchGrafana(r.context, r.client, reqLogger, cr.Namespace, cr.Labels, common.MatchByUser)
+	if err != nil {
+		reqLogger.Error(err, "matchGrafana failed.")
+		return err
+	}
+
+	password, version, err := r.readPassword(cr)
+	if err != nil {
+		return err
+	}
+
+	instances := cr.Status.DeepCopy().Instances
+	var failed []string
+	for _, graf := range matchedGrafs {
+		reqLogger.V(3).Info("reconcile user for grafana", "grafanaName", graf.Name)
+		previous := grafanav1alpha1.FindInstanceStatus(instances, graf.Namespace, graf.Name)
+		userId, created, orgIds, err := r.reconcileUser(cr, graf, previous, password, version)
+		if err != nil {
+			reqLogger.Error(err, "cannot reconcile user", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
+			continue
+		}
+
+		// The last sync is the time the user was created or its password was set
+		status := grafanav1alpha1.GrafanaInstanceStatus{
+			Namespace:       graf.Namespace,
+			Name:            graf.Name,
+			ID:              userId,
+			PasswordVersion: version,
+			OrgIDs:          orgIds,
+			Created:         created,
+		}
+		if previous != nil && previous.ID == userId && previous.PasswordVersion == version {
+			status.LastSync = previous.LastSync
+		} else {
+			now := metav1.Now()
+			status.LastSync = &now
+		}
+		grafanav1alpha1.SetInstanceStatus(&instances, status)
+	}
+
+	cr.Status.Instances = instances
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot reconcile user in grafana %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Creates the user if it does not exist yet, renames it if the login in the spec
+// changed and updates its profile, password, permissions and organization roles.
+// Users that exist already are only adopted with the adopt annotation. Returns
+// the id of the user, whether the operator created it and the organizations it
+// was granted a role in
+func (r *ReconcileGrafanaUser) reconcileUser(cr *grafanav1alpha1.GrafanaUser, graf *grafanav1alpha1.Grafana, previous *grafanav1alpha1.GrafanaInstanceStatus, password, version string) (uint, bool, []uint, error) {
+	client, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
+	if err != nil {
+		return 0, false, nil, err
+	}
+
+	login := cr.UserLogin()
+	admin, err := common.AdminLogin(r.context, r.client, graf)
+	if err != nil {
+		return 0, false, nil, err
+	}
+	if login == admin {
+		return 0, false, nil, fmt.Errorf("user %v is the admin user of the operator and can't be managed", login)
+	}
+
+	request := grafanaClient.GrafanaUserRequest{
+		Login:    login,
+		Email:    cr.Spec.Email,
+		Name:     cr.Spec.Name,
+		Password: password,
+	}
+
+	user, err := client.GetUserByLogin(r.context, login)
+	var userId uint
+	created := previous != nil && previous.Created
+	switch {
+	case err == nil:
+		userId = user.ID
+		if previous == nil || previous.ID != userId {
+			if !grafanav1alpha1.Adopts(cr) {
+				return 0, false, nil, fmt.Errorf("user %v already exists in grafana %v and was not created by the operator, set the %v annotation to adopt it", login, graf.Name, grafanav1alpha1.AdoptAnnotation)
+			}
+			created = false
+			r.recorder.Eventf(cr, "Normal", "Adopted", "existing user %v adopted in grafana %v", login, graf.Name)
+		}
+		if user.Email != request.Email || user.Name != request.Name {
+			if err := client.UpdateUser(r.context, userId, request); err != nil {
+				return 0, false, nil, err
+			}
+		}
+		if previous == nil || previous.ID != userId || previous.PasswordVersion != version {
+			if err := client.UpdateUserPassword(r.context, userId, password); err != nil {
+				return 0, false, nil, err
+			}
+		}
+	case err == grafanaClient.NotFoundError && previous != nil && previous.ID > 0:
+		userId = previous.ID
+		err = client.UpdateUser(r.context, userId, request)
+		if err == grafanaClient.NotFoundError {
+			// Deleted outside of the operator
+			if userId, err = r.createUser(cr, graf, client, request); err != nil {
+				return 0, false, nil, err
+			}
+			created = true
+			break
+		}
+		if err != nil {
+			return 0, false, nil, err
+		}
+		if previous.PasswordVersion != version {
+			if err := client.UpdateUserPassword(r.context, userId, password); err != nil {
+				return 0, false, nil, err
+			}
+		}
+		r.recorder.Eventf(cr, "Normal", "Renamed", "user renamed to %v in grafana %v", login, graf.Name)
+	case err == grafanaClient.NotFoundError:
+		if userId, err = r.createUser(cr, graf, client, request); err != nil {
+			return 0, false, nil, err
+		}
+		created = true
+	default:
+		return 0, false, nil, err
+	}
+
+	if user.IsGrafanaAdmin != cr.Spec.GrafanaAdmin {
+		if err := client.UpdateUserPermissions(r.context, userId, cr.Spec.GrafanaAdmin); err != nil {
+			return 0, false, nil, err
+		}
+	}
+
+	var granted []uint
+	for _, organization := range cr.Spec.Organizations {
+		orgId, err := r.updateRole(client, userId, login, organization)
+		if err != nil {
+			return 0, false, nil, err
+		}
+		granted = append(granted, orgId)
+	}
+	sort.Slice(granted, func(i, j int) bool { return granted[i] < granted[j] })
+
+	if previous != nil && previous.ID == userId {
+		if err := revokeRoles(r.context, client, userId, previous.OrgIDs, granted); err != nil {
+			return 0, false, nil, err
+		}
+	}
+	return userId, created, granted, nil
+}
+
+func (r *ReconcileGrafanaUser) createUser(cr *grafanav1alpha1.GrafanaUser, graf *grafanav1alpha1.Grafana, client grafanaClient.GrafanaClient, request grafanaClient.GrafanaUserRequest) (uint, error) {
+	userId, err := client.CreateUser(r.context, request)
+	if err == grafanaClient.ConflictError {
+		return 0, fmt.Errorf("login %v or email %v is already taken", request.Login, request.Email)
+	}
+	if err != nil {
+		return 0, err
+	}
+	r.recorder.Eventf(cr, "Normal", "Created", "user %v created in grafana %v", request.Login, graf.Name)
+	return userId, nil
+}
+
+// Adds the user to the organization or changes its role. Returns the id of the
+// organization
+func (r *ReconcileGrafanaUser) updateRole(client grafanaClient.GrafanaClient, userId uint, login string, organization grafanav1alpha1.GrafanaUserOrganization) (uint, error) {
+	var orgId uint = grafanaClient.DefaultOrganizationId
+	if organization.Name != "" {
+		org, err := client.GetOrganizationByName(r.context, organization.Name)
+		if err != nil {
+			if err == grafanaClient.NotFoundError {
+				return 0, &common.OrganizationNotFoundError{Organization: organization.Name}
+			}
+			return 0, err
+		}
+		orgId = org.ID
+	}
+
+	users, err := client.ListOrganizationUsers(r.context, orgId)
+	if err != nil {
+		return 0, err
+	}
+	for _, user := range users {
+		if user.UserID != userId {
+			continue
+		}
+		if user.Role == organization.Role {
+			return orgId, nil
+		}
+		return orgId, client.UpdateOrganizationUser(r.context, orgId, userId, organization.Role)
+	}
+	return orgId, client.AddOrganizationUser(r.context, orgId, login, organization.Role)
+}
+
+// Removes the user from the organizations it was granted a role in that are no
+// longer listed. Every user is a member of the default organization, its role
+// there is reset to Viewer instead. Grafana deletes users that are removed from
+// their last organization
+func revokeRoles(ctx context.Context, client grafanaClient.GrafanaClient, userId uint, previous, granted []uint) error {
+	for _, orgId := range previous {
+		if containsOrg(granted, orgId) {
+			continue
+		}
+
+		var err error
+		if orgId == grafanaClient.DefaultOrganizationId {
+			err = client.UpdateOrganizationUser(ctx, orgId, userId, defaultRole)
+		} else {
+			err = client.RemoveOrganizationUser(ctx, orgId, userId)
+		}
+		if err != nil && err != grafanaClient.NotFoundError {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsOrg(orgIds []uint, orgId uint) bool {
+	for _, id := range orgIds {
+		if id == orgId {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the password and its version, the resource version of the secret and
+// the key. The password is set again whenever the secret changes
+func (r *ReconcileGrafanaUser) readPassword(cr *grafanav1alpha1.GrafanaUser) (string, string, error) {
+	ref := cr.Spec.PasswordRef
+	if ref == nil {
+		return "", "", fmt.Errorf("no password secret given")
+	}
+
+	secret := &v1.Secret{}
+	key := client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      ref.Name,
+	}
+	if err := r.client.Get(r.context, key, secret); err != nil {
+		return "", "", fmt.Errorf("error reading secret %v: %v", ref.Name, err)
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok || len(value) == 0 {
+		return "", "", fmt.Errorf("key %v not found in secret %v", ref.Key, ref.Name)
+	}
+	return string(value), fmt.Sprintf("%v/%v", secret.ResourceVersion, ref.Key), nil
+}
+
+// Deletes the users the operator created from every matching grafana, by the id
+// recorded in the status. Adopted users are kept
+func (r *ReconcileGrafanaUser) reconcileDelete(reqLogger logr.Logger, cr *grafanav1alpha1.GrafanaUser) error {
+	matchedGrafs, err := common.MatchGrafana(r.context, r.client, reqLogger, cr.Namespace, cr.Labels, common.MatchByUser)
+	if err != nil {
+		reqLogger.Error(err, "matchGrafana failed.")
+		return err
+	}
+
+	var failed []string
+	for _, graf := range matchedGrafs {
+		instance := grafanav1alpha1.FindInstanceStatus(cr.Status.Instances, graf.Namespace, graf.Name)
+		if instance == nil || instance.ID == 0 || !instance.Created {
+			continue
+		}
+
+		reqLogger.V(3).Info("delete user from grafana", "grafanaName", graf.Name)
+		client, err := common.NewReadyGrafanaClient(r.context, r.client, graf)
+		if err == nil {
+			err = client.DeleteUser(r.context, instance.ID)
+		}
+		if err != nil && err != grafanaClient.NotFoundError {
+			reqLogger.Error(err, "cannot delete user", "grafana", graf.Name)
+			failed = append(failed, graf.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot delete user from grafana %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
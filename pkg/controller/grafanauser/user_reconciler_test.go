@@ -0,0 +1,272 @@
+package grafanauser
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	grafanav1alpha1 "github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/common"
+	grafanaClient "github.com/ucloud/grafana-operator/pkg/controller/grafanaclient"
+	"github.com/ucloud/grafana-operator/pkg/controller/metrics"
+	"github.com/ucloud/grafana-operator/pkg/controller/model"
+)
+
+// Returns a grafana instance selecting the users labeled app=grafana and its
+// service and admin secret, grafana is reached at the server url
+func newTestGrafana(t *testing.T, serverUrl string) (*grafanav1alpha1.Grafana, []runtime.Object) {
+	parsed, err := url.Parse(serverUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graf := &grafanav1alpha1.Grafana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "grafana"},
+		Spec: grafanav1alpha1.GrafanaSpec{
+			Config: grafanav1alpha1.GrafanaConfig{
+				Server: &grafanav1alpha1.GrafanaConfigServer{HttpPort: parsed.Port()},
+			},
+			UserLabelSelector: []*metav1.LabelSelector{{MatchLabels: map[string]string{"app": "grafana"}}},
+		},
+	}
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: graf.Namespace, Name: model.GrafanaServiceSelector(graf).Name},
+		Spec:       v1.ServiceSpec{ClusterIP: parsed.Hostname()},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: graf.Namespace, Name: model.AdminSecretSelector(graf).Name},
+		Data: map[string][]byte{
+			model.GrafanaAdminUserEnvVar:     []byte("admin"),
+			model.GrafanaAdminPasswordEnvVar: []byte("admin"),
+		},
+	}
+	return graf, []runtime.Object{graf, service, secret}
+}
+
+func newTestReconciler(t *testing.T, objects ...runtime.Object) *ReconcileGrafanaUser {
+	s := runtime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := grafanav1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	kubeclient := fake.NewFakeClientWithScheme(s, objects...)
+	recorder := record.NewFakeRecorder(10)
+	return &ReconcileGrafanaUser{
+		client:   kubeclient,
+		context:  context.Background(),
+		recorder: recorder,
+		resources: &common.SyncedResourceReconciler{
+			Client:    kubeclient,
+			Context:   context.Background(),
+			Recorder:  recorder,
+			Log:       log,
+			Kind:      metrics.KindGrafanaUser,
+			Finalizer: userFinalizer,
+		},
+	}
+}
+
+func TestReconcileUser(t *testing.T) {
+	created := false
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			body, _ := ioutil.ReadAll(req.Body)
+			requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+		}
+		switch {
+		case req.URL.Path == "/api/users/lookup" && !created:
+			w.WriteHeader(http.StatusNotFound)
+		case req.URL.Path == "/api/users/lookup":
+			w.Write([]byte(`{"id": 7, "login": "alice"}`))
+		case req.Method == http.MethodPost && req.URL.Path == "/api/admin/users":
+			created = true
+			w.Write([]byte(`{"id": 7}`))
+		case req.URL.Path == "/api/orgs/1/users":
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	graf, objects := newTestGrafana(t, server.URL)
+	password := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: graf.Namespace, Name: "alice", ResourceVersion: "3"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+	user := &grafanav1alpha1.GrafanaUser{
+		ObjectMeta: metav1.ObjectMeta{Namespace: graf.Namespace, Name: "alice", Labels: map[string]string{"app": "grafana"}},
+		Spec: grafanav1alpha1.GrafanaUserSpec{
+			Email: "alice@example.com",
+			PasswordRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "alice"},
+				Key:                  "password",
+			},
+			Organizations: []grafanav1alpha1.GrafanaUserOrganization{{Role: "Editor"}},
+		},
+	}
+	r := newTestReconciler(t, append(objects, password, user)...)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: user.Namespace, Name: user.Name}}
+
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.client.Get(r.context, request.NamespacedName, user); err != nil {
+		t.Fatal(err)
+	}
+	instance := grafanav1alpha1.FindInstanceStatus(user.Status.Instances, graf.Namespace, graf.Name)
+	if user.Status.Phase != grafanav1alpha1.PhaseReconciling || instance == nil || instance.ID != 7 || !instance.Created || !reflect.DeepEqual(instance.OrgIDs, []uint{1}) {
+		t.Fatalf("expected the user to be created, got status %+v", user.Status)
+	}
+	if !reflect.DeepEqual(user.Finalizers, []string{userFinalizer}) {
+		t.Errorf("expected the finalizer to be added, got %v", user.Finalizers)
+	}
+
+	// The user is deleted before the finalizer is removed
+	now := metav1.Now()
+	user.DeletionTimestamp = &now
+	if err := r.client.Update(r.context, user); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatal(err)
+	}
+	deleted := &grafanav1alpha1.GrafanaUser{}
+	if err := r.client.Get(r.context, request.NamespacedName, deleted); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted.Finalizers) > 0 {
+		t.Errorf("expected the finalizer to be removed, got %v", deleted.Finalizers)
+	}
+
+	expected := []string{
+		`POST /api/admin/users {"login":"alice","email":"alice@example.com","name":"","password":"secret"}`,
+		`POST /api/orgs/1/users {"loginOrEmail":"alice","role":"Editor"}`,
+		"DELETE /api/admin/users/7 ",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+}
+
+// Users that exist already are only adopted with the adopt annotation and never
+// deleted by the operator
+func TestReconcileUserAdoption(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			requests = append(requests, req.Method+" "+req.URL.Path)
+		}
+		switch req.URL.Path {
+		case "/api/users/lookup":
+			w.Write([]byte(`{"id": 3, "login": "bob", "email": "bob@example.com"}`))
+		case "/api/orgs/1/users":
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	graf, objects := newTestGrafana(t, server.URL)
+	password := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: graf.Namespace, Name: "bob", ResourceVersion: "3"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+	user := &grafanav1alpha1.GrafanaUser{
+		ObjectMeta: metav1.ObjectMeta{Namespace: graf.Namespace, Name: "bob", Labels: map[string]string{"app": "grafana"}},
+		Spec: grafanav1alpha1.GrafanaUserSpec{
+			Email: "bob@example.com",
+			PasswordRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "bob"},
+				Key:                  "password",
+			},
+		},
+	}
+	r := newTestReconciler(t, append(objects, password, user)...)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: user.Namespace, Name: user.Name}}
+
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.client.Get(r.context, request.NamespacedName, user); err != nil {
+		t.Fatal(err)
+	}
+	if user.Status.Phase != grafanav1alpha1.PhaseFailing || len(user.Status.Instances) > 0 || len(requests) > 0 {
+		t.Fatalf("expected the existing user not to be adopted, got status %+v and requests %v", user.Status, requests)
+	}
+
+	user.Annotations = map[string]string{grafanav1alpha1.AdoptAnnotation: "true"}
+	if err := r.client.Update(r.context, user); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.client.Get(r.context, request.NamespacedName, user); err != nil {
+		t.Fatal(err)
+	}
+	instance := grafanav1alpha1.FindInstanceStatus(user.Status.Instances, graf.Namespace, graf.Name)
+	if instance == nil || instance.ID != 3 || instance.Created {
+		t.Fatalf("expected the user to be adopted, got status %+v", user.Status)
+	}
+
+	now := metav1.Now()
+	user.DeletionTimestamp = &now
+	if err := r.client.Update(r.context, user); err != nil {
+		t.Fatal(err)
+	}
+	requests = nil
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) > 0 {
+		t.Errorf("expected the adopted user to be kept, got requests %v", requests)
+	}
+}
+
+func TestRevokeRoles(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+		if req.URL.Path == "/api/orgs/4/users/7" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := grafanaClient.NewGrafanaClient(server.URL, "admin", "admin", grafanaClient.ClientOptions{Timeout: time.Second})
+	if err := revokeRoles(context.Background(), client, 7, []uint{1, 2, 3, 4}, []uint{2}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`PATCH /api/orgs/1/users/7 {"role":"Viewer"}`,
+		"DELETE /api/orgs/3/users/7 ",
+		"DELETE /api/orgs/4/users/7 ",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+}
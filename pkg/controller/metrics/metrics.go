@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "grafana_operator"
+
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+
+	KindGrafana               = "Grafana"
+	KindGrafanaDashboard      = "GrafanaDashboard"
+	KindGrafanaDataSource     = "GrafanaDataSource"
+	KindGrafanaOrganization   = "GrafanaOrganization"
+	KindGrafanaServiceAccount = "GrafanaServiceAccount"
+	KindGrafanaTeam           = "GrafanaTeam"
+	KindGrafanaUser           = "GrafanaUser"
+)
+
+var (
+	// DashboardSyncs counts the attempts to submit a dashboard to a Grafana instance
+	DashboardSyncs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "dashboard_syncs_total",
+		Help:      "Number of dashboard submissions per Grafana instance, partitioned by result",
+	}, []string{"namespace", "grafana", "result"})
+
+	// DatasourceSyncs counts the attempts to submit a datasource to a Grafana instance
+	DatasourceSyncs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "datasource_syncs_total",
+		Help:      "Number of datasource submissions per Grafana instance, partitioned by result",
+	}, []string{"namespace", "grafana", "result"})
+
+	// ApiRequestDuration tracks the latency and status codes of the Grafana API
+	ApiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "api_request_duration_seconds",
+		Help:      "Latency of requests sent to the Grafana API, partitioned by method, endpoint and status code",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "endpoint", "code"})
+
+	// PluginValidationFailures counts plugins that could not be found in the plugin registry
+	PluginValidationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "plugin_validation_failures_total",
+		Help:      "Number of requested plugins that failed validation against the plugin registry",
+	}, []string{"namespace", "grafana", "plugin"})
+
+	// JsonnetEvaluationDuration tracks how long it takes to compile jsonnet dashboards
+	JsonnetEvaluationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "jsonnet_evaluation_duration_seconds",
+		Help:      "Time spent evaluating jsonnet dashboards",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"namespace"})
+
+	// JsonnetEvaluationErrors counts jsonnet dashboards that failed to evaluate
+	JsonnetEvaluationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "jsonnet_evaluation_errors_total",
+		Help:      "Number of jsonnet evaluations that returned an error",
+	}, []string{"namespace"})
+
+	// LastSuccessfulSync records the unix time of the last successful reconciliation
+	// of a custom resource. Use `time() - grafana_operator_last_successful_sync_timestamp_seconds`
+	// to get the time since the last successful sync
+	LastSuccessfulSync = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "last_successful_sync_timestamp_seconds",
+		Help:      "Unix timestamp of the last successful reconciliation per custom resource",
+	}, []string{"kind", "namespace", "name"})
+)
+
+func init() {
+	// Register the custom metrics with the controller-runtime registry so that
+	// they are exported on the operator metrics port
+	metrics.Registry.MustRegister(
+		DashboardSyncs,
+		DatasourceSyncs,
+		ApiRequestDuration,
+		PluginValidationFailures,
+		JsonnetEvaluationDuration,
+		JsonnetEvaluationErrors,
+		LastSuccessfulSync,
+	)
+}
+
+// Result maps an error to the value of the result label
+func Result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
+
+func ObserveApiRequest(method, endpoint, code string, duration time.Duration) {
+	ApiRequestDuration.WithLabelValues(method, endpoint, code).Observe(duration.Seconds())
+}
+
+func ObserveJsonnetEvaluation(namespace string, duration time.Duration, err error) {
+	JsonnetEvaluationDuration.WithLabelValues(namespace).Observe(duration.Seconds())
+	if err != nil {
+		JsonnetEvaluationErrors.WithLabelValues(namespace).Inc()
+	}
+}
+
+func SetLastSuccessfulSync(kind, namespace, name string) {
+	LastSuccessfulSync.WithLabelValues(kind, namespace, name).SetToCurrentTime()
+}
+
+// ForgetResource removes all per resource series once the resource has been deleted
+func ForgetResource(kind, namespace, name string) {
+	LastSuccessfulSync.DeleteLabelValues(kind, namespace, name)
+}
@@ -20,22 +20,55 @@ func getAdminUser(cr *v1alpha1.Grafana, current *v12.Secret) []byte {
 	return []byte(cr.Spec.Config.Security.AdminUser)
 }
 
+// The password of an existing secret is only changed by a rotation after grafana
+// accepted the new password, grafana ignores the password of the secret once its
+// database is initialized
 func getAdminPassword(cr *v1alpha1.Grafana, current *v12.Secret) []byte {
+	if current != nil && current.Data[GrafanaAdminPasswordEnvVar] != nil {
+		return current.Data[GrafanaAdminPasswordEnvVar]
+	}
 	if cr.Spec.Config.Security == nil || cr.Spec.Config.Security.AdminPassword == "" {
-		// If a password is already set, don't change it
-		if current != nil && current.Data[GrafanaAdminPasswordEnvVar] != nil {
-			return current.Data[GrafanaAdminPasswordEnvVar]
-		}
-		return []byte(RandStringRunes(10))
+		return []byte(RandStringRunes(GeneratedAdminPasswordBytes))
 	}
 	return []byte(cr.Spec.Config.Security.AdminPassword)
 }
 
 func getData(cr *v1alpha1.Grafana, current *v12.Secret) map[string][]byte {
-	return map[string][]byte{
+	data := map[string][]byte{
 		GrafanaAdminUserEnvVar:     getAdminUser(cr, current),
 		GrafanaAdminPasswordEnvVar: getAdminPassword(cr, current),
 	}
+	// Keep the password of an unfinished rotation
+	if current != nil && current.Data[GrafanaAdminPendingPasswordKey] != nil {
+		data[GrafanaAdminPendingPasswordKey] = current.Data[GrafanaAdminPendingPasswordKey]
+	}
+	return data
+}
+
+// AdminPasswordRotation returns the new password of the admin user, or an empty
+// string if no rotation is due. A rotation is due if the password in the spec
+// differs from the password in the secret, or if the rotate annotation changed
+// and the password is generated. An unfinished rotation is resumed with its
+// pending password
+func AdminPasswordRotation(cr *v1alpha1.Grafana, current *v12.Secret) string {
+	if current == nil || current.Data[GrafanaAdminPasswordEnvVar] == nil {
+		return ""
+	}
+	if pending := current.Data[GrafanaAdminPendingPasswordKey]; len(pending) > 0 {
+		return string(pending)
+	}
+
+	if cr.Spec.Config.Security != nil && cr.Spec.Config.Security.AdminPassword != "" {
+		if cr.Spec.Config.Security.AdminPassword != string(current.Data[GrafanaAdminPasswordEnvVar]) {
+			return cr.Spec.Config.Security.AdminPassword
+		}
+		return ""
+	}
+
+	if cr.Annotations[v1alpha1.RotateAdminPasswordAnnotation] != cr.Status.AdminPasswordRotation {
+		return RandStringRunes(GeneratedAdminPasswordBytes)
+	}
+	return ""
 }
 
 func AdminSecret(cr *v1alpha1.Grafana) *v12.Secret {
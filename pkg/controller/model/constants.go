@@ -1,29 +1,47 @@
 package model
 
 const (
-	GrafanaImage                    = "grafana/grafana"
-	GrafanaVersion                  = "7.1.1"
-	grafanaServiceAccountName       = "grafana-serviceaccount"
-	grafanaServiceName              = "grafana-service"
-	grafanaDataStorageName          = "grafana-pvc"
-	grafanaConfigName               = "grafana-config"
-	grafanaConfigFileName           = "grafana.ini"
-	grafanaIngressName              = "grafana-ingress"
-	grafanaRouteName                = "grafana-route"
-	grafanaDeploymentName           = "grafana-deployment"
-	GrafanaPluginsVolumeName        = "grafana-plugins"
-	GrafanaInitContainerName        = "grafana-plugins-init"
-	GrafanaLogsVolumeName           = "grafana-logs"
-	GrafanaDataVolumeName           = "grafana-data"
-	GrafanaHealthEndpoint           = "/api/health"
-	GrafanaPodLabel                 = "grafana"
-	LastConfigAnnotation            = "last-config"
-	LastConfigEnvVar                = "LAST_CONFIG"
-	LastDatasourcesConfigEnvVar     = "LAST_DATASOURCES"
-	grafanaAdminSecretName          = "grafana-admin-credentials"
-	DefaultAdminUser                = "admin"
-	GrafanaAdminUserEnvVar          = "GF_SECURITY_ADMIN_USER"
-	GrafanaAdminPasswordEnvVar      = "GF_SECURITY_ADMIN_PASSWORD"
-	GrafanaHttpPort             int = 3000
-	GrafanaHttpPortName             = "grafana"
+	GrafanaImage                         = "grafana/grafana"
+	GrafanaVersion                       = "7.1.1"
+	grafanaServiceAccountName            = "grafana-serviceaccount"
+	grafanaServiceName                   = "grafana-service"
+	grafanaDataStorageName               = "grafana-pvc"
+	grafanaConfigName                    = "grafana-config"
+	grafanaConfigFileName                = "grafana.ini"
+	grafanaIngressName                   = "grafana-ingress"
+	grafanaRouteName                     = "grafana-route"
+	grafanaDeploymentName                = "grafana-deployment"
+	GrafanaPluginsVolumeName             = "grafana-plugins"
+	GrafanaInitContainerName             = "grafana-plugins-init"
+	GrafanaLogsVolumeName                = "grafana-logs"
+	GrafanaDataVolumeName                = "grafana-data"
+	GrafanaHealthEndpoint                = "/api/health"
+	GrafanaPodLabel                      = "grafana"
+	LastConfigAnnotation                 = "last-config"
+	LastConfigEnvVar                     = "LAST_CONFIG"
+	LastDatasourcesConfigEnvVar          = "LAST_DATASOURCES"
+	LastSecretsConfigEnvVar              = "LAST_SECRETS"
+	grafanaAdminSecretName               = "grafana-admin-credentials"
+	DefaultAdminUser                     = "admin"
+	GrafanaAdminUserEnvVar               = "GF_SECURITY_ADMIN_USER"
+	GrafanaAdminPasswordEnvVar           = "GF_SECURITY_ADMIN_PASSWORD"
+	GrafanaAdminPendingPasswordKey       = "PENDING_ADMIN_PASSWORD"
+	GeneratedAdminPasswordBytes      int = 32
+	GrafanaHttpPort                  int = 3000
+	GrafanaHttpPortName                  = "grafana"
+	grafanaNetworkPolicyName             = "grafana-network-policy"
+	NamespaceNameLabel                   = "kubernetes.io/metadata.name"
+	OpenshiftIngressPolicyGroupLabel     = "network.openshift.io/policy-group"
+	NetworkPolicyDnsPort             int = 53
+	NetworkPolicyHttpsPort           int = 443
+	ImageRendererImage                   = "grafana/grafana-image-renderer"
+	ImageRendererVersion                 = "2.0.0"
+	ImageRendererPodLabel                = "grafana-image-renderer"
+	ImageRendererPort                int = 8081
+	ImageRendererPortName                = "renderer-http"
+	ImageRendererTokenKey                = "GF_RENDERING_RENDERER_TOKEN"
+	ImageRendererAuthTokenEnvVar         = "AUTH_TOKEN"
+	imageRendererDeploymentName          = "grafana-image-renderer-deployment"
+	imageRendererServiceName             = "grafana-image-renderer-service"
+	imageRendererSecretName              = "grafana-image-renderer-token"
 )
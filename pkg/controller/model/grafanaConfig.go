@@ -10,9 +10,32 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Returns the grafana config with the settings managed by the operator applied.
+// Works on a copy to not modify the spec of the custom resource
+func getGrafanaConfig(cr *v1alpha1.Grafana) *v1alpha1.GrafanaConfig {
+	cfg := cr.Spec.Config.DeepCopy()
+
+	if ImageRendererEnabled(cr) {
+		if cfg.Rendering == nil {
+			cfg.Rendering = &v1alpha1.GrafanaConfigRendering{}
+		}
+		if cfg.Rendering.ServerUrl == "" {
+			cfg.Rendering.ServerUrl = GetImageRendererServerUrl(cr)
+		}
+		if cfg.Rendering.CallbackUrl == "" {
+			cfg.Rendering.CallbackUrl = GetImageRendererCallbackUrl(cr)
+		}
+	}
+
+	return cfg
+}
+
 func GrafanaConfig(cr *v1alpha1.Grafana) (*v1.ConfigMap, error) {
-	ini := config.NewGrafanaIni(&cr.Spec.Config)
-	config, hash := ini.Write()
+	ini := config.NewGrafanaIni(getGrafanaConfig(cr))
+	config, hash, err := ini.Write()
+	if err != nil {
+		return nil, err
+	}
 
 	configMap := &v1.ConfigMap{}
 	configMap.ObjectMeta = v12.ObjectMeta{
@@ -23,7 +46,7 @@ func GrafanaConfig(cr *v1alpha1.Grafana) (*v1.ConfigMap, error) {
 	// Store the hash of the current configuration for later
 	// comparisons
 	configMap.Annotations = map[string]string{
-		"lastConfig": hash,
+		LastConfigAnnotation: hash,
 	}
 
 	configMap.Data = map[string]string{}
@@ -34,8 +57,11 @@ func GrafanaConfig(cr *v1alpha1.Grafana) (*v1.ConfigMap, error) {
 func GrafanaConfigReconciled(cr *v1alpha1.Grafana, currentState *v1.ConfigMap) (*v1.ConfigMap, error) {
 	reconciled := currentState.DeepCopy()
 
-	ini := config.NewGrafanaIni(&cr.Spec.Config)
-	config, hash := ini.Write()
+	ini := config.NewGrafanaIni(getGrafanaConfig(cr))
+	config, hash, err := ini.Write()
+	if err != nil {
+		return nil, err
+	}
 
 	reconciled.Annotations = map[string]string{
 		LastConfigAnnotation: hash,
@@ -45,6 +71,11 @@ func GrafanaConfigReconciled(cr *v1alpha1.Grafana, currentState *v1.ConfigMap) (
 	return reconciled, nil
 }
 
+// GetConfigSecretRefs returns the config keys that are read from secrets
+func GetConfigSecretRefs(cr *v1alpha1.Grafana) []config.GrafanaIniSecretRef {
+	return config.NewGrafanaIni(&cr.Spec.Config).SecretRefs()
+}
+
 func GrafanaConfigSelector(cr *v1alpha1.Grafana) client.ObjectKey {
 	return client.ObjectKey{
 		Namespace: cr.Namespace,
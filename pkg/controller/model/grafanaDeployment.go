@@ -314,7 +314,67 @@ func getProbe(cr *v1alpha1.Grafana, delay, timeout, failure int32) *v13.Probe {
 	}
 }
 
-func getContainers(cr *v1alpha1.Grafana, configHash, dsHash string) []v13.Container {
+func getEnv(cr *v1alpha1.Grafana, configHash, dsHash, secretsHash string) []v13.EnvVar {
+	env := []v13.EnvVar{
+		{
+			Name:  LastConfigEnvVar,
+			Value: configHash,
+		},
+		{
+			Name:  LastDatasourcesConfigEnvVar,
+			Value: dsHash,
+		},
+		{
+			Name: GrafanaAdminUserEnvVar,
+			ValueFrom: &v13.EnvVarSource{
+				SecretKeyRef: &v13.SecretKeySelector{
+					LocalObjectReference: v13.LocalObjectReference{
+						Name: getGrafanaAdminSecretName(cr),
+					},
+					Key: GrafanaAdminUserEnvVar,
+				},
+			},
+		},
+		{
+			Name: GrafanaAdminPasswordEnvVar,
+			ValueFrom: &v13.EnvVarSource{
+				SecretKeyRef: &v13.SecretKeySelector{
+					LocalObjectReference: v13.LocalObjectReference{
+						Name: getGrafanaAdminSecretName(cr),
+					},
+					Key: GrafanaAdminPasswordEnvVar,
+				},
+			},
+		},
+	}
+
+	// Inject the config values that are read from secrets. The hash of the secrets
+	// is only set when there are any, adding it would roll every deployment
+	refs := GetConfigSecretRefs(cr)
+	if len(refs) > 0 {
+		env = append(env, v13.EnvVar{
+			Name:  LastSecretsConfigEnvVar,
+			Value: secretsHash,
+		})
+	}
+	for _, ref := range refs {
+		env = append(env, v13.EnvVar{
+			Name: ref.EnvVarName(),
+			ValueFrom: &v13.EnvVarSource{
+				SecretKeyRef: ref.Ref,
+			},
+		})
+	}
+
+	// Share the generated token with the image renderer
+	if ImageRendererEnabled(cr) {
+		env = append(env, getImageRendererTokenEnvVar(cr, ImageRendererTokenKey))
+	}
+
+	return env
+}
+
+func getContainers(cr *v1alpha1.Grafana, configHash, dsHash, secretsHash string) []v13.Container {
 	var containers []v13.Container
 
 	cfg := config.GetControllerConfig()
@@ -333,38 +393,7 @@ func getContainers(cr *v1alpha1.Grafana, configHash, dsHash string) []v13.Contai
 				Protocol:      "TCP",
 			},
 		},
-		Env: []v13.EnvVar{
-			{
-				Name:  LastConfigEnvVar,
-				Value: configHash,
-			},
-			{
-				Name:  LastDatasourcesConfigEnvVar,
-				Value: dsHash,
-			},
-			{
-				Name: GrafanaAdminUserEnvVar,
-				ValueFrom: &v13.EnvVarSource{
-					SecretKeyRef: &v13.SecretKeySelector{
-						LocalObjectReference: v13.LocalObjectReference{
-							Name: getGrafanaAdminSecretName(cr),
-						},
-						Key: GrafanaAdminUserEnvVar,
-					},
-				},
-			},
-			{
-				Name: GrafanaAdminPasswordEnvVar,
-				ValueFrom: &v13.EnvVarSource{
-					SecretKeyRef: &v13.SecretKeySelector{
-						LocalObjectReference: v13.LocalObjectReference{
-							Name: getGrafanaAdminSecretName(cr),
-						},
-						Key: GrafanaAdminPasswordEnvVar,
-					},
-				},
-			},
-		},
+		Env:                      getEnv(cr, configHash, dsHash, secretsHash),
 		Resources:                getResources(cr),
 		VolumeMounts:             getVolumeMounts(cr),
 		LivenessProbe:            getProbe(cr, 60, 30, 10),
@@ -414,7 +443,7 @@ func getInitContainers(cr *v1alpha1.Grafana, plugins string) []v13.Container {
 	}
 }
 
-func getDeploymentSpec(cr *v1alpha1.Grafana, annotations map[string]string, configHash, plugins, dsHash string) v1.DeploymentSpec {
+func getDeploymentSpec(cr *v1alpha1.Grafana, annotations map[string]string, configHash, plugins, dsHash, secretsHash string) v1.DeploymentSpec {
 	return v1.DeploymentSpec{
 		Replicas: getReplicas(cr),
 		Selector: &v12.LabelSelector{
@@ -433,7 +462,7 @@ func getDeploymentSpec(cr *v1alpha1.Grafana, annotations map[string]string, conf
 				SecurityContext:               getSecurityContext(cr),
 				Volumes:                       getVolumes(cr),
 				InitContainers:                getInitContainers(cr, plugins),
-				Containers:                    getContainers(cr, configHash, dsHash),
+				Containers:                    getContainers(cr, configHash, dsHash, secretsHash),
 				ServiceAccountName:            getGrafanaServiceAccountName(cr),
 				TerminationGracePeriodSeconds: getTerminationGracePeriod(cr),
 			},
@@ -445,13 +474,13 @@ func getDeploymentSpec(cr *v1alpha1.Grafana, annotations map[string]string, conf
 	}
 }
 
-func GrafanaDeployment(cr *v1alpha1.Grafana, configHash, dsHash string) *v1.Deployment {
+func GrafanaDeployment(cr *v1alpha1.Grafana, configHash, dsHash, secretsHash string) *v1.Deployment {
 	return &v1.Deployment{
 		ObjectMeta: v12.ObjectMeta{
 			Name:      GetGrafanaDeploymentName(cr),
 			Namespace: cr.Namespace,
 		},
-		Spec: getDeploymentSpec(cr, nil, configHash, "", dsHash),
+		Spec: getDeploymentSpec(cr, nil, configHash, "", dsHash, secretsHash),
 	}
 }
 
@@ -462,13 +491,14 @@ func GrafanaDeploymentSelector(cr *v1alpha1.Grafana) client.ObjectKey {
 	}
 }
 
-func GrafanaDeploymentReconciled(cr *v1alpha1.Grafana, currentState *v1.Deployment, configHash, plugins, dshash string) *v1.Deployment {
+func GrafanaDeploymentReconciled(cr *v1alpha1.Grafana, currentState *v1.Deployment, configHash, plugins, dshash, secretsHash string) *v1.Deployment {
 	reconciled := currentState.DeepCopy()
 	reconciled.Spec = getDeploymentSpec(cr,
 		currentState.Spec.Template.Annotations,
 		configHash,
 		plugins,
-		dshash)
+		dshash,
+		secretsHash)
 	return reconciled
 }
 
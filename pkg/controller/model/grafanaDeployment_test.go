@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	v13 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func findEnv(env []v13.EnvVar, name string) *v13.EnvVar {
+	for i := range env {
+		if env[i].Name == name {
+			return &env[i]
+		}
+	}
+	return nil
+}
+
+func TestGetEnvSecretsHash(t *testing.T) {
+	cr := &v1alpha1.Grafana{
+		ObjectMeta: v12.ObjectMeta{Namespace: "monitoring", Name: "grafana"},
+	}
+
+	// Deployments without secret refs are not rolled by the secrets hash
+	if env := findEnv(getEnv(cr, "config", "datasources", "secrets"), LastSecretsConfigEnvVar); env != nil {
+		t.Errorf("expected no secrets hash without secret refs, got %+v", env)
+	}
+
+	cr.Spec.Config.Security = &v1alpha1.GrafanaConfigSecurity{
+		SecretKeyRef: &v13.SecretKeySelector{
+			LocalObjectReference: v13.LocalObjectReference{Name: "grafana-secrets"},
+			Key:                  "secret_key",
+		},
+	}
+	env := findEnv(getEnv(cr, "config", "datasources", "secrets"), LastSecretsConfigEnvVar)
+	if env == nil || env.Value != "secrets" {
+		t.Errorf("expected the secrets hash, got %+v", env)
+	}
+}
@@ -0,0 +1,280 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	v1 "k8s.io/api/apps/v1"
+	v13 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func ImageRendererEnabled(cr *v1alpha1.Grafana) bool {
+	return cr.Spec.ImageRenderer != nil && cr.Spec.ImageRenderer.Enabled
+}
+
+// The pod labels are used by the deployment and service selectors and must not
+// be affected by user provided labels
+func getImageRendererPodLabels(cr *v1alpha1.Grafana) map[string]string {
+	return map[string]string{
+		"app":     ImageRendererPodLabel,
+		"grafana": cr.Name,
+	}
+}
+
+func getImageRendererLabels(cr *v1alpha1.Grafana) map[string]string {
+	labels := getImageRendererPodLabels(cr)
+	if cr.Spec.ImageRenderer == nil {
+		return labels
+	}
+	for k, v := range cr.Spec.ImageRenderer.Labels {
+		if _, ok := labels[k]; !ok {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+func getImageRendererAnnotations(cr *v1alpha1.Grafana, existing map[string]string) map[string]string {
+	if cr.Spec.ImageRenderer == nil {
+		return existing
+	}
+	return MergeAnnotations(cr.Spec.ImageRenderer.Annotations, existing)
+}
+
+func getImageRendererImage(cr *v1alpha1.Grafana) string {
+	if cr.Spec.ImageRenderer == nil || cr.Spec.ImageRenderer.Image == "" {
+		return fmt.Sprintf("%s:%s", ImageRendererImage, ImageRendererVersion)
+	}
+	return cr.Spec.ImageRenderer.Image
+}
+
+func getImageRendererReplicas(cr *v1alpha1.Grafana) *int32 {
+	var replicas int32 = 1
+	if cr.Spec.ImageRenderer == nil || cr.Spec.ImageRenderer.Replicas <= 0 {
+		return &replicas
+	}
+	return &cr.Spec.ImageRenderer.Replicas
+}
+
+func getImageRendererResources(cr *v1alpha1.Grafana) v13.ResourceRequirements {
+	if cr.Spec.ImageRenderer == nil || cr.Spec.ImageRenderer.Resources == nil {
+		return v13.ResourceRequirements{}
+	}
+	return *cr.Spec.ImageRenderer.Resources
+}
+
+// GetImageRendererServerUrl returns the url Grafana uses to send render requests
+func GetImageRendererServerUrl(cr *v1alpha1.Grafana) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d/render",
+		getImageRendererServiceName(cr),
+		cr.Namespace,
+		ImageRendererPort)
+}
+
+// GetImageRendererCallbackUrl returns the url the renderer uses to load the panels from Grafana
+func GetImageRendererCallbackUrl(cr *v1alpha1.Grafana) string {
+	protocol := "http"
+	if cr.Spec.Config.Server != nil && cr.Spec.Config.Server.Protocol == "https" {
+		protocol = "https"
+	}
+
+	return fmt.Sprintf("%s://%s.%s.svc:%d/",
+		protocol,
+		getGrafanaServiceName(cr),
+		cr.Namespace,
+		GetGrafanaPort(cr))
+}
+
+func getImageRendererTokenEnvVar(cr *v1alpha1.Grafana, name string) v13.EnvVar {
+	return v13.EnvVar{
+		Name: name,
+		ValueFrom: &v13.EnvVarSource{
+			SecretKeyRef: &v13.SecretKeySelector{
+				LocalObjectReference: v13.LocalObjectReference{
+					Name: getImageRendererSecretName(cr),
+				},
+				Key: ImageRendererTokenKey,
+			},
+		},
+	}
+}
+
+func getImageRendererContainers(cr *v1alpha1.Grafana) []v13.Container {
+	return []v13.Container{
+		{
+			Name:  ImageRendererPodLabel,
+			Image: getImageRendererImage(cr),
+			Ports: []v13.ContainerPort{
+				{
+					Name:          ImageRendererPortName,
+					ContainerPort: int32(ImageRendererPort),
+					Protocol:      "TCP",
+				},
+			},
+			Env: []v13.EnvVar{
+				{
+					Name:  "HTTP_PORT",
+					Value: fmt.Sprintf("%d", ImageRendererPort),
+				},
+				getImageRendererTokenEnvVar(cr, ImageRendererAuthTokenEnvVar),
+			},
+			Resources: getImageRendererResources(cr),
+			ReadinessProbe: &v13.Probe{
+				Handler: v13.Handler{
+					TCPSocket: &v13.TCPSocketAction{
+						Port: intstr.FromInt(ImageRendererPort),
+					},
+				},
+				InitialDelaySeconds: 5,
+				TimeoutSeconds:      3,
+			},
+			TerminationMessagePath:   "/dev/termination-log",
+			TerminationMessagePolicy: "File",
+			ImagePullPolicy:          "IfNotPresent",
+		},
+	}
+}
+
+func getImageRendererDeploymentSpec(cr *v1alpha1.Grafana) v1.DeploymentSpec {
+	return v1.DeploymentSpec{
+		Replicas: getImageRendererReplicas(cr),
+		Selector: &v12.LabelSelector{
+			MatchLabels: getImageRendererPodLabels(cr),
+		},
+		Template: v13.PodTemplateSpec{
+			ObjectMeta: v12.ObjectMeta{
+				Name:   getImageRendererDeploymentName(cr),
+				Labels: getImageRendererPodLabels(cr),
+			},
+			Spec: v13.PodSpec{
+				Containers: getImageRendererContainers(cr),
+			},
+		},
+	}
+}
+
+func ImageRendererDeployment(cr *v1alpha1.Grafana) *v1.Deployment {
+	return &v1.Deployment{
+		ObjectMeta: v12.ObjectMeta{
+			Name:        getImageRendererDeploymentName(cr),
+			Namespace:   cr.Namespace,
+			Labels:      getImageRendererLabels(cr),
+			Annotations: getImageRendererAnnotations(cr, nil),
+		},
+		Spec: getImageRendererDeploymentSpec(cr),
+	}
+}
+
+func ImageRendererDeploymentReconciled(cr *v1alpha1.Grafana, currentState *v1.Deployment) *v1.Deployment {
+	reconciled := currentState.DeepCopy()
+	reconciled.Labels = getImageRendererLabels(cr)
+	reconciled.Annotations = getImageRendererAnnotations(cr, currentState.Annotations)
+	reconciled.Spec = getImageRendererDeploymentSpec(cr)
+	return reconciled
+}
+
+func ImageRendererDeploymentSelector(cr *v1alpha1.Grafana) client.ObjectKey {
+	return client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      getImageRendererDeploymentName(cr),
+	}
+}
+
+func getImageRendererServiceSpec(cr *v1alpha1.Grafana, currentState *v13.Service) v13.ServiceSpec {
+	spec := v13.ServiceSpec{
+		Type:     v13.ServiceTypeClusterIP,
+		Selector: getImageRendererPodLabels(cr),
+		Ports: []v13.ServicePort{
+			{
+				Name:       ImageRendererPortName,
+				Protocol:   "TCP",
+				Port:       int32(ImageRendererPort),
+				TargetPort: intstr.FromString(ImageRendererPortName),
+			},
+		},
+	}
+
+	// The cluster ip is immutable
+	if currentState != nil {
+		spec.ClusterIP = currentState.Spec.ClusterIP
+	}
+	return spec
+}
+
+func ImageRendererService(cr *v1alpha1.Grafana) *v13.Service {
+	return &v13.Service{
+		ObjectMeta: v12.ObjectMeta{
+			Name:        getImageRendererServiceName(cr),
+			Namespace:   cr.Namespace,
+			Labels:      getImageRendererLabels(cr),
+			Annotations: getImageRendererAnnotations(cr, nil),
+		},
+		Spec: getImageRendererServiceSpec(cr, nil),
+	}
+}
+
+func ImageRendererServiceReconciled(cr *v1alpha1.Grafana, currentState *v13.Service) *v13.Service {
+	reconciled := currentState.DeepCopy()
+	reconciled.Labels = getImageRendererLabels(cr)
+	reconciled.Annotations = getImageRendererAnnotations(cr, currentState.Annotations)
+	reconciled.Spec = getImageRendererServiceSpec(cr, currentState)
+	return reconciled
+}
+
+func ImageRendererServiceSelector(cr *v1alpha1.Grafana) client.ObjectKey {
+	return client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      getImageRendererServiceName(cr),
+	}
+}
+
+func getImageRendererToken(current *v13.Secret) []byte {
+	// If a token is already set, don't change it
+	if current != nil && current.Data[ImageRendererTokenKey] != nil {
+		return current.Data[ImageRendererTokenKey]
+	}
+	return []byte(RandStringRunes(32))
+}
+
+func ImageRendererSecret(cr *v1alpha1.Grafana) *v13.Secret {
+	return &v13.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      getImageRendererSecretName(cr),
+			Namespace: cr.Namespace,
+		},
+		Data: map[string][]byte{
+			ImageRendererTokenKey: getImageRendererToken(nil),
+		},
+		Type: v13.SecretTypeOpaque,
+	}
+}
+
+func ImageRendererSecretReconciled(cr *v1alpha1.Grafana, currentState *v13.Secret) *v13.Secret {
+	reconciled := currentState.DeepCopy()
+	reconciled.Data = map[string][]byte{
+		ImageRendererTokenKey: getImageRendererToken(currentState),
+	}
+	return reconciled
+}
+
+func ImageRendererSecretSelector(cr *v1alpha1.Grafana) client.ObjectKey {
+	return client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      getImageRendererSecretName(cr),
+	}
+}
+
+func getImageRendererDeploymentName(cr *v1alpha1.Grafana) string {
+	return fmt.Sprintf("%s-%s", imageRendererDeploymentName, cr.Name)
+}
+
+func getImageRendererServiceName(cr *v1alpha1.Grafana) string {
+	return fmt.Sprintf("%s-%s", imageRendererServiceName, cr.Name)
+}
+
+func getImageRendererSecretName(cr *v1alpha1.Grafana) string {
+	return fmt.Sprintf("%s-%s", imageRendererSecretName, cr.Name)
+}
@@ -0,0 +1,159 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	v13 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newImageRendererGrafana() *v1alpha1.Grafana {
+	return &v1alpha1.Grafana{
+		ObjectMeta: v12.ObjectMeta{Namespace: "monitoring", Name: "grafana"},
+		Spec: v1alpha1.GrafanaSpec{
+			ImageRenderer: &v1alpha1.GrafanaImageRenderer{
+				Enabled: true,
+				Labels:  map[string]string{"app": "custom", "team": "a"},
+			},
+		},
+	}
+}
+
+// Returns the secret key reference of the environment variable
+func envSecretKeyRef(env []v13.EnvVar, name string) *v13.SecretKeySelector {
+	if e := findEnv(env, name); e != nil && e.ValueFrom != nil {
+		return e.ValueFrom.SecretKeyRef
+	}
+	return nil
+}
+
+func TestImageRendererDeployment(t *testing.T) {
+	cr := newImageRendererGrafana()
+	deployment := ImageRendererDeployment(cr)
+
+	if deployment.Name != "grafana-image-renderer-deployment-grafana" || deployment.Namespace != "monitoring" {
+		t.Errorf("unexpected deployment %v/%v", deployment.Namespace, deployment.Name)
+	}
+	if *deployment.Spec.Replicas != 1 {
+		t.Errorf("expected one replica by default, got %v", *deployment.Spec.Replicas)
+	}
+
+	// User provided labels don't override the selector labels
+	if deployment.Labels["app"] != ImageRendererPodLabel || deployment.Labels["team"] != "a" {
+		t.Errorf("unexpected deployment labels %v", deployment.Labels)
+	}
+	if len(deployment.Spec.Selector.MatchLabels) != 2 || deployment.Spec.Template.Labels["team"] != "" {
+		t.Errorf("expected only the pod labels in the selector, got %v", deployment.Spec.Selector.MatchLabels)
+	}
+
+	containers := deployment.Spec.Template.Spec.Containers
+	if len(containers) != 1 {
+		t.Fatalf("expected one container, got %v", len(containers))
+	}
+	if containers[0].Image != ImageRendererImage+":"+ImageRendererVersion {
+		t.Errorf("unexpected default image %v", containers[0].Image)
+	}
+
+	ref := envSecretKeyRef(containers[0].Env, ImageRendererAuthTokenEnvVar)
+	if ref == nil || ref.Name != "grafana-image-renderer-token-grafana" || ref.Key != ImageRendererTokenKey {
+		t.Errorf("expected the auth token from the token secret, got %+v", ref)
+	}
+
+	cr.Spec.ImageRenderer.Image = "grafana/grafana-image-renderer:3.0.0"
+	cr.Spec.ImageRenderer.Replicas = 2
+	deployment = ImageRendererDeploymentReconciled(cr, deployment)
+	if deployment.Spec.Template.Spec.Containers[0].Image != "grafana/grafana-image-renderer:3.0.0" || *deployment.Spec.Replicas != 2 {
+		t.Errorf("expected the image and replicas of the spec, got %v and %v", deployment.Spec.Template.Spec.Containers[0].Image, *deployment.Spec.Replicas)
+	}
+}
+
+func TestImageRendererService(t *testing.T) {
+	cr := newImageRendererGrafana()
+	service := ImageRendererService(cr)
+
+	if service.Name != "grafana-image-renderer-service-grafana" {
+		t.Errorf("unexpected service name %v", service.Name)
+	}
+	if len(service.Spec.Ports) != 1 || service.Spec.Ports[0].Port != int32(ImageRendererPort) {
+		t.Errorf("expected the renderer port, got %+v", service.Spec.Ports)
+	}
+	if service.Spec.Selector["app"] != ImageRendererPodLabel || service.Spec.Selector["grafana"] != "grafana" {
+		t.Errorf("unexpected service selector %v", service.Spec.Selector)
+	}
+
+	// The cluster ip is kept
+	service.Spec.ClusterIP = "10.0.0.1"
+	if reconciled := ImageRendererServiceReconciled(cr, service); reconciled.Spec.ClusterIP != "10.0.0.1" {
+		t.Errorf("expected the cluster ip to be kept, got %v", reconciled.Spec.ClusterIP)
+	}
+}
+
+func TestImageRendererSecret(t *testing.T) {
+	cr := newImageRendererGrafana()
+	secret := ImageRendererSecret(cr)
+
+	token := secret.Data[ImageRendererTokenKey]
+	if len(token) == 0 {
+		t.Fatalf("expected a generated token, got %q", token)
+	}
+	if other := ImageRendererSecret(cr).Data[ImageRendererTokenKey]; string(other) == string(token) {
+		t.Error("expected a new token for every secret")
+	}
+
+	// Existing tokens are kept, missing ones are generated
+	if reconciled := ImageRendererSecretReconciled(cr, secret); string(reconciled.Data[ImageRendererTokenKey]) != string(token) {
+		t.Errorf("expected the token to be kept, got %q", reconciled.Data[ImageRendererTokenKey])
+	}
+	secret.Data = nil
+	if reconciled := ImageRendererSecretReconciled(cr, secret); len(reconciled.Data[ImageRendererTokenKey]) == 0 {
+		t.Errorf("expected a generated token, got %q", reconciled.Data[ImageRendererTokenKey])
+	}
+}
+
+func TestImageRendererConfig(t *testing.T) {
+	cr := newImageRendererGrafana()
+	cr.Spec.Config.Server = &v1alpha1.GrafanaConfigServer{Protocol: "https", HttpPort: "3443"}
+
+	cfg := getGrafanaConfig(cr)
+	if cfg.Rendering == nil {
+		t.Fatal("expected the rendering section")
+	}
+	if expected := "http://grafana-image-renderer-service-grafana.monitoring.svc:8081/render"; cfg.Rendering.ServerUrl != expected {
+		t.Errorf("expected server url %v, got %v", expected, cfg.Rendering.ServerUrl)
+	}
+	if expected := "https://grafana-service-grafana.monitoring.svc:3443/"; cfg.Rendering.CallbackUrl != expected {
+		t.Errorf("expected callback url %v, got %v", expected, cfg.Rendering.CallbackUrl)
+	}
+	if cr.Spec.Config.Rendering != nil {
+		t.Error("expected the spec to be unchanged")
+	}
+
+	// Provided urls take precedence
+	cr.Spec.Config.Rendering = &v1alpha1.GrafanaConfigRendering{ServerUrl: "http://renderer:8081/render"}
+	cfg = getGrafanaConfig(cr)
+	if cfg.Rendering.ServerUrl != "http://renderer:8081/render" || !strings.HasPrefix(cfg.Rendering.CallbackUrl, "https://") {
+		t.Errorf("unexpected rendering config %+v", cfg.Rendering)
+	}
+
+	cr.Spec.ImageRenderer.Enabled = false
+	cr.Spec.Config.Rendering = nil
+	if cfg := getGrafanaConfig(cr); cfg.Rendering != nil {
+		t.Errorf("expected no rendering section without the renderer, got %+v", cfg.Rendering)
+	}
+}
+
+func TestImageRendererGrafanaEnv(t *testing.T) {
+	cr := newImageRendererGrafana()
+
+	ref := envSecretKeyRef(getEnv(cr, "config", "datasources", ""), ImageRendererTokenKey)
+	if ref == nil || ref.Name != "grafana-image-renderer-token-grafana" || ref.Key != ImageRendererTokenKey {
+		t.Errorf("expected the renderer token from the token secret, got %+v", ref)
+	}
+
+	cr.Spec.ImageRenderer.Enabled = false
+	if env := findEnv(getEnv(cr, "config", "datasources", ""), ImageRendererTokenKey); env != nil {
+		t.Errorf("expected no renderer token without the renderer, got %+v", env)
+	}
+}
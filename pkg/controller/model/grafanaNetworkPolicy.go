@@ -0,0 +1,259 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func getNetworkPolicyLabels(cr *v1alpha1.Grafana) map[string]string {
+	if cr.Spec.NetworkPolicy == nil {
+		return nil
+	}
+	return cr.Spec.NetworkPolicy.Labels
+}
+
+func getNetworkPolicyAnnotations(cr *v1alpha1.Grafana, existing map[string]string) map[string]string {
+	if cr.Spec.NetworkPolicy == nil {
+		return existing
+	}
+	return MergeAnnotations(cr.Spec.NetworkPolicy.Annotations, existing)
+}
+
+// Only select the grafana pods by the labels set by the operator, extra deployment
+// labels may change at any time
+func getNetworkPolicyPodSelector(cr *v1alpha1.Grafana) v12.LabelSelector {
+	return v12.LabelSelector{
+		MatchLabels: map[string]string{
+			"app":     GrafanaPodLabel,
+			"grafana": cr.Name,
+		},
+	}
+}
+
+// Ports exposed by the grafana service, including extra ports of sidecar containers
+func getNetworkPolicyIngressPorts(cr *v1alpha1.Grafana) []networkingv1.NetworkPolicyPort {
+	protocol := v1.ProtocolTCP
+	grafanaPort := intstr.FromInt(GetGrafanaPort(cr))
+
+	ports := []networkingv1.NetworkPolicyPort{
+		{
+			Protocol: &protocol,
+			Port:     &grafanaPort,
+		},
+	}
+
+	if cr.Spec.Service == nil {
+		return ports
+	}
+
+	for _, servicePort := range cr.Spec.Service.Ports {
+		port := servicePort.TargetPort
+		if port.IntVal == 0 && port.StrVal == "" {
+			port = intstr.FromInt(int(servicePort.Port))
+		}
+		portProtocol := servicePort.Protocol
+		if portProtocol == "" {
+			portProtocol = v1.ProtocolTCP
+		}
+		ports = append(ports, networkingv1.NetworkPolicyPort{
+			Protocol: &portProtocol,
+			Port:     &port,
+		})
+	}
+
+	return ports
+}
+
+// The ingress controller peers default to the router namespaces on OpenShift. On
+// vanilla Kubernetes the location of the ingress controller has to be provided
+func getNetworkPolicyIngressControllerPeers(cr *v1alpha1.Grafana) []networkingv1.NetworkPolicyPeer {
+	if len(cr.Spec.NetworkPolicy.IngressController) > 0 {
+		return cr.Spec.NetworkPolicy.IngressController
+	}
+
+	cfg := config.GetControllerConfig()
+	if cfg.GetConfigBool(config.ConfigOpenshift, false) {
+		return []networkingv1.NetworkPolicyPeer{
+			{
+				NamespaceSelector: &v12.LabelSelector{
+					MatchLabels: map[string]string{
+						OpenshiftIngressPolicyGroupLabel: "ingress",
+					},
+				},
+			},
+		}
+	}
+
+	return nil
+}
+
+// The operator needs access to the Grafana API to import dashboards and datasources.
+// The operator namespace is selected by the immutable kubernetes.io/metadata.name
+// label, clusters before Kubernetes 1.21 need it set manually
+func getNetworkPolicyOperatorPeers() []networkingv1.NetworkPolicyPeer {
+	cfg := config.GetControllerConfig()
+	namespace := cfg.GetConfigString(config.ConfigOperatorNamespace, "")
+	if namespace == "" {
+		return nil
+	}
+
+	return []networkingv1.NetworkPolicyPeer{
+		{
+			NamespaceSelector: &v12.LabelSelector{
+				MatchLabels: map[string]string{
+					NamespaceNameLabel: namespace,
+				},
+			},
+		},
+	}
+}
+
+func getNetworkPolicyImageRendererPeers(cr *v1alpha1.Grafana) []networkingv1.NetworkPolicyPeer {
+	return []networkingv1.NetworkPolicyPeer{
+		{
+			PodSelector: &v12.LabelSelector{
+				MatchLabels: getImageRendererPodLabels(cr),
+			},
+		},
+	}
+}
+
+func getNetworkPolicyIngressRules(cr *v1alpha1.Grafana) []networkingv1.NetworkPolicyIngressRule {
+	var peers []networkingv1.NetworkPolicyPeer
+	peers = append(peers, getNetworkPolicyIngressControllerPeers(cr)...)
+	peers = append(peers, getNetworkPolicyOperatorPeers()...)
+	peers = append(peers, cr.Spec.NetworkPolicy.IngressPeers...)
+
+	// The image renderer loads the panels through the callback url
+	if ImageRendererEnabled(cr) {
+		peers = append(peers, getNetworkPolicyImageRendererPeers(cr)...)
+	}
+
+	// Without any peers the policy denies all incoming traffic
+	if len(peers) == 0 {
+		return nil
+	}
+
+	return []networkingv1.NetworkPolicyIngressRule{
+		{
+			Ports: getNetworkPolicyIngressPorts(cr),
+			From:  peers,
+		},
+	}
+}
+
+func getNetworkPolicyEgressRules(cr *v1alpha1.Grafana) []networkingv1.NetworkPolicyEgressRule {
+	tcp := v1.ProtocolTCP
+	udp := v1.ProtocolUDP
+	dnsPort := intstr.FromInt(NetworkPolicyDnsPort)
+	httpsPort := intstr.FromInt(NetworkPolicyHttpsPort)
+
+	// Name resolution is always required once egress is restricted
+	rules := []networkingv1.NetworkPolicyEgressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{
+					Protocol: &udp,
+					Port:     &dnsPort,
+				},
+				{
+					Protocol: &tcp,
+					Port:     &dnsPort,
+				},
+			},
+		},
+	}
+
+	if len(cr.Spec.NetworkPolicy.DatasourcePeers) > 0 {
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+			To: cr.Spec.NetworkPolicy.DatasourcePeers,
+		})
+	}
+
+	if ImageRendererEnabled(cr) {
+		rendererPort := intstr.FromInt(ImageRendererPort)
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{
+					Protocol: &tcp,
+					Port:     &rendererPort,
+				},
+			},
+			To: getNetworkPolicyImageRendererPeers(cr),
+		})
+	}
+
+	// Plugins are downloaded by the init container over https
+	if len(cr.Spec.NetworkPolicy.PluginDownloadPeers) > 0 {
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{
+					Protocol: &tcp,
+					Port:     &httpsPort,
+				},
+			},
+			To: cr.Spec.NetworkPolicy.PluginDownloadPeers,
+		})
+	}
+
+	return rules
+}
+
+// Egress is only restricted when at least one egress peer is configured
+func restrictNetworkPolicyEgress(cr *v1alpha1.Grafana) bool {
+	return len(cr.Spec.NetworkPolicy.DatasourcePeers) > 0 ||
+		len(cr.Spec.NetworkPolicy.PluginDownloadPeers) > 0
+}
+
+func getNetworkPolicySpec(cr *v1alpha1.Grafana) networkingv1.NetworkPolicySpec {
+	spec := networkingv1.NetworkPolicySpec{
+		PodSelector: getNetworkPolicyPodSelector(cr),
+		Ingress:     getNetworkPolicyIngressRules(cr),
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+	}
+
+	if restrictNetworkPolicyEgress(cr) {
+		spec.Egress = getNetworkPolicyEgressRules(cr)
+		spec.PolicyTypes = append(spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+	}
+
+	return spec
+}
+
+func GrafanaNetworkPolicy(cr *v1alpha1.Grafana) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: v12.ObjectMeta{
+			Name:        getGrafanaNetworkPolicyName(cr),
+			Namespace:   cr.Namespace,
+			Labels:      getNetworkPolicyLabels(cr),
+			Annotations: getNetworkPolicyAnnotations(cr, nil),
+		},
+		Spec: getNetworkPolicySpec(cr),
+	}
+}
+
+func GrafanaNetworkPolicyReconciled(cr *v1alpha1.Grafana, currentState *networkingv1.NetworkPolicy) *networkingv1.NetworkPolicy {
+	reconciled := currentState.DeepCopy()
+	reconciled.Labels = getNetworkPolicyLabels(cr)
+	reconciled.Annotations = getNetworkPolicyAnnotations(cr, currentState.Annotations)
+	reconciled.Spec = getNetworkPolicySpec(cr)
+	return reconciled
+}
+
+func GrafanaNetworkPolicySelector(cr *v1alpha1.Grafana) client.ObjectKey {
+	return client.ObjectKey{
+		Namespace: cr.Namespace,
+		Name:      getGrafanaNetworkPolicyName(cr),
+	}
+}
+
+func getGrafanaNetworkPolicyName(cr *v1alpha1.Grafana) string {
+	return fmt.Sprintf("%s-%s", grafanaNetworkPolicyName, cr.Name)
+}
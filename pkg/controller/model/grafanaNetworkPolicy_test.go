@@ -0,0 +1,88 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ucloud/grafana-operator/pkg/apis/monitor/v1alpha1"
+	"github.com/ucloud/grafana-operator/pkg/controller/config"
+	networkingv1 "k8s.io/api/networking/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newNetworkPolicyGrafana(policy *v1alpha1.GrafanaNetworkPolicy) *v1alpha1.Grafana {
+	return &v1alpha1.Grafana{
+		ObjectMeta: v12.ObjectMeta{Namespace: "monitoring", Name: "grafana"},
+		Spec:       v1alpha1.GrafanaSpec{NetworkPolicy: policy},
+	}
+}
+
+func TestNetworkPolicyOperatorPeers(t *testing.T) {
+	cfg := config.GetControllerConfig()
+	cfg.RemoveConfigItem(config.ConfigOperatorNamespace)
+	if peers := getNetworkPolicyOperatorPeers(); peers != nil {
+		t.Errorf("expected no peers without operator namespace, got %+v", peers)
+	}
+
+	cfg.AddConfigItem(config.ConfigOperatorNamespace, "grafana-operator")
+	defer cfg.RemoveConfigItem(config.ConfigOperatorNamespace)
+
+	expected := []networkingv1.NetworkPolicyPeer{
+		{
+			NamespaceSelector: &v12.LabelSelector{
+				MatchLabels: map[string]string{NamespaceNameLabel: "grafana-operator"},
+			},
+		},
+	}
+	if peers := getNetworkPolicyOperatorPeers(); !reflect.DeepEqual(peers, expected) {
+		t.Errorf("expected peers %+v, got %+v", expected, peers)
+	}
+}
+
+func TestNetworkPolicySpec(t *testing.T) {
+	cfg := config.GetControllerConfig()
+	cfg.RemoveConfigItem(config.ConfigOperatorNamespace)
+
+	// No peers at all deny all ingress and leave egress unrestricted
+	spec := getNetworkPolicySpec(newNetworkPolicyGrafana(&v1alpha1.GrafanaNetworkPolicy{Enabled: true}))
+	if spec.Ingress != nil {
+		t.Errorf("expected no ingress rules, got %+v", spec.Ingress)
+	}
+	if !reflect.DeepEqual(spec.PolicyTypes, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}) {
+		t.Errorf("expected only an ingress policy, got %v", spec.PolicyTypes)
+	}
+
+	cfg.AddConfigItem(config.ConfigOperatorNamespace, "grafana-operator")
+	defer cfg.RemoveConfigItem(config.ConfigOperatorNamespace)
+
+	prometheus := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &v12.LabelSelector{MatchLabels: map[string]string{"name": "monitoring"}},
+	}
+	datasources := networkingv1.NetworkPolicyPeer{
+		IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/16"},
+	}
+	cr := newNetworkPolicyGrafana(&v1alpha1.GrafanaNetworkPolicy{
+		Enabled:         true,
+		IngressPeers:    []networkingv1.NetworkPolicyPeer{prometheus},
+		DatasourcePeers: []networkingv1.NetworkPolicyPeer{datasources},
+	})
+	spec = getNetworkPolicySpec(cr)
+
+	if len(spec.Ingress) != 1 {
+		t.Fatalf("expected one ingress rule, got %+v", spec.Ingress)
+	}
+	if len(spec.Ingress[0].From) != 2 || !reflect.DeepEqual(spec.Ingress[0].From[1], prometheus) {
+		t.Errorf("expected the operator and the ingress peers, got %+v", spec.Ingress[0].From)
+	}
+	if len(spec.Ingress[0].Ports) != 1 || spec.Ingress[0].Ports[0].Port.IntValue() != GrafanaHttpPort {
+		t.Errorf("expected ingress on the grafana port, got %+v", spec.Ingress[0].Ports)
+	}
+
+	// DNS and the datasources
+	if len(spec.Egress) != 2 || !reflect.DeepEqual(spec.Egress[1].To, []networkingv1.NetworkPolicyPeer{datasources}) {
+		t.Errorf("expected egress to dns and the datasources, got %+v", spec.Egress)
+	}
+	if len(spec.PolicyTypes) != 2 {
+		t.Errorf("expected an ingress and egress policy, got %v", spec.PolicyTypes)
+	}
+}